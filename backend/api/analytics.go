@@ -0,0 +1,143 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAnalyticsRange keeps a single analytics query from scanning years of data
+const maxAnalyticsRange = 366 * 24 * time.Hour
+
+type analyticsRequest struct {
+	From       string `form:"from" binding:"required"`
+	To         string `form:"to" binding:"required"`
+	HostID     int64  `form:"host_id" binding:"omitempty,min=1"`
+	Department string `form:"department"`
+}
+
+// bindAnalyticsRequest parses the shared date range and filters of every
+// analytics endpoint. It writes the error response itself and returns false
+// when the request is invalid.
+func bindAnalyticsRequest(ctx *gin.Context) (db.GetDailyVisitVolumesParams, bool) {
+	var req analyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return db.GetDailyVisitVolumesParams{}, false
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid from date format, use YYYY-MM-DD")))
+		return db.GetDailyVisitVolumesParams{}, false
+	}
+
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid to date format, use YYYY-MM-DD")))
+		return db.GetDailyVisitVolumesParams{}, false
+	}
+
+	if to.Before(from) || to.Sub(from) > maxAnalyticsRange {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("date range must be between 1 and 366 days")))
+		return db.GetDailyVisitVolumesParams{}, false
+	}
+
+	return db.GetDailyVisitVolumesParams{
+		FromDate:   from,
+		ToDate:     to,
+		HostID:     sql.NullInt32{Int32: int32(req.HostID), Valid: req.HostID != 0},
+		Department: sql.NullString{String: req.Department, Valid: req.Department != ""},
+	}, true
+}
+
+func (server *Server) getVisitVolumes(ctx *gin.Context) {
+	arg, ok := bindAnalyticsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	volumes, err := server.store.GetDailyVisitVolumes(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, volumes)
+}
+
+func (server *Server) getVisitHeatmap(ctx *gin.Context) {
+	arg, ok := bindAnalyticsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	cells, err := server.store.GetVisitHeatmap(ctx, db.GetVisitHeatmapParams(arg))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// heatmap[weekday-1][hour], Monday first, so empty cells read as zero
+	var heatmap [7][24]int32
+	for _, cell := range cells {
+		heatmap[cell.Weekday-1][cell.Hour] = cell.Visits
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"heatmap": heatmap})
+}
+
+type visitRatesResponse struct {
+	db.GetVisitOutcomeCountsRow
+	CancellationRate float64 `json:"cancellation_rate"`
+	NoShowRate       float64 `json:"no_show_rate"`
+}
+
+func (server *Server) getVisitRates(ctx *gin.Context) {
+	arg, ok := bindAnalyticsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	counts, err := server.store.GetVisitOutcomeCounts(ctx, db.GetVisitOutcomeCountsParams(arg))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := visitRatesResponse{
+		GetVisitOutcomeCountsRow: counts,
+		CancellationRate:         ratio(counts.Cancelled, counts.Total),
+		NoShowRate:               ratio(counts.NoShow, counts.Total),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) getVisitDurations(ctx *gin.Context) {
+	arg, ok := bindAnalyticsRequest(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := server.store.GetVisitDurationStats(ctx, db.GetVisitDurationStatsParams(arg))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// ratio returns part/total rounded to four decimal places, or 0 for an empty total
+func ratio(part, total int32) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
@@ -2,11 +2,15 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 // CreateAppointmentLogRequest struct to bind request for creating appointment log
@@ -14,25 +18,40 @@ type createAppointmentLogRequest struct {
 	AppointmentID int       `json:"appointment_id" binding:"required"`
 	CheckInTime   time.Time `json:"check_in_time"`
 	CheckOutTime  time.Time `json:"check_out_time"`
+	Gate          string    `json:"gate" binding:"max=50"`
 }
 
-// Function to create an appointment log
+// Function to create an appointment log. The given times are recorded as
+// access events and the returned log is the resulting visit summary.
 func (server *Server) createAppointmentLog(ctx *gin.Context) {
 	var req createAppointmentLogRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	arg := db.CreateAppointmentLogParams{
-		AppointmentID: int32(req.AppointmentID),
-		CheckInTime:   sql.NullTime{Time: req.CheckInTime, Valid: !req.CheckInTime.IsZero()},
-		CheckOutTime:  sql.NullTime{Time: req.CheckOutTime, Valid: !req.CheckOutTime.IsZero()},
+	var log db.AppointmentLog
+	for _, scan := range []struct {
+		direction string
+		at        time.Time
+	}{
+		{"in", req.CheckInTime},
+		{"out", req.CheckOutTime},
+	} {
+		if scan.at.IsZero() {
+			continue
+		}
+
+		result, err := server.recordAccessEvent(ctx, accessEventParams(req.AppointmentID, scan.direction, scan.at, req.Gate, int64(authPayload(ctx).UserID)))
+		if err != nil {
+			accessEventError(ctx, err)
+			return
+		}
+		log = result.Log
 	}
 
-	log, err := server.store.CreateAppointmentLog(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if log.ID == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("check_in_time or check_out_time is required")))
 		return
 	}
 
@@ -48,17 +67,17 @@ type getAppointmentLogRequest struct {
 func (server *Server) getAppointmentLogByAppointmentID(ctx *gin.Context) {
 	var req getAppointmentLogRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	log, err := server.store.GetAppointmentLogByAppointmentID(ctx, int32(req.AppointmentID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -69,52 +88,49 @@ func (server *Server) getAppointmentLogByAppointmentID(ctx *gin.Context) {
 type updateCheckInTimeRequest struct {
 	AppointmentID int       `json:"appointment_id" binding:"required"`
 	CheckInTime   time.Time `json:"check_in_time" binding:"required"`
+	Gate          string    `json:"gate" binding:"max=50"`
 }
 
-// Function to update the check-in time for a specific appointment log
+// Function to record a check-in for a specific appointment. Repeated
+// check-ins are kept as separate entries rather than overwriting the first.
 func (server *Server) updateCheckInTime(ctx *gin.Context) {
 	var req updateCheckInTimeRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	log, err := server.store.UpdateCheckInTime(ctx, db.UpdateCheckInTimeParams{
-		AppointmentID: int32(req.AppointmentID),
-		CheckInTime:   sql.NullTime{Time: req.CheckInTime, Valid: !req.CheckInTime.IsZero()},
-	})
+	result, err := server.recordAccessEvent(ctx, accessEventParams(req.AppointmentID, "in", req.CheckInTime, req.Gate, int64(authPayload(ctx).UserID)))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		accessEventError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, log)
+	ctx.JSON(http.StatusOK, result.Log)
 }
 
 // UpdateCheckOutTimeRequest struct to bind request for updating check-out time
 type updateCheckOutTimeRequest struct {
 	AppointmentID int       `json:"appointment_id" binding:"required"`
 	CheckOutTime  time.Time `json:"check_out_time" binding:"required"`
+	Gate          string    `json:"gate" binding:"max=50"`
 }
 
-// Function to update the check-out time for a specific appointment log
+// Function to record a check-out for a specific appointment
 func (server *Server) updateCheckOutTime(ctx *gin.Context) {
 	var req updateCheckOutTimeRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	log, err := server.store.UpdateCheckOutTime(ctx, db.UpdateCheckOutTimeParams{
-		AppointmentID: int32(req.AppointmentID),
-		CheckOutTime:  sql.NullTime{Time: req.CheckOutTime, Valid: !req.CheckOutTime.IsZero()},
-	})
+	result, err := server.recordAccessEvent(ctx, accessEventParams(req.AppointmentID, "out", req.CheckOutTime, req.Gate, int64(authPayload(ctx).UserID)))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		accessEventError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, log)
+	ctx.JSON(http.StatusOK, result.Log)
 }
 
 // DeleteAppointmentLogRequest struct to bind request for deleting appointment log
@@ -122,19 +138,147 @@ type deleteAppointmentLogRequest struct {
 	AppointmentID int `json:"appointment_id" binding:"required"`
 }
 
-// Function to delete an appointment log by appointment ID
+// Function to delete an appointment log and its access events by appointment ID
 func (server *Server) deleteAppointmentLog(ctx *gin.Context) {
 	var req deleteAppointmentLogRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	err := server.store.DeleteAppointmentLog(ctx, int32(req.AppointmentID))
+	err := server.store.DeleteVisitLogTx(ctx, int32(req.AppointmentID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Appointment log deleted"})
 }
+
+// CreateAccessEventRequest struct to bind request for recording a single gate scan
+type createAccessEventRequest struct {
+	AppointmentID int       `json:"appointment_id" binding:"required"`
+	Direction     string    `json:"direction" binding:"required,oneof=in out"`
+	ScannedAt     time.Time `json:"scanned_at"` // Optional — defaults to now
+	Gate          string    `json:"gate" binding:"max=50"`
+}
+
+// Function to record a gate scan and return it with the updated visit summary
+func (server *Server) createAccessEvent(ctx *gin.Context) {
+	var req createAccessEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.ScannedAt.IsZero() {
+		req.ScannedAt = time.Now()
+	}
+
+	// The scan is recorded against whoever is signed in at the gate
+	scannedBy := int64(authPayload(ctx).UserID)
+
+	result, err := server.recordAccessEvent(ctx, accessEventParams(req.AppointmentID, req.Direction, req.ScannedAt, req.Gate, scannedBy))
+	if err != nil {
+		accessEventError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Function to list every gate scan for an appointment in order
+func (server *Server) listAccessEvents(ctx *gin.Context) {
+	var req getAppointmentLogRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	events, err := server.store.ListAccessEventsByAppointment(ctx, int32(req.AppointmentID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// recordAccessEvent records a gate scan, queueing the host's arrival notice
+// with it in case this turns out to be the visit's first entry.
+func (server *Server) recordAccessEvent(ctx *gin.Context, arg db.CreateAccessEventParams) (db.RecordAccessEventTxResult, error) {
+	entries, err := server.checkInNotifications(ctx, arg)
+	if err != nil {
+		return db.RecordAccessEventTxResult{}, err
+	}
+
+	return server.store.RecordAccessEventTx(ctx, db.RecordAccessEventTxParams{
+		CreateAccessEventParams: arg,
+		Notifications:           entries,
+	})
+}
+
+// checkInNotifications builds the outbox entries for a check-in scan; other
+// scans notify nobody.
+func (server *Server) checkInNotifications(ctx *gin.Context, arg db.CreateAccessEventParams) ([]db.CreateNotificationParams, error) {
+	if arg.Direction != "in" {
+		return nil, nil
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, arg.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return server.visitorCheckedInNotifications(ctx, appointment, arg.ScannedAt, arg.Gate.String)
+}
+
+// visitorCheckedInNotifications builds the host's arrival notice, with a link
+// to the page where they can reply to the gate.
+func (server *Server) visitorCheckedInNotifications(ctx *gin.Context, appointment db.Appointment, at time.Time, gate string) ([]db.CreateNotificationParams, error) {
+	visitor, err := server.store.GetUserByID(ctx, appointment.VisitorID)
+	if err != nil {
+		return nil, err
+	}
+	host, err := server.store.GetUserByID(ctx, appointment.HostID)
+	if err != nil {
+		return nil, err
+	}
+
+	replyURL := fmt.Sprintf("%s/appointments/%d/reply", server.config.ClientURL, appointment.ID)
+	return server.outbox.Entries(notifications.VisitorCheckedIn(appointment, visitor, host, at, gate, replyURL)), nil
+}
+
+// Helper function to build the params for a gate scan
+func accessEventParams(appointmentID int, direction string, at time.Time, gate string, scannedBy int64) db.CreateAccessEventParams {
+	return db.CreateAccessEventParams{
+		AppointmentID: int32(appointmentID),
+		Direction:     direction,
+		ScannedAt:     at,
+		Gate:          sql.NullString{String: gate, Valid: gate != ""},
+		ScannedBy:     sql.NullInt32{Int32: int32(scannedBy), Valid: scannedBy != 0},
+	}
+}
+
+// accessEventError reports a failed scan, telling the guard when the
+// appointment does not exist or was cancelled, or the visitor still has to
+// complete their declarations.
+func accessEventError(ctx *gin.Context, err error) {
+	if errors.Is(err, db.ErrDeclarationsPending) {
+		ctx.JSON(http.StatusPreconditionFailed, errorResponse(ctx, err))
+		return
+	}
+	if errors.Is(err, db.ErrAppointmentCancelled) {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no appointment found with this ID")))
+		return
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no appointment found with this ID")))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+}
@@ -8,32 +8,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-type createAppointmentStatsRequest struct {
-	UserID            int64 `json:"user_id" binding:"required,min=1"`
-	TotalAppointments int32 `json:"total_appointments" binding:"required,min=0"`
-}
-
-func (server *Server) createAppointmentStats(ctx *gin.Context) {
-	var req createAppointmentStatsRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.CreateAppointmentStatsParams{
-		UserID:            int32(req.UserID),
-		TotalAppointments: sql.NullInt32{Int32: req.TotalAppointments, Valid: true},
-	}
-
-	appointmentStats, err := server.store.CreateAppointmentStats(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, appointmentStats)
-}
-
 type getAppointmentStatsByUserIDRequest struct {
 	UserID int64 `uri:"user_id" binding:"required,min=1"`
 }
@@ -41,81 +15,101 @@ type getAppointmentStatsByUserIDRequest struct {
 func (server *Server) getAppointmentStatsByUserID(ctx *gin.Context) {
 	var req getAppointmentStatsByUserIDRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	appointmentStats, err := server.store.GetAppointmentStatsByUserID(ctx, int32(req.UserID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, appointmentStats)
 }
 
-type incrementAppointmentCountRequest struct {
+type appointmentCountShimRequest struct {
 	UserID int64 `json:"user_id" binding:"required,min=1"`
 }
 
-func (server *Server) incrementAppointmentCount(ctx *gin.Context) {
-	var req incrementAppointmentCountRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
+// createAppointmentStats, incrementAppointmentCount, decrementAppointmentCount
+// and resetAppointmentCount are kept for old admin tooling only. Counters are
+// now derived from the appointments table, so instead of writing the stored
+// value these shims recompute it for the user and return the authoritative
+// row. Any total sent by the client is ignored.
+func (server *Server) createAppointmentStats(ctx *gin.Context) {
+	server.appointmentCountShim(ctx)
+}
 
-	appointmentStats, err := server.store.IncrementAppointmentCount(ctx, int32(req.UserID))
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
+func (server *Server) incrementAppointmentCount(ctx *gin.Context) {
+	server.appointmentCountShim(ctx)
+}
 
-	ctx.JSON(http.StatusOK, appointmentStats)
+func (server *Server) decrementAppointmentCount(ctx *gin.Context) {
+	server.appointmentCountShim(ctx)
 }
 
-type decrementAppointmentCountRequest struct {
-	UserID int64 `json:"user_id" binding:"required,min=1"`
+func (server *Server) resetAppointmentCount(ctx *gin.Context) {
+	server.appointmentCountShim(ctx)
 }
 
-func (server *Server) decrementAppointmentCount(ctx *gin.Context) {
-	var req decrementAppointmentCountRequest
+func (server *Server) appointmentCountShim(ctx *gin.Context) {
+	var req appointmentCountShimRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	appointmentStats, err := server.store.DecrementAppointmentCount(ctx, int32(req.UserID))
+	_, err := server.store.ReconcileAppointmentCountsTx(ctx, db.ReconcileAppointmentCountsTxParams{
+		UserID: sql.NullInt32{Int32: int32(req.UserID), Valid: true},
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	appointmentStats, err := server.store.GetAppointmentStatsByUserID(ctx, int32(req.UserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Deprecation", "true")
 	ctx.JSON(http.StatusOK, appointmentStats)
 }
 
-type resetAppointmentCountRequest struct {
-	UserID int64 `json:"user_id" binding:"required,min=1"`
+type reconcileAppointmentCountsRequest struct {
+	UserID int64 `json:"user_id" binding:"omitempty,min=1"`
+	DryRun bool  `json:"dry_run"`
 }
 
-func (server *Server) resetAppointmentCount(ctx *gin.Context) {
-	var req resetAppointmentCountRequest
+// reconcileAppointmentCounts recomputes the appointment counters from the
+// appointments table and reports which users had drifted.
+func (server *Server) reconcileAppointmentCounts(ctx *gin.Context) {
+	var req reconcileAppointmentCountsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	appointmentStats, err := server.store.ResetAppointmentCount(ctx, int32(req.UserID))
+	result, err := server.store.ReconcileAppointmentCountsTx(ctx, db.ReconcileAppointmentCountsTxParams{
+		UserID: sql.NullInt32{Int32: int32(req.UserID), Valid: req.UserID != 0},
+		DryRun: req.DryRun,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, appointmentStats)
+	ctx.JSON(http.StatusOK, result)
 }
 
 type deleteAppointmentStatsRequest struct {
@@ -125,13 +119,13 @@ type deleteAppointmentStatsRequest struct {
 func (server *Server) deleteAppointmentStats(ctx *gin.Context) {
 	var req deleteAppointmentStatsRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	err := server.store.DeleteAppointmentStats(ctx, int32(req.UserID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
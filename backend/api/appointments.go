@@ -2,11 +2,17 @@ package api
 
 import (
 	"database/sql"
+	"encoding/base64"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,14 +22,18 @@ type createAppointmentRequest struct {
 	AppointmentDate time.Time `json:"appointment_date" binding:"required"`
 	StartTime       time.Time `json:"start_time" binding:"required"`
 	EndTime         time.Time `json:"end_time" binding:"required"`
-	Status          *string   `json:"status"` // Optional — will default to 'pending'
 	QRCode          string    `json:"qr_code" binding:"required"`
+	Purpose         string    `json:"purpose" binding:"max=500"`
+	PurposeCategory string    `json:"purpose_category" binding:"omitempty,oneof=meeting interview delivery maintenance personal other"`
+	Location        string    `json:"location" binding:"max=100"`
+	HostNotes       string    `json:"host_notes" binding:"max=1000"`
+	VisitorNotes    string    `json:"visitor_notes" binding:"max=1000"`
 }
 
 func (server *Server) createAppointment(ctx *gin.Context) {
 	var req createAppointmentRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -35,15 +45,49 @@ func (server *Server) createAppointment(ctx *gin.Context) {
 		EndTime:         req.EndTime,
 		Status:          sql.NullString{String: "pending", Valid: true},
 		QrCode:          sql.NullString{String: req.QRCode, Valid: true},
+		Purpose:         sql.NullString{String: req.Purpose, Valid: req.Purpose != ""},
+		PurposeCategory: sql.NullString{String: "meeting", Valid: true},
+		Location:        sql.NullString{String: req.Location, Valid: req.Location != ""},
+		HostNotes:       sql.NullString{String: req.HostNotes, Valid: req.HostNotes != ""},
+		VisitorNotes:    sql.NullString{String: req.VisitorNotes, Valid: req.VisitorNotes != ""},
 	}
 
-	if req.Status != nil {
-		arg.Status = sql.NullString{String: *req.Status, Valid: true}
+	if req.PurposeCategory != "" {
+		arg.PurposeCategory = sql.NullString{String: req.PurposeCategory, Valid: true}
 	}
 
-	appointment, err := server.store.CreateAppointment(ctx, arg)
+	visitor, err := server.store.GetUserByID(ctx, arg.VisitorID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no visitor found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	host, err := server.store.GetUserByID(ctx, arg.HostID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no host found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.CreateAppointmentTx(ctx, db.CreateAppointmentTxParams{
+		CreateAppointmentParams: arg,
+		StartsAt:                ical.At(arg.AppointmentDate, arg.StartTime, server.location),
+		EndsAt:                  ical.At(arg.AppointmentDate, arg.EndTime, server.location),
+		Notifications:           server.outbox.Entries(notifications.AppointmentBooked(arg, visitor, host)...),
+	})
+	if err != nil {
+		if err == db.ErrHostBusy {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -57,20 +101,24 @@ type getAppointmentUriRequest struct {
 func (server *Server) getAppointmentByID(ctx *gin.Context) {
 	var req getAppointmentUriRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	appointment, err := server.store.GetAppointmentByID(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	if !canSeeHostNotes(optionalAuthPayload(ctx), appointment.HostID) {
+		appointment.HostNotes = sql.NullString{}
+	}
+
 	ctx.JSON(http.StatusOK, appointment)
 }
 
@@ -81,13 +129,13 @@ type listByIDUri struct {
 func (server *Server) listAppointmentsByVisitor(ctx *gin.Context) {
 	var req listByIDUri
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	appointments, err := server.store.ListAppointmentsByVisitor(ctx, int32(req.ID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -97,16 +145,22 @@ func (server *Server) listAppointmentsByVisitor(ctx *gin.Context) {
 func (server *Server) listAppointmentsByHost(ctx *gin.Context) {
 	var req listByIDUri
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	appointments, err := server.store.ListAppointmentsByHost(ctx, int32(req.ID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	if !canSeeHostNotes(optionalAuthPayload(ctx), int32(req.ID)) {
+		for i := range appointments {
+			appointments[i].HostNotes = sql.NullString{}
+		}
+	}
+
 	ctx.JSON(http.StatusOK, appointments)
 }
 
@@ -120,24 +174,31 @@ func (server *Server) listAppointmentsByDate(ctx *gin.Context) {
 	// Bind the query parameter as date
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		// If there's an error, respond with BadRequest
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Parse the date string to time.Time
 	parsedDate, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid date format, use YYYY-MM-DD")))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid date format, use YYYY-MM-DD")))
 		return
 	}
 
 	// Use the parsed time.Time object for the database query
 	appointments, err := server.store.ListAppointmentsByDate(ctx, parsedDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	payload := optionalAuthPayload(ctx)
+	for i := range appointments {
+		if !canSeeHostNotes(payload, appointments[i].HostID) {
+			appointments[i].HostNotes = sql.NullString{}
+		}
+	}
+
 	ctx.JSON(http.StatusOK, appointments)
 }
 
@@ -145,50 +206,100 @@ type getAppointmentByQRCodeRequest struct {
 	QRCode string `uri:"qr_code" binding:"required"`
 }
 
+type getAppointmentByQRCodeResponse struct {
+	db.GetAppointmentByQRCodeRow
+	PhotoURL *string `json:"photo_url"`
+}
+
 func (server *Server) getAppointmentByQRCode(ctx *gin.Context) {
 	var req getAppointmentByQRCodeRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	appointment, err := server.store.GetAppointmentByQRCode(ctx, sql.NullString{String: req.QRCode, Valid: true})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("no appointment found for this QR code")))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no appointment found for this QR code")))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, appointment)
+	if !canSeeHostNotes(optionalAuthPayload(ctx), appointment.HostID) {
+		appointment.HostNotes = sql.NullString{}
+	}
+
+	// A missing photo must not stop the scan, so storage errors are only logged
+	photoURL, err := server.visitorPhotoURL(ctx, appointment.VisitorID, appointment.ID)
+	if err != nil {
+		log.Printf("cannot sign photo URL for appointment %d: %v\n", appointment.ID, err)
+	}
+
+	ctx.JSON(http.StatusOK, getAppointmentByQRCodeResponse{
+		GetAppointmentByQRCodeRow: appointment,
+		PhotoURL:                  photoURL,
+	})
 }
 
+// Cancelling needs a reason and notifies the other party, so it goes through
+// POST /appointments/:id/cancel instead of this route
 type updateAppointmentStatusRequest struct {
 	ID     int64  `json:"id" binding:"required,min=1"`
-	Status string `json:"status" binding:"required,oneof=pending ongoing cancelled completed"`
+	Status string `json:"status" binding:"required,oneof=pending ongoing completed"`
 }
 
 func (server *Server) updateAppointmentStatus(ctx *gin.Context) {
 	var req updateAppointmentStatusRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no appointment found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if payload.UserID != appointment.HostID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the host or an admin can update the status of this appointment")))
 		return
 	}
 
-	arg := db.UpdateAppointmentStatusParams{
-		ID:     int32(req.ID),
-		Status: sql.NullString{String: req.Status, Valid: true},
+	arg := db.UpdateAppointmentStatusTxParams{
+		UpdateAppointmentStatusParams: db.UpdateAppointmentStatusParams{
+			ID:     appointment.ID,
+			Status: sql.NullString{String: req.Status, Valid: true},
+		},
 	}
 
-	appointment, err := server.store.UpdateAppointmentStatus(ctx, arg)
+	if req.Status == "ongoing" {
+		arg.CheckInNotifications, err = server.visitorCheckedInNotifications(ctx, appointment, time.Now(), "")
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	appointment, err = server.store.UpdateAppointmentStatusTx(ctx, arg)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("no appointment found with this ID")))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no appointment found with this ID")))
+			return
+		}
+		if err == db.ErrDeclarationsPending {
+			ctx.JSON(http.StatusPreconditionFailed, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -202,59 +313,386 @@ type getUserAppointmentStatsRequest struct {
 func (server *Server) getUserAppointmentStats(ctx *gin.Context) {
 	var req getUserAppointmentStatsRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	stats, err := server.store.GetUserAppointmentStats(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("no user found with this ID")))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no user found with this ID")))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, stats)
 }
 
+type cancelAppointmentRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
 func (server *Server) cancelAppointment(ctx *gin.Context) {
-	var req getAppointmentUriRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	var uri getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req cancelAppointmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	appointment, err := server.store.CancelAppointment(ctx, int32(req.ID))
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(uri.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	actor, err := server.store.GetUserByID(ctx, authPayload(ctx).UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if actor.ID != appointment.HostID && actor.ID != appointment.VisitorID && actor.Role.String != "admin" {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the host, the visitor or an admin can cancel this appointment")))
+		return
+	}
+
+	recipients, err := server.otherParties(ctx, appointment, actor)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err = server.store.CancelAppointmentTx(ctx, db.CancelAppointmentTxParams{
+		AppointmentID:      appointment.ID,
+		CancellationReason: req.Reason,
+		CancelledBy:        actor.ID,
+		Notifications:      server.outbox.Entries(notifications.AppointmentCancelled(appointment, req.Reason, actor, recipients)),
+	})
+	if err != nil {
+		if err == db.ErrAppointmentNotCancellable {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	server.offerFreedSlot(ctx, appointment)
+
+	if !canSeeHostNotes(authPayload(ctx), appointment.HostID) {
+		appointment.HostNotes = sql.NullString{}
+	}
+
 	ctx.JSON(http.StatusOK, appointment)
 }
 
+type rescheduleAppointmentRequest struct {
+	AppointmentDate time.Time `json:"appointment_date" binding:"required"`
+	StartTime       time.Time `json:"start_time" binding:"required"`
+	EndTime         time.Time `json:"end_time" binding:"required"`
+}
+
+// rescheduleAppointment moves a pending appointment to a new time. The host,
+// the visitor or an admin may reschedule; the other side is told, and the
+// freed slot is offered to the waitlist.
+func (server *Server) rescheduleAppointment(ctx *gin.Context) {
+	var uri getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req rescheduleAppointmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if !req.EndTime.After(req.StartTime) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("end_time must be after start_time")))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	actor, err := server.store.GetUserByID(ctx, authPayload(ctx).UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if actor.ID != appointment.HostID && actor.ID != appointment.VisitorID && actor.Role.String != "admin" {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the host, the visitor or an admin can reschedule this appointment")))
+		return
+	}
+
+	recipients, err := server.otherParties(ctx, appointment, actor)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	updated := appointment
+	updated.AppointmentDate = req.AppointmentDate
+	updated.StartTime = req.StartTime
+	updated.EndTime = req.EndTime
+
+	updated, err = server.store.RescheduleAppointmentTx(ctx, db.RescheduleAppointmentTxParams{
+		RescheduleAppointmentParams: db.RescheduleAppointmentParams{
+			ID:              appointment.ID,
+			AppointmentDate: req.AppointmentDate,
+			StartTime:       req.StartTime,
+			EndTime:         req.EndTime,
+		},
+		StartsAt:      ical.At(req.AppointmentDate, req.StartTime, server.location),
+		EndsAt:        ical.At(req.AppointmentDate, req.EndTime, server.location),
+		Notifications: server.outbox.Entries(notifications.AppointmentRescheduled(appointment, updated, actor, recipients)),
+	})
+	if err != nil {
+		if err == db.ErrAppointmentNotReschedulable || err == db.ErrSlotTaken || err == db.ErrHostBusy {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.offerFreedSlot(ctx, appointment)
+
+	if !canSeeHostNotes(authPayload(ctx), updated.HostID) {
+		updated.HostNotes = sql.NullString{}
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
 func (server *Server) deleteAppointment(ctx *gin.Context) {
 	var req getAppointmentUriRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	photos, err := server.store.ListVisitorPhotosByAppointment(ctx, int32(req.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err := server.purgeVisitorPhotos(ctx, photos); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	err := server.store.DeleteAppointment(ctx, int32(req.ID))
+	appointment, err := server.store.DeleteAppointmentTx(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	// Only slots that were still booked free up capacity for the waitlist
+	if appointment.Status.String == "pending" {
+		server.offerFreedSlot(ctx, appointment)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "appointment deleted"})
 }
+
+// otherParties returns who to tell about a change the actor made: the other
+// party, or both the host and the visitor when an admin made it.
+func (server *Server) otherParties(ctx *gin.Context, appointment db.Appointment, actor db.User) ([]db.User, error) {
+	recipients := []db.User{}
+	for _, userID := range []int32{appointment.HostID, appointment.VisitorID} {
+		if userID == actor.ID {
+			continue
+		}
+
+		user, err := server.store.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, user)
+	}
+	return recipients, nil
+}
+
+type listAppointmentsRequest struct {
+	HostID    int64    `form:"host_id" binding:"omitempty,min=1"`
+	VisitorID int64    `form:"visitor_id" binding:"omitempty,min=1"`
+	Status    []string `form:"status" binding:"dive,oneof=pending ongoing cancelled completed"`
+	From      string   `form:"from"`
+	To        string   `form:"to"`
+	Search    string   `form:"q"`
+	Order     string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	PageSize  int32    `form:"page_size" binding:"omitempty,min=5,max=100"`
+	Cursor    string   `form:"cursor"`
+}
+
+type listAppointmentsResponse struct {
+	Appointments []db.ListAppointmentsPageDescRow `json:"appointments"`
+	NextCursor   string                           `json:"next_cursor,omitempty"`
+}
+
+// listAppointments returns one page of appointments matching the filters.
+// Non-admin callers only ever see appointments they host or visit.
+func (server *Server) listAppointments(ctx *gin.Context) {
+	var req listAppointmentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	arg := db.ListAppointmentsPageDescParams{
+		HostID:    sql.NullInt32{Int32: int32(req.HostID), Valid: req.HostID != 0},
+		VisitorID: sql.NullInt32{Int32: int32(req.VisitorID), Valid: req.VisitorID != 0},
+		Statuses:  []string{},
+		Search:    sql.NullString{String: req.Search, Valid: req.Search != ""},
+		// Fetch one extra row to know whether there is a next page
+		PageSize: req.PageSize + 1,
+	}
+
+	// An empty (not nil) list means "any status"
+	arg.Statuses = append(arg.Statuses, req.Status...)
+
+	payload := authPayload(ctx)
+	if !payload.IsAdmin() {
+		arg.ParticipantID = sql.NullInt32{Int32: payload.UserID, Valid: true}
+	}
+
+	if req.From != "" {
+		from, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid from date format, use YYYY-MM-DD")))
+			return
+		}
+		arg.FromDate = sql.NullTime{Time: from, Valid: true}
+	}
+
+	if req.To != "" {
+		to, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid to date format, use YYYY-MM-DD")))
+			return
+		}
+		arg.ToDate = sql.NullTime{Time: to, Valid: true}
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeAppointmentCursor(req.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		arg.CursorDate = sql.NullTime{Time: cursor.date, Valid: true}
+		arg.CursorTime = sql.NullTime{Time: cursor.startTime, Valid: true}
+		arg.CursorID = sql.NullInt32{Int32: cursor.id, Valid: true}
+	}
+
+	var appointments []db.ListAppointmentsPageDescRow
+	if req.Order == "asc" {
+		rows, err := server.store.ListAppointmentsPageAsc(ctx, db.ListAppointmentsPageAscParams(arg))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		appointments = make([]db.ListAppointmentsPageDescRow, len(rows))
+		for i, row := range rows {
+			appointments[i] = db.ListAppointmentsPageDescRow(row)
+		}
+	} else {
+		var err error
+		appointments, err = server.store.ListAppointmentsPageDesc(ctx, arg)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	rsp := listAppointmentsResponse{Appointments: appointments}
+	if len(appointments) > int(req.PageSize) {
+		rsp.Appointments = appointments[:req.PageSize]
+		last := rsp.Appointments[len(rsp.Appointments)-1]
+		rsp.NextCursor = encodeAppointmentCursor(appointmentCursor{
+			date:      last.AppointmentDate,
+			startTime: last.StartTime,
+			id:        last.ID,
+		})
+	}
+
+	// Host notes are for staff only
+	for i := range rsp.Appointments {
+		if !canSeeHostNotes(payload, rsp.Appointments[i].HostID) {
+			rsp.Appointments[i].HostNotes = sql.NullString{}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// appointmentCursor marks the last row of a page in the listing sort order
+type appointmentCursor struct {
+	date      time.Time
+	startTime time.Time
+	id        int32
+}
+
+func encodeAppointmentCursor(cursor appointmentCursor) string {
+	raw := fmt.Sprintf("%s|%s|%d", cursor.date.Format("2006-01-02"), cursor.startTime.Format("15:04:05"), cursor.id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeAppointmentCursor(encoded string) (appointmentCursor, error) {
+	var cursor appointmentCursor
+	errInvalid := fmt.Errorf("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, errInvalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return cursor, errInvalid
+	}
+
+	if cursor.date, err = time.Parse("2006-01-02", parts[0]); err != nil {
+		return cursor, errInvalid
+	}
+	if cursor.startTime, err = time.Parse("15:04:05", parts[1]); err != nil {
+		return cursor, errInvalid
+	}
+
+	id, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return cursor, errInvalid
+	}
+	cursor.id = int32(id)
+
+	return cursor, nil
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
@@ -30,7 +32,7 @@ func parseTime(timeStr string) time.Time {
 func (server *Server) createAvailabilitySlot(ctx *gin.Context) {
 	var req createAvailabilitySlotRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -43,7 +45,7 @@ func (server *Server) createAvailabilitySlot(ctx *gin.Context) {
 
 	availabilitySlot, err := server.store.CreateAvailabilitySlot(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -59,17 +61,17 @@ type getAvailabilityByUserRequest struct {
 func (server *Server) getAvailabilityByUser(ctx *gin.Context) {
 	var req getAvailabilityByUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	availabilitySlots, err := server.store.GetAvailabilityByUser(ctx, int32(req.UserID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -88,7 +90,7 @@ type deleteAvailabilitySlotRequest struct {
 func (server *Server) deleteAvailabilitySlot(ctx *gin.Context) {
 	var req deleteAvailabilitySlotRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -100,7 +102,7 @@ func (server *Server) deleteAvailabilitySlot(ctx *gin.Context) {
 	}
 	err := server.store.DeleteAvailabilitySlot(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -116,13 +118,13 @@ type deleteAvailabilityByUserRequest struct {
 func (server *Server) deleteAvailabilityByUser(ctx *gin.Context) {
 	var req deleteAvailabilityByUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	err := server.store.DeleteAvailabilityByUser(ctx, int32(req.UserID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -142,7 +144,7 @@ type updateAvailabilityStatusRequest struct {
 func (server *Server) updateAvailabilityStatus(ctx *gin.Context) {
 	var req updateAvailabilityStatusRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -156,9 +158,172 @@ func (server *Server) updateAvailabilityStatus(ctx *gin.Context) {
 
 	err := server.store.UpdateAvailabilityStatus(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "availability status updated"})
 }
+
+type getFreeSlotsRequest struct {
+	Date string `form:"date" binding:"required"`
+}
+
+type timeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type getFreeSlotsResponse struct {
+	UserID  int32       `json:"user_id"`
+	Date    string      `json:"date"`
+	Free    []timeRange `json:"free"`
+	Blocked []timeRange `json:"blocked"`
+}
+
+// getFreeSlots returns when a host is free on a date: their available hours
+// for that weekday, less their appointments and the busy time imported from
+// their external calendars. What blocks each range is not disclosed.
+func (server *Server) getFreeSlots(ctx *gin.Context) {
+	var uri getAvailabilityByUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req getFreeSlotsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid date format, use YYYY-MM-DD")))
+		return
+	}
+	userID := int32(uri.UserID)
+
+	slots, err := server.store.GetAvailabilityByUser(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// day_of_week is ISO numbered, Monday being 1 and Sunday 7
+	isoDay := int32((date.Weekday()+6)%7) + 1
+	var available []minuteRange
+	for _, slot := range slots {
+		if slot.DayOfWeek == isoDay && slot.Status.String == "available" {
+			available = append(available, minuteRange{minuteOfDay(slot.StartTime), minuteOfDay(slot.EndTime)})
+		}
+	}
+
+	appointments, err := server.store.ListHostAppointmentTimes(ctx, db.ListHostAppointmentTimesParams{
+		HostID:          userID,
+		AppointmentDate: date,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	var blocked []minuteRange
+	for _, appointment := range appointments {
+		blocked = append(blocked, minuteRange{minuteOfDay(appointment.StartTime), minuteOfDay(appointment.EndTime)})
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, server.location)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	busy, err := server.store.ListBusyBlocks(ctx, db.ListBusyBlocksParams{
+		UserID: userID,
+		Since:  dayStart,
+		Until:  dayEnd,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	for _, block := range busy {
+		start, end := 0, 24*60
+		if block.StartsAt.After(dayStart) {
+			start = minuteOfDay(block.StartsAt.In(server.location))
+		}
+		if block.EndsAt.Before(dayEnd) {
+			// Round a partial minute up, but not past midnight into the next day
+			local := block.EndsAt.In(server.location)
+			end = minuteOfDay(local)
+			if local.Second() != 0 || local.Nanosecond() != 0 {
+				end = min(end+1, 24*60)
+			}
+		}
+		blocked = append(blocked, minuteRange{start, end})
+	}
+
+	blocked = mergeRanges(blocked)
+	rsp := getFreeSlotsResponse{
+		UserID:  userID,
+		Date:    req.Date,
+		Free:    formatRanges(subtractRanges(mergeRanges(available), blocked)),
+		Blocked: formatRanges(blocked),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// minuteRange is a half-open range of minutes since midnight.
+type minuteRange struct {
+	start, end int
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// mergeRanges sorts ranges and joins those that overlap or touch.
+func mergeRanges(ranges []minuteRange) []minuteRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	var merged []minuteRange
+	for _, r := range ranges {
+		if r.end <= r.start {
+			continue
+		}
+		if n := len(merged); n > 0 && r.start <= merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, r.end)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// subtractRanges removes the merged blocked ranges from the merged free ones.
+func subtractRanges(free, blocked []minuteRange) []minuteRange {
+	var result []minuteRange
+	for _, f := range free {
+		start := f.start
+		for _, b := range blocked {
+			if b.end <= start || b.start >= f.end {
+				continue
+			}
+			if b.start > start {
+				result = append(result, minuteRange{start, b.start})
+			}
+			start = max(start, b.end)
+		}
+		if start < f.end {
+			result = append(result, minuteRange{start, f.end})
+		}
+	}
+	return result
+}
+
+func formatRanges(ranges []minuteRange) []timeRange {
+	formatted := make([]timeRange, 0, len(ranges))
+	for _, r := range ranges {
+		formatted = append(formatted, timeRange{
+			Start: fmt.Sprintf("%02d:%02d", r.start/60, r.start%60),
+			End:   fmt.Sprintf("%02d:%02d", r.end/60, r.end%60),
+		})
+	}
+	return formatted
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// qrImageSize is the edge length in pixels of the rendered PNG
+	qrImageSize = 512
+	// qrModuleSize is the edge length of one QR module in SVG user units
+	qrModuleSize = 8
+)
+
+// loadBadge fetches the appointment together with the names printed on its
+// badge and checks that the caller is its visitor, its host or an admin. It
+// writes the error response itself and returns false when access is denied.
+func (server *Server) loadBadge(ctx *gin.Context) (db.GetAppointmentBadgeRow, bool) {
+	var req getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return db.GetAppointmentBadgeRow{}, false
+	}
+
+	badge, err := server.store.GetAppointmentBadge(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.GetAppointmentBadgeRow{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.GetAppointmentBadgeRow{}, false
+	}
+
+	payload := authPayload(ctx)
+	if !payload.IsAdmin() && payload.UserID != badge.VisitorID && payload.UserID != badge.HostID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the visitor, the host or an admin can view this appointment's badge")))
+		return db.GetAppointmentBadgeRow{}, false
+	}
+
+	if !badge.QrCode.Valid || badge.QrCode.String == "" {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("appointment has no QR code")))
+		return db.GetAppointmentBadgeRow{}, false
+	}
+
+	return badge, true
+}
+
+// getAppointmentQRPNG renders the appointment's QR token as a PNG image.
+func (server *Server) getAppointmentQRPNG(ctx *gin.Context) {
+	badge, ok := server.loadBadge(ctx)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.Encode(badge.QrCode.String, qrcode.Medium, qrImageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, "image/png", png)
+}
+
+// getAppointmentQRSVG renders the appointment's QR token as an SVG image.
+func (server *Server) getAppointmentQRSVG(ctx *gin.Context) {
+	badge, ok := server.loadBadge(ctx)
+	if !ok {
+		return
+	}
+
+	code, err := qrcode.New(badge.QrCode.String, qrcode.Medium)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, "image/svg+xml", qrSVG(code.Bitmap()))
+}
+
+// qrSVG draws each dark module of the bitmap as a unit square in a single
+// path, which keeps the output small and scales cleanly when printed.
+func qrSVG(bitmap [][]bool) []byte {
+	size := len(bitmap) * qrModuleSize
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh%dv%dh-%dz", x*qrModuleSize, y*qrModuleSize, qrModuleSize, qrModuleSize, qrModuleSize)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`, size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, size, size)
+	fmt.Fprintf(&buf, `<path fill="#000" d="%s"/>`, path.String())
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// getAppointmentBadgePDF renders a printable visitor badge sized for a
+// standard 4x3 inch badge holder.
+func (server *Server) getAppointmentBadgePDF(ctx *gin.Context) {
+	badge, ok := server.loadBadge(ctx)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.Encode(badge.QrCode.String, qrcode.Medium, qrImageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "L",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: 76.2, Ht: 101.6},
+	})
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(fmt.Sprintf("Visitor badge #%d", badge.ID), true)
+	pdf.SetMargins(6, 6, 6)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	pdf.SetFillColor(33, 37, 41)
+	pdf.Rect(0, 0, 101.6, 12, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.SetXY(6, 2)
+	pdf.CellFormat(0, 8, "VISITOR", "", 0, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	pdf.ImageOptions("qr", 60, 20, 36, 36, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	host := badge.HostName
+	if badge.HostDepartment.Valid && badge.HostDepartment.String != "" {
+		host = fmt.Sprintf("%s (%s)", host, badge.HostDepartment.String)
+	}
+
+	pdf.SetXY(6, 18)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.MultiCell(52, 7, tr(badge.VisitorName), "", "L", false)
+
+	lines := []struct{ label, value string }{
+		{"Host", host},
+		{"Date", badge.AppointmentDate.Format("Mon, 02 Jan 2006")},
+		{"Valid", badge.StartTime.Format("15:04") + " - " + badge.EndTime.Format("15:04")},
+	}
+	if badge.Location.Valid && badge.Location.String != "" {
+		lines = append(lines, struct{ label, value string }{"Location", badge.Location.String})
+	}
+
+	pdf.Ln(2)
+	for _, line := range lines {
+		pdf.SetX(6)
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.CellFormat(14, 5, line.label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.MultiCell(38, 5, tr(line.value), "", "L", false)
+	}
+
+	pdf.SetXY(6, 66)
+	pdf.SetFont("Helvetica", "I", 7)
+	pdf.CellFormat(0, 5, fmt.Sprintf("Appointment #%d - please return this badge at the front desk", badge.ID), "", 0, "L", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=badge_%d.pdf", badge.ID))
+	ctx.Status(http.StatusOK)
+	if err := pdf.Output(ctx.Writer); err != nil {
+		log.Println("badge PDF render aborted:", err)
+	}
+}
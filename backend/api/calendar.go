@@ -0,0 +1,116 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type getCalendarRequest struct {
+	View   string `form:"view" binding:"required,oneof=month week"`
+	UserID int64  `form:"user_id" binding:"omitempty,min=1"`
+	Start  string `form:"start"` // Optional — defaults to today
+}
+
+type calendarDay struct {
+	db.GetCalendarSummaryRow
+	CoveragePercent float64 `json:"coverage_percent"`
+}
+
+type getCalendarResponse struct {
+	View   string        `json:"view"`
+	UserID *int32        `json:"user_id"`
+	Start  string        `json:"start"`
+	End    string        `json:"end"`
+	Days   []calendarDay `json:"days"`
+}
+
+// getCalendar returns per-day appointment aggregates for a month or week.
+// Admins may omit user_id to get the whole site; everyone else only sees
+// their own calendar.
+func (server *Server) getCalendar(ctx *gin.Context) {
+	var req getCalendarRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	anchor := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Start != "" {
+		parsed, err := time.Parse("2006-01-02", req.Start)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid start date format, use YYYY-MM-DD")))
+			return
+		}
+		anchor = parsed
+	}
+
+	payload := authPayload(ctx)
+	userID := sql.NullInt32{Int32: int32(req.UserID), Valid: req.UserID != 0}
+	if !payload.IsAdmin() {
+		if userID.Valid && userID.Int32 != payload.UserID {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("you can only view your own calendar")))
+			return
+		}
+		userID = sql.NullInt32{Int32: payload.UserID, Valid: true}
+	}
+
+	start, end := calendarRange(req.View, anchor)
+
+	rows, err := server.store.GetCalendarSummary(ctx, db.GetCalendarSummaryParams{
+		StartDate: start,
+		EndDate:   end,
+		UserID:    userID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := getCalendarResponse{
+		View:  req.View,
+		Start: start.Format("2006-01-02"),
+		End:   end.Format("2006-01-02"),
+		Days:  make([]calendarDay, len(rows)),
+	}
+	if userID.Valid {
+		rsp.UserID = &userID.Int32
+	}
+
+	for i, row := range rows {
+		rsp.Days[i] = calendarDay{
+			GetCalendarSummaryRow: row,
+			CoveragePercent:       coveragePercent(row.BookedMinutes, row.AvailableMinutes),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// calendarRange snaps the anchor date to the month or the Monday-based week
+// containing it and returns the first and last day of that range.
+func calendarRange(view string, anchor time.Time) (time.Time, time.Time) {
+	if view == "week" {
+		offset := (int(anchor.Weekday()) + 6) % 7
+		start := anchor.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 6)
+	}
+
+	start := time.Date(anchor.Year(), anchor.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, -1)
+}
+
+// coveragePercent is the share of available minutes that are booked, rounded
+// to one decimal place and capped at 100.
+func coveragePercent(booked, available int32) float64 {
+	if available <= 0 {
+		return 0
+	}
+	percent := float64(booked) / float64(available) * 100
+	return math.Min(math.Round(percent*10)/10, 100)
+}
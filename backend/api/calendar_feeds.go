@@ -0,0 +1,199 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+)
+
+// calendarFeedHistory is how far back the ICS feed goes; upcoming
+// appointments are always included
+const calendarFeedHistory = 90 * 24 * time.Hour
+
+// calendarFeedResponse carries the subscription URL only when a token has
+// just been issued; afterwards only its hash is kept, like a kiosk key.
+type calendarFeedResponse struct {
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (server *Server) newCalendarFeedResponse(feed db.CalendarFeed, token string) calendarFeedResponse {
+	rsp := calendarFeedResponse{CreatedAt: feed.CreatedAt}
+	if token != "" {
+		rsp.URL = fmt.Sprintf("%s/calendar/%s.ics", strings.TrimRight(server.config.PublicURL, "/"), token)
+	}
+	return rsp
+}
+
+// getCalendarFeed issues the caller's ICS subscription URL on first use.
+// Anyone holding the URL can read the feed, so it should be kept as private
+// as a password; once issued it cannot be shown again, only rotated.
+func (server *Server) getCalendarFeed(ctx *gin.Context) {
+	userID := authPayload(ctx).UserID
+
+	token, err := util.RandomToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	feed, err := server.store.CreateCalendarFeed(ctx, db.CreateCalendarFeedParams{
+		UserID:    userID,
+		TokenHash: util.HashToken(token),
+	})
+	if err == nil {
+		ctx.JSON(http.StatusOK, server.newCalendarFeedResponse(feed, token))
+		return
+	}
+	if err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	feed, err = server.store.GetCalendarFeed(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newCalendarFeedResponse(feed, ""))
+}
+
+// rotateCalendarFeed replaces the caller's feed token, so the old URL stops
+// working.
+func (server *Server) rotateCalendarFeed(ctx *gin.Context) {
+	token, err := util.RandomToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	feed, err := server.store.RotateCalendarFeed(ctx, db.RotateCalendarFeedParams{
+		UserID:    authPayload(ctx).UserID,
+		TokenHash: util.HashToken(token),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newCalendarFeedResponse(feed, token))
+}
+
+type serveCalendarFeedRequest struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+// serveCalendarFeed is the ICS feed calendar clients subscribe to: every
+// appointment the token's owner hosts or visits, cancelled ones included so
+// clients drop them. It is unauthenticated, the token being the credential.
+func (server *Server) serveCalendarFeed(ctx *gin.Context) {
+	var req serveCalendarFeedRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	token, ok := strings.CutSuffix(req.Token, ".ics")
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("calendar feeds end in .ics")))
+		return
+	}
+
+	tokenHash := util.HashToken(token)
+	feed, err := server.store.GetCalendarFeedByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("calendar feed not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(feed.TokenHash), []byte(tokenHash)) != 1 {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("calendar feed not found")))
+		return
+	}
+
+	hosted, err := server.store.ListAppointmentsByHost(ctx, feed.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	visits, err := server.store.ListAppointmentsByVisitor(ctx, feed.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	owner, err := server.store.GetUserByID(ctx, feed.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	locale := owner.PreferredLocale.String
+
+	since := time.Now().Add(-calendarFeedHistory)
+	domain := ical.Domain(server.config.PublicURL)
+	cal := ical.Calendar{Name: "VisiTrack"}
+
+	for _, a := range hosted {
+		event := server.feedEvent(domain, a.ID, a.IcsSequence, a.AppointmentDate, a.StartTime, a.EndTime, a.Status, a.Location, a.Purpose)
+		if event.End.Before(since) {
+			continue
+		}
+		event.Summary, err = server.feedSummary(notifications.MessageFeedSummaryHost, locale, map[string]any{"Visitor": a.VisitorName})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		cal.Events = append(cal.Events, event)
+	}
+	for _, a := range visits {
+		event := server.feedEvent(domain, a.ID, a.IcsSequence, a.AppointmentDate, a.StartTime, a.EndTime, a.Status, a.Location, a.Purpose)
+		if event.End.Before(since) {
+			continue
+		}
+		event.Summary, err = server.feedSummary(notifications.MessageFeedSummaryVisitor, locale, map[string]any{"Host": a.HostName})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		cal.Events = append(cal.Events, event)
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.Encode())
+}
+
+// feedSummary renders an event title in the feed owner's locale.
+func (server *Server) feedSummary(key, locale string, params map[string]any) (string, error) {
+	_, summary, err := server.catalog.Render(key, locale, params)
+	return summary, err
+}
+
+// feedEvent is the part of an appointment's feed entry that does not depend
+// on whether the owner hosts or visits.
+func (server *Server) feedEvent(domain string, id, sequence int32, date, start, end time.Time, status, location, purpose sql.NullString) ical.Event {
+	event := ical.Event{
+		UID:         ical.UID(id, domain),
+		Sequence:    sequence,
+		Start:       ical.At(date, start, server.location),
+		End:         ical.At(date, end, server.location),
+		Location:    location.String,
+		Description: purpose.String,
+		Status:      ical.StatusConfirmed,
+	}
+	if status.String == "cancelled" {
+		event.Status = ical.StatusCancelled
+	}
+	return event
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/worker"
+	"github.com/gin-gonic/gin"
+)
+
+type calendarImportResponse struct {
+	db.ListCalendarImportsByUserRow
+	Source string `json:"source"` // "url" or "upload"
+}
+
+func newCalendarImportResponse(row db.ListCalendarImportsByUserRow) calendarImportResponse {
+	source := "upload"
+	if row.Url.Valid {
+		source = "url"
+	}
+	return calendarImportResponse{ListCalendarImportsByUserRow: row, Source: source}
+}
+
+// calendarImportResponseOf leaves out the uploaded file itself.
+func calendarImportResponseOf(calendar db.CalendarImport) calendarImportResponse {
+	return newCalendarImportResponse(db.ListCalendarImportsByUserRow{
+		ID:              calendar.ID,
+		UserID:          calendar.UserID,
+		Name:            calendar.Name,
+		Url:             calendar.Url,
+		BusyCount:       calendar.BusyCount,
+		LastSyncedAt:    calendar.LastSyncedAt,
+		LastAttemptedAt: calendar.LastAttemptedAt,
+		LastError:       calendar.LastError,
+		CreatedAt:       calendar.CreatedAt,
+	})
+}
+
+type createCalendarImportRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+	URL  string `json:"url" binding:"required,max=2048"`
+}
+
+// createCalendarImport registers an ICS URL, such as an Outlook or Google
+// Calendar "secret address", to import the caller's busy time from. The URL
+// is fetched by the calendar import worker, never during the request;
+// webcal:// is stored as https://.
+func (server *Server) createCalendarImport(ctx *gin.Context) {
+	var req createCalendarImportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err == nil && parsed.Scheme == "webcal" {
+		parsed.Scheme = "https"
+	}
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("url must be an absolute http, https or webcal URL")))
+		return
+	}
+
+	calendar, err := server.store.CreateCalendarImport(ctx, db.CreateCalendarImportParams{
+		UserID: authPayload(ctx).UserID,
+		Name:   req.Name,
+		Url:    sql.NullString{String: parsed.String(), Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarImportResponseOf(calendar))
+}
+
+// uploadCalendarImport imports busy time from an .ics file sent as a
+// multipart form in the "file" field, with an optional "name".
+func (server *Server) uploadCalendarImport(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, worker.MaxICSSize+64<<10)
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, apiErrorf("file is required and must be at most %d MB", worker.MaxICSSize>>20)))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, worker.MaxICSSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if len(data) > worker.MaxICSSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(ctx, apiErrorf("file must be at most %d MB", worker.MaxICSSize>>20)))
+		return
+	}
+
+	// Reject a file that cannot be read now rather than on every sync
+	if _, err := ical.BusyTimes(bytes.NewReader(data), time.Now(), time.Now(), server.location); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	name := strings.TrimSpace(ctx.PostForm("name"))
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	calendar, err := server.store.CreateCalendarImport(ctx, db.CreateCalendarImportParams{
+		UserID:  authPayload(ctx).UserID,
+		Name:    name,
+		IcsData: sql.NullString{String: string(data), Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarImportResponseOf(calendar))
+}
+
+// listCalendarImports returns the caller's imports with when each last
+// synced and why the latest attempt failed, if it did.
+func (server *Server) listCalendarImports(ctx *gin.Context) {
+	rows, err := server.store.ListCalendarImportsByUser(ctx, authPayload(ctx).UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]calendarImportResponse, 0, len(rows))
+	for _, row := range rows {
+		rsp = append(rsp, newCalendarImportResponse(row))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type calendarImportUriRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// ownCalendarImport loads an import the caller owns, or that an admin is
+// managing, writing the error response otherwise.
+func (server *Server) ownCalendarImport(ctx *gin.Context) (db.CalendarImport, bool) {
+	var req calendarImportUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return db.CalendarImport{}, false
+	}
+
+	calendar, err := server.store.GetCalendarImport(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.CalendarImport{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.CalendarImport{}, false
+	}
+
+	payload := authPayload(ctx)
+	if calendar.UserID != payload.UserID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("this calendar import belongs to another user")))
+		return db.CalendarImport{}, false
+	}
+	return calendar, true
+}
+
+// resyncCalendarImport makes an import due, so the worker syncs it on its
+// next tick instead of after CALENDAR_SYNC_INTERVAL.
+func (server *Server) resyncCalendarImport(ctx *gin.Context) {
+	calendar, ok := server.ownCalendarImport(ctx)
+	if !ok {
+		return
+	}
+
+	calendar, err := server.store.RequestCalendarImportSync(ctx, calendar.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendarImportResponseOf(calendar))
+}
+
+// deleteCalendarImport removes an import and the busy time it blocked.
+func (server *Server) deleteCalendarImport(ctx *gin.Context) {
+	calendar, ok := server.ownCalendarImport(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteCalendarImport(ctx, calendar.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "calendar import deleted"})
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type replyToCheckInRequest struct {
+	Reply   string `json:"reply" binding:"required,oneof=on_my_way please_wait"`
+	Message string `json:"message" binding:"max=200"` // Optional — e.g. "5 minutes, grab a coffee"
+}
+
+// replyToCheckIn lets the host answer a check-in notification. The latest
+// reply is shown to guards on the on-site roster.
+func (server *Server) replyToCheckIn(ctx *gin.Context) {
+	var uri getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req replyToCheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if payload.UserID != appointment.HostID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the host can reply to a check-in")))
+		return
+	}
+	if appointment.Status.String == "cancelled" {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, fmt.Errorf("appointment has been cancelled")))
+		return
+	}
+
+	reply, err := server.store.CreateCheckInReply(ctx, db.CreateCheckInReplyParams{
+		AppointmentID: appointment.ID,
+		HostID:        payload.UserID,
+		Reply:         req.Reply,
+		Message:       sql.NullString{String: req.Message, Valid: req.Message != ""},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reply)
+}
+
+// listCheckInReplies returns the host's replies for an appointment, newest
+// first, for guards and the host.
+func (server *Server) listCheckInReplies(ctx *gin.Context) {
+	var req getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if !payload.IsAdmin() && payload.UserID != appointment.HostID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the host or an admin can view check-in replies")))
+		return
+	}
+
+	replies, err := server.store.ListCheckInReplies(ctx, appointment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, replies)
+}
@@ -0,0 +1,408 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// declarationQuestion is one question of a declaration form. The list is
+// stored as JSON on the form version it belongs to.
+type declarationQuestion struct {
+	ID       string   `json:"id" binding:"required,max=50"`
+	Label    string   `json:"label" binding:"required,max=500"`
+	Type     string   `json:"type" binding:"required,oneof=text yes_no choice"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty" binding:"omitempty,dive,required,max=100"`
+}
+
+// encodeQuestions checks that question ids are unique and that choice
+// questions have options, and returns the list as JSON.
+func encodeQuestions(questions []declarationQuestion) (json.RawMessage, error) {
+	if questions == nil {
+		questions = []declarationQuestion{}
+	}
+
+	seen := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		if seen[question.ID] {
+			return nil, apiErrorf("duplicate question id %q", question.ID)
+		}
+		seen[question.ID] = true
+
+		if question.Type == "choice" && len(question.Options) < 2 {
+			return nil, apiErrorf("choice question %q needs at least two options", question.ID)
+		}
+		if question.Type != "choice" && len(question.Options) > 0 {
+			return nil, apiErrorf("only choice questions can have options, see question %q", question.ID)
+		}
+	}
+
+	return json.Marshal(questions)
+}
+
+// validateAnswers checks submitted answers against the form version's
+// questions: required questions must be answered, and every answer must
+// match its question's type.
+func validateAnswers(rawQuestions json.RawMessage, answers map[string]any) error {
+	var questions []declarationQuestion
+	if err := json.Unmarshal(rawQuestions, &questions); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		known[question.ID] = true
+
+		answer, ok := answers[question.ID]
+		if !ok || answer == nil || answer == "" {
+			if question.Required {
+				return apiErrorf("question %q is required", question.ID)
+			}
+			continue
+		}
+
+		switch question.Type {
+		case "yes_no":
+			if _, ok := answer.(bool); !ok {
+				return apiErrorf("question %q must be answered with true or false", question.ID)
+			}
+		case "text":
+			if _, ok := answer.(string); !ok {
+				return apiErrorf("question %q must be answered with text", question.ID)
+			}
+		case "choice":
+			choice, _ := answer.(string)
+			valid := false
+			for _, option := range question.Options {
+				valid = valid || option == choice
+			}
+			if !valid {
+				return apiErrorf("question %q must be answered with one of its options", question.ID)
+			}
+		}
+	}
+
+	for id := range answers {
+		if !known[id] {
+			return apiErrorf("unknown question %q", id)
+		}
+	}
+	return nil
+}
+
+type createDeclarationFormRequest struct {
+	Title     string                `json:"title" binding:"required,max=150"`
+	Kind      string                `json:"kind" binding:"required,oneof=nda questionnaire"`
+	Required  *bool                 `json:"is_required"` // Optional — defaults to true
+	Body      string                `json:"body" binding:"required"`
+	Questions []declarationQuestion `json:"questions" binding:"dive"`
+}
+
+// createDeclarationForm creates a form and publishes its first version.
+func (server *Server) createDeclarationForm(ctx *gin.Context) {
+	var req createDeclarationFormRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	questions, err := encodeQuestions(req.Questions)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.CreateDeclarationFormTx(ctx, db.CreateDeclarationFormTxParams{
+		CreateDeclarationFormParams: db.CreateDeclarationFormParams{
+			Title:      req.Title,
+			Kind:       req.Kind,
+			IsRequired: req.Required == nil || *req.Required,
+			CreatedBy:  sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+		},
+		Body:      req.Body,
+		Questions: questions,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listDeclarationFormsRequest struct {
+	IncludeInactive bool `form:"include_inactive"`
+}
+
+// listDeclarationForms lists forms with their current version.
+func (server *Server) listDeclarationForms(ctx *gin.Context) {
+	var req listDeclarationFormsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	forms, err := server.store.ListDeclarationForms(ctx, sql.NullBool{Bool: true, Valid: !req.IncludeInactive})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, forms)
+}
+
+type declarationFormUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type updateDeclarationFormRequest struct {
+	Title    string `json:"title" binding:"required,max=150"`
+	Required bool   `json:"is_required"`
+	Active   bool   `json:"is_active"`
+}
+
+// updateDeclarationForm renames a form or changes whether it is required or
+// shown at all. Changing the text or questions publishes a new version instead.
+func (server *Server) updateDeclarationForm(ctx *gin.Context) {
+	var uri declarationFormUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateDeclarationFormRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	form, err := server.store.UpdateDeclarationForm(ctx, db.UpdateDeclarationFormParams{
+		ID:         int32(uri.ID),
+		Title:      req.Title,
+		IsRequired: req.Required,
+		IsActive:   req.Active,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, form)
+}
+
+type publishDeclarationVersionRequest struct {
+	Body      string                `json:"body" binding:"required"`
+	Questions []declarationQuestion `json:"questions" binding:"dive"`
+}
+
+// publishDeclarationVersion publishes new text and questions for a form.
+// Visits that already signed an older version stay signed; the new version
+// is what later visits are asked to complete.
+func (server *Server) publishDeclarationVersion(ctx *gin.Context) {
+	var uri declarationFormUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req publishDeclarationVersionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	questions, err := encodeQuestions(req.Questions)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, err := server.store.GetDeclarationForm(ctx, int32(uri.ID)); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	version, err := server.store.CreateDeclarationFormVersion(ctx, db.CreateDeclarationFormVersionParams{
+		FormID:    int32(uri.ID),
+		Body:      req.Body,
+		Questions: questions,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, fmt.Errorf("another version was published at the same time, please retry")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, version)
+}
+
+// listDeclarationVersions returns every published version of a form, newest first.
+func (server *Server) listDeclarationVersions(ctx *gin.Context) {
+	var uri declarationFormUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	versions, err := server.store.ListDeclarationFormVersions(ctx, int32(uri.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versions)
+}
+
+type appointmentDeclarationsResponse struct {
+	AppointmentID int32                               `json:"appointment_id"`
+	Pending       int                                 `json:"pending"`
+	Forms         []db.ListAppointmentDeclarationsRow `json:"forms"`
+}
+
+// getAppointmentDeclarations returns the forms to present at check-in along
+// with anything this visit has already completed.
+func (server *Server) getAppointmentDeclarations(ctx *gin.Context) {
+	var req getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if payload.UserID != appointment.VisitorID && payload.UserID != appointment.HostID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the visitor, the host or an admin can see these declarations")))
+		return
+	}
+
+	forms, err := server.store.ListAppointmentDeclarations(ctx, appointment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// Counted the way check-in counts them: a form signed in an earlier
+	// version is not pending again
+	pending, err := server.store.CountPendingDeclarations(ctx, appointment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := appointmentDeclarationsResponse{AppointmentID: appointment.ID, Pending: int(pending), Forms: forms}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type submitDeclarationRequest struct {
+	FormVersionID int64          `json:"form_version_id" binding:"required,min=1"`
+	Answers       map[string]any `json:"answers"`
+	SignedName    string         `json:"signed_name" binding:"required,max=100"`
+	Accepted      bool           `json:"accepted" binding:"required"`
+}
+
+// submitAppointmentDeclaration records the visitor's acceptance of a form
+// and their answers to its questions. Resubmitting replaces the answers.
+func (server *Server) submitAppointmentDeclaration(ctx *gin.Context) {
+	var uri getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req submitDeclarationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	payload := authPayload(ctx)
+	if payload.UserID != appointment.VisitorID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only the visitor or an admin can sign these declarations")))
+		return
+	}
+	if appointment.Status.String == "cancelled" || appointment.Status.String == "completed" {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, apiErrorf("appointment is %s", appointment.Status.String)))
+		return
+	}
+
+	// Only the current version of an active form can be completed
+	forms, err := server.store.ListAppointmentDeclarations(ctx, appointment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var form *db.ListAppointmentDeclarationsRow
+	for i := range forms {
+		if int64(forms[i].VersionID) == req.FormVersionID {
+			form = &forms[i]
+		}
+	}
+	if form == nil {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, fmt.Errorf("form version is not current, reload the forms and try again")))
+		return
+	}
+
+	if req.Answers == nil {
+		req.Answers = map[string]any{}
+	}
+	if err := validateAnswers(form.Questions, req.Answers); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	answers, err := json.Marshal(req.Answers)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	response, err := server.store.UpsertDeclarationResponse(ctx, db.UpsertDeclarationResponseParams{
+		AppointmentID: appointment.ID,
+		FormVersionID: form.VersionID,
+		Answers:       answers,
+		SignedName:    req.SignedName,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
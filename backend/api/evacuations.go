@@ -0,0 +1,184 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// startEvacuation snapshots the on-site roster into a new evacuation.
+func (server *Server) startEvacuation(ctx *gin.Context) {
+	payload := authPayload(ctx)
+
+	result, err := server.store.StartEvacuationTx(ctx, payload.UserID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, fmt.Errorf("an evacuation is already in progress")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listEvacuationsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listEvacuations(ctx *gin.Context) {
+	var req listEvacuationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	evacuations, err := server.store.ListEvacuations(ctx, db.ListEvacuationsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evacuations)
+}
+
+type evacuationUriRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type evacuationReport struct {
+	Evacuation   db.Evacuation       `json:"evacuation"`
+	Total        int                 `json:"total"`
+	AccountedFor int                 `json:"accounted_for"`
+	Missing      int                 `json:"missing"`
+	Roll         []db.EvacuationRoll `json:"roll"`
+}
+
+// getEvacuation returns the evacuation with its roll and running totals.
+// Once the evacuation is closed this is the final report.
+func (server *Server) getEvacuation(ctx *gin.Context) {
+	var req evacuationUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	evacuation, err := server.store.GetEvacuation(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	report, err := server.buildEvacuationReport(ctx, evacuation)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+type markEvacuationRollUriRequest struct {
+	ID      int64 `uri:"id" binding:"required,min=1"`
+	EntryID int64 `uri:"entry_id" binding:"required,min=1"`
+}
+
+type markEvacuationRollRequest struct {
+	AccountedFor *bool `json:"accounted_for" binding:"required"`
+}
+
+// markEvacuationRollEntry lets a marshal tick a person off, or undo it.
+func (server *Server) markEvacuationRollEntry(ctx *gin.Context) {
+	var uri markEvacuationRollUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req markEvacuationRollRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	entry, err := server.store.MarkEvacuationRollEntry(ctx, db.MarkEvacuationRollEntryParams{
+		AccountedFor: *req.AccountedFor,
+		AccountedBy:  sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+		ID:           int32(uri.EntryID),
+		EvacuationID: int32(uri.ID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no roll entry found in an active evacuation")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// closeEvacuation ends the evacuation and returns the final report.
+func (server *Server) closeEvacuation(ctx *gin.Context) {
+	var req evacuationUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	evacuation, err := server.store.CloseEvacuation(ctx, db.CloseEvacuationParams{
+		ID:       int32(req.ID),
+		ClosedBy: sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no active evacuation found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	report, err := server.buildEvacuationReport(ctx, evacuation)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (server *Server) buildEvacuationReport(ctx *gin.Context, evacuation db.Evacuation) (evacuationReport, error) {
+	roll, err := server.store.ListEvacuationRoll(ctx, evacuation.ID)
+	if err != nil {
+		return evacuationReport{}, err
+	}
+
+	report := evacuationReport{
+		Evacuation: evacuation,
+		Total:      len(roll),
+		Roll:       roll,
+	}
+	for _, entry := range roll {
+		if entry.AccountedFor {
+			report.AccountedFor++
+		}
+	}
+	report.Missing = report.Total - report.AccountedFor
+
+	return report, nil
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	// logbookBatchSize is how many rows are read from the database at a time
+	logbookBatchSize = 500
+	// maxPDFExportDays bounds PDF exports. A PDF cannot be streamed: its page
+	// count and cross-reference table are only known once every page is laid
+	// out, so the whole document is held in memory before it is written.
+	maxPDFExportDays = 93
+)
+
+var logbookColumns = []string{
+	"Date", "Visitor", "Phone", "Host", "Purpose", "Scheduled", "Check-in", "Check-out", "Status",
+}
+
+type exportLogbookRequest struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to" binding:"required"`
+}
+
+func bindExportRange(ctx *gin.Context) (time.Time, time.Time, bool) {
+	var req exportLogbookRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return time.Time{}, time.Time{}, false
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid from date format, use YYYY-MM-DD")))
+		return time.Time{}, time.Time{}, false
+	}
+
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid to date format, use YYYY-MM-DD")))
+		return time.Time{}, time.Time{}, false
+	}
+
+	if to.Before(from) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("to date must not be before from date")))
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// eachLogbookPage walks the logbook for the date range in keyset-paginated
+// batches so that large ranges never sit in memory at once.
+func (server *Server) eachLogbookPage(ctx *gin.Context, from, to time.Time, fn func(rows []db.ListLogbookPageRow) error) error {
+	arg := db.ListLogbookPageParams{
+		FromDate: from,
+		ToDate:   to,
+		PageSize: logbookBatchSize,
+	}
+
+	for {
+		rows, err := server.store.ListLogbookPage(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		if err := fn(rows); err != nil {
+			return err
+		}
+
+		if len(rows) < logbookBatchSize {
+			return nil
+		}
+
+		last := rows[len(rows)-1]
+		arg.CursorDate = sql.NullTime{Time: last.AppointmentDate, Valid: true}
+		arg.CursorTime = sql.NullTime{Time: last.StartTime, Valid: true}
+		arg.CursorID = sql.NullInt32{Int32: last.ID, Valid: true}
+	}
+}
+
+func logbookRecord(row db.ListLogbookPageRow) []string {
+	formatLogTime := func(t sql.NullTime) string {
+		if !t.Valid {
+			return ""
+		}
+		return t.Time.Format("15:04")
+	}
+
+	purpose := row.Purpose.String
+	if row.PurposeCategory.Valid {
+		if purpose == "" {
+			purpose = row.PurposeCategory.String
+		} else {
+			purpose = fmt.Sprintf("%s (%s)", purpose, row.PurposeCategory.String)
+		}
+	}
+
+	return []string{
+		row.AppointmentDate.Format("2006-01-02"),
+		row.VisitorName,
+		util.MaskPhone(row.VisitorPhone),
+		row.HostName,
+		purpose,
+		row.StartTime.Format("15:04") + "-" + row.EndTime.Format("15:04"),
+		formatLogTime(row.CheckInTime),
+		formatLogTime(row.CheckOutTime),
+		row.Status.String,
+	}
+}
+
+// clearExportDeadline lifts SERVER_WRITE_TIMEOUT, which is meant for
+// ordinary requests, off an export that can run for minutes.
+func clearExportDeadline(ctx *gin.Context) bool {
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// exportLogbookCSV streams the visitor register as CSV.
+func (server *Server) exportLogbookCSV(ctx *gin.Context) {
+	from, to, ok := bindExportRange(ctx)
+	if !ok || !clearExportDeadline(ctx) {
+		return
+	}
+
+	writer := csv.NewWriter(ctx.Writer)
+	started := false
+
+	// Headers are only committed once the first batch has loaded, so an early
+	// database error can still be reported as a normal JSON error
+	start := func() error {
+		started = true
+		ctx.Header("Content-Type", "text/csv")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=logbook_%s_%s.csv", from.Format("20060102"), to.Format("20060102")))
+		ctx.Status(http.StatusOK)
+		return writer.Write(logbookColumns)
+	}
+
+	err := server.eachLogbookPage(ctx, from, to, func(rows []db.ListLogbookPageRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range rows {
+			if err := writer.Write(logbookRecord(row)); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		ctx.Writer.Flush()
+		return writer.Error()
+	})
+	if err == nil && !started {
+		err = start()
+		writer.Flush()
+	}
+	if err != nil {
+		if !started {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		log.Println("logbook CSV export aborted:", err)
+	}
+}
+
+// exportLogbookPDF renders the visitor register as a printable, paginated PDF.
+// Ranges longer than maxPDFExportDays are rejected with a 400; the CSV export
+// has no such limit.
+func (server *Server) exportLogbookPDF(ctx *gin.Context) {
+	from, to, ok := bindExportRange(ctx)
+	if !ok {
+		return
+	}
+
+	if to.Sub(from) > maxPDFExportDays*24*time.Hour {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, apiErrorf("PDF exports are limited to %d days, use the CSV export for longer ranges", maxPDFExportDays)))
+		return
+	}
+	if !clearExportDeadline(ctx) {
+		return
+	}
+
+	widths := []float64{22, 40, 30, 40, 55, 24, 20, 20, 24}
+	title := fmt.Sprintf("Visitor Register %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(true, 15)
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 10, title, "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for i, column := range logbookColumns {
+			pdf.CellFormat(widths[i], 7, column, "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 8)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 8, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	err := server.eachLogbookPage(ctx, from, to, func(rows []db.ListLogbookPageRow) error {
+		for _, row := range rows {
+			for i, value := range logbookRecord(row) {
+				pdf.CellFormat(widths[i], 6, tr(truncate(value, widths[i])), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		return pdf.Error()
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=logbook_%s_%s.pdf", from.Format("20060102"), to.Format("20060102")))
+	ctx.Status(http.StatusOK)
+	if err := pdf.Output(ctx.Writer); err != nil {
+		log.Println("logbook PDF export aborted:", err)
+	}
+}
+
+// truncate shortens a value so it fits a table cell of the given width in mm
+// at the 8pt body font (roughly 1.6mm per character).
+func truncate(value string, width float64) string {
+	maxChars := int(width / 1.6)
+	runes := []rune(value)
+	if len(runes) <= maxChars {
+		return value
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
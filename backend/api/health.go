@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DebdipWritesCode/VisitorManagementSystem/db/migration"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds the database checks behind /readyz
+const readinessTimeout = 2 * time.Second
+
+// healthz is the liveness probe: the process is up and serving requests. It
+// does not touch the database, so an outage does not get pods restarted.
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readyz is the readiness probe: the database answers and has every
+// migration this build expects applied, cleanly.
+func (server *Server) readyz(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	// Driver errors can name the database host and user, so the detail only
+	// goes to the log
+	if err := server.store.Ping(checkCtx); err != nil {
+		log.Println("readiness check: cannot reach database:", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database is unreachable"})
+		return
+	}
+
+	version, dirty, err := server.store.MigrationVersion(checkCtx)
+	if err != nil {
+		log.Println("readiness check: cannot read migration version:", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "migration version is unavailable"})
+		return
+	}
+
+	expected := migration.Latest()
+	if dirty || version < expected {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":            "unavailable",
+			"error":             "migrations are not current",
+			"migration_version": version,
+			"expected_version":  expected,
+			"dirty":             dirty,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "migration_version": version})
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	// kioskDuplicateWindow treats repeat scans in the same direction within
+	// this window as one scan, e.g. the same badge read by two gate devices
+	kioskDuplicateWindow = 2 * time.Minute
+	// kioskClockSkew is how far ahead of server time a device clock may run
+	kioskClockSkew = 5 * time.Minute
+)
+
+// Reasons reported for scans the server refused to apply
+const (
+	kioskRejectInvalid             = "invalid"
+	kioskRejectUnknownQR           = "unknown_qr"
+	kioskRejectCancelled           = "appointment_cancelled"
+	kioskRejectOutsideValidity     = "outside_validity"
+	kioskRejectFutureTimestamp     = "future_timestamp"
+	kioskRejectDeclarationsPending = "declarations_pending"
+)
+
+// hashKioskKey returns the value stored for a kiosk API key; the key itself
+// is only shown once when the kiosk is registered.
+func hashKioskKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type registerKioskRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Gate string `json:"gate" binding:"max=50"`
+}
+
+type registerKioskResponse struct {
+	Kiosk  db.Kiosk `json:"kiosk"`
+	APIKey string   `json:"api_key"`
+}
+
+// registerKiosk registers a gate device and issues its API key.
+func (server *Server) registerKiosk(ctx *gin.Context) {
+	var req registerKioskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	key, err := util.RandomToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	kiosk, err := server.store.CreateKiosk(ctx, db.CreateKioskParams{
+		Name:       req.Name,
+		Gate:       sql.NullString{String: req.Gate, Valid: req.Gate != ""},
+		ApiKeyHash: hashKioskKey(key),
+		CreatedBy:  sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, registerKioskResponse{Kiosk: kiosk, APIKey: key})
+}
+
+func (server *Server) listKiosks(ctx *gin.Context) {
+	kiosks, err := server.store.ListKiosks(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, kiosks)
+}
+
+type updateKioskActiveRequest struct {
+	ID     int64 `json:"id" binding:"required,min=1"`
+	Active bool  `json:"is_active"`
+}
+
+// updateKioskActive enables or revokes a kiosk, e.g. when a tablet is lost.
+func (server *Server) updateKioskActive(ctx *gin.Context) {
+	var req updateKioskActiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	kiosk, err := server.store.UpdateKioskActive(ctx, db.UpdateKioskActiveParams{
+		ID:       int32(req.ID),
+		IsActive: req.Active,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, kiosk)
+}
+
+type kioskManifestRequest struct {
+	Date string `form:"date"` // Optional — defaults to today
+}
+
+type kioskManifestResponse struct {
+	KioskID      int32                     `json:"kiosk_id"`
+	Gate         string                    `json:"gate"`
+	Date         string                    `json:"date"`
+	GeneratedAt  time.Time                 `json:"generated_at"`
+	Appointments []db.ListKioskManifestRow `json:"appointments"`
+}
+
+// getKioskManifest returns the day's valid QR tokens with a visitor summary
+// for each, so the kiosk can validate scans while it is offline.
+func (server *Server) getKioskManifest(ctx *gin.Context) {
+	var req kioskManifestRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// "Today" is the site's date; appointment dates are stored without a zone
+	now := time.Now()
+	today := now.In(server.location)
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid date format, use YYYY-MM-DD")))
+			return
+		}
+		date = parsed
+	}
+
+	kiosk := authKiosk(ctx)
+	appointments, err := server.store.ListKioskManifest(ctx, date)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, kioskManifestResponse{
+		KioskID:      kiosk.ID,
+		Gate:         kiosk.Gate.String,
+		Date:         date.Format("2006-01-02"),
+		GeneratedAt:  now,
+		Appointments: appointments,
+	})
+}
+
+type kioskScan struct {
+	ClientEventID string    `json:"client_event_id"`
+	QRCode        string    `json:"qr_code"`
+	Direction     string    `json:"direction"`
+	ScannedAt     time.Time `json:"scanned_at"`
+	ScannedBy     int64     `json:"scanned_by"` // Optional — the guard on duty, who must be an admin
+}
+
+type kioskSyncRequest struct {
+	Events []kioskScan `json:"events" binding:"required,min=1,max=500"`
+}
+
+type kioskScanResult struct {
+	ClientEventID string `json:"client_event_id"`
+	Status        string `json:"status"` // applied, duplicate or rejected
+	Reason        string `json:"reason,omitempty"`
+	EventID       int32  `json:"event_id,omitempty"`
+}
+
+type kioskSyncResponse struct {
+	Applied    int               `json:"applied"`
+	Duplicates int               `json:"duplicates"`
+	Rejected   int               `json:"rejected"`
+	Results    []kioskScanResult `json:"results"`
+}
+
+// syncKioskEvents applies a batch of scans a kiosk recorded while offline.
+// Scans are applied in device-time order (ties broken by client event id), so
+// the outcome does not depend on upload order. Each scan is reported as
+// applied, duplicate or rejected; a rejected scan never blocks the rest.
+func (server *Server) syncKioskEvents(ctx *gin.Context) {
+	var req kioskSyncRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	kiosk := authKiosk(ctx)
+
+	sort.SliceStable(req.Events, func(i, j int) bool {
+		a, b := req.Events[i], req.Events[j]
+		if !a.ScannedAt.Equal(b.ScannedAt) {
+			return a.ScannedAt.Before(b.ScannedAt)
+		}
+		return a.ClientEventID < b.ClientEventID
+	})
+
+	rsp := kioskSyncResponse{Results: make([]kioskScanResult, 0, len(req.Events))}
+	seen := make(map[string]bool, len(req.Events))
+	guards := make(map[int64]bool)
+
+	for _, scan := range req.Events {
+		result := kioskScanResult{ClientEventID: scan.ClientEventID, Status: "duplicate"}
+		if scan.ClientEventID == "" || !seen[scan.ClientEventID] {
+			seen[scan.ClientEventID] = true
+
+			var err error
+			result, err = server.applyKioskScan(ctx, kiosk, scan, guards)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+				return
+			}
+		}
+
+		switch result.Status {
+		case "applied":
+			rsp.Applied++
+		case "duplicate":
+			rsp.Duplicates++
+		default:
+			rsp.Rejected++
+		}
+		rsp.Results = append(rsp.Results, result)
+	}
+
+	if err := server.store.TouchKioskSync(ctx, kiosk.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// applyKioskScan validates a single uploaded scan and records it. Problems
+// with the scan itself come back as a rejected result; only server failures
+// are returned as errors. guards caches which scanned_by ids name an admin
+// for the rest of the batch.
+func (server *Server) applyKioskScan(ctx *gin.Context, kiosk db.Kiosk, scan kioskScan, guards map[int64]bool) (kioskScanResult, error) {
+	result := kioskScanResult{ClientEventID: scan.ClientEventID, Status: "rejected"}
+
+	if scan.ClientEventID == "" || len(scan.ClientEventID) > 64 || scan.QRCode == "" ||
+		(scan.Direction != "in" && scan.Direction != "out") || scan.ScannedAt.IsZero() {
+		result.Reason = kioskRejectInvalid
+		return result, nil
+	}
+	if scan.ScannedAt.After(time.Now().Add(kioskClockSkew)) {
+		result.Reason = kioskRejectFutureTimestamp
+		return result, nil
+	}
+	if scan.ScannedBy != 0 {
+		isGuard, err := server.isKioskGuard(ctx, scan.ScannedBy, guards)
+		if err != nil {
+			return result, err
+		}
+		if !isGuard {
+			result.Reason = kioskRejectInvalid
+			return result, nil
+		}
+	}
+
+	appointment, err := server.store.GetAppointmentByQRCode(ctx, sql.NullString{String: scan.QRCode, Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			result.Reason = kioskRejectUnknownQR
+			return result, nil
+		}
+		return result, err
+	}
+	if appointment.Status.String == "cancelled" {
+		result.Reason = kioskRejectCancelled
+		return result, nil
+	}
+	if scan.Direction == "in" && scan.ScannedAt.In(server.location).Format("2006-01-02") != appointment.AppointmentDate.Format("2006-01-02") {
+		result.Reason = kioskRejectOutsideValidity
+		return result, nil
+	}
+
+	arg := db.CreateAccessEventParams{
+		AppointmentID: appointment.ID,
+		Direction:     scan.Direction,
+		ScannedAt:     scan.ScannedAt,
+		Gate:          kiosk.Gate,
+		ScannedBy:     sql.NullInt32{Int32: int32(scan.ScannedBy), Valid: scan.ScannedBy > 0},
+		KioskID:       sql.NullInt32{Int32: kiosk.ID, Valid: true},
+		ClientEventID: sql.NullString{String: scan.ClientEventID, Valid: true},
+	}
+
+	entries, err := server.checkInNotifications(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	applied, err := server.store.ApplyKioskScanTx(ctx, db.ApplyKioskScanTxParams{
+		RecordAccessEventTxParams: db.RecordAccessEventTxParams{
+			CreateAccessEventParams: arg,
+			Notifications:           entries,
+		},
+		DuplicateWindow: kioskDuplicateWindow,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDeclarationsPending) {
+			result.Reason = kioskRejectDeclarationsPending
+			return result, nil
+		}
+		if errors.Is(err, db.ErrAppointmentCancelled) {
+			result.Reason = kioskRejectCancelled
+			return result, nil
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				// Another upload of the same batch won the race
+				result.Status = "duplicate"
+				return result, nil
+			case "foreign_key_violation":
+				// The guard was deleted after isKioskGuard checked them
+				result.Reason = kioskRejectInvalid
+				return result, nil
+			}
+		}
+		return result, err
+	}
+
+	result.EventID = applied.Event.ID
+	result.Status = "applied"
+	if applied.Duplicate {
+		result.Status = "duplicate"
+	}
+	return result, nil
+}
+
+// isKioskGuard reports whether id names an admin, the only users who staff
+// the gates. Kiosks sign in with a device key rather than a user token, so
+// the guard a device reports has to be checked against the users table.
+func (server *Server) isKioskGuard(ctx *gin.Context, id int64, guards map[int64]bool) (bool, error) {
+	if isGuard, ok := guards[id]; ok {
+		return isGuard, nil
+	}
+	if id < 0 || id > math.MaxInt32 {
+		guards[id] = false
+		return false, nil
+	}
+
+	user, err := server.store.GetUserByID(ctx, int32(id))
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	guards[id] = err == nil && user.Role.String == "admin"
+	return guards[id], nil
+}
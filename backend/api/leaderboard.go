@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// leaderboardCache keeps recent leaderboard results in memory so the Book
+// Appointment page doesn't aggregate the appointments table on every load.
+type leaderboardCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[db.GetLeaderboardParams]leaderboardCacheEntry
+}
+
+type leaderboardCacheEntry struct {
+	rows      []db.GetLeaderboardRow
+	expiresAt time.Time
+}
+
+func newLeaderboardCache(ttl time.Duration) *leaderboardCache {
+	return &leaderboardCache{
+		ttl:     ttl,
+		entries: make(map[db.GetLeaderboardParams]leaderboardCacheEntry),
+	}
+}
+
+func (cache *leaderboardCache) get(key db.GetLeaderboardParams) ([]db.GetLeaderboardRow, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(cache.entries, key)
+		return nil, false
+	}
+	return entry.rows, true
+}
+
+func (cache *leaderboardCache) set(key db.GetLeaderboardParams, rows []db.GetLeaderboardRow) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Drop stale entries so keys from previous days don't pile up
+	now := time.Now()
+	for k, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, k)
+		}
+	}
+
+	cache.entries[key] = leaderboardCacheEntry{
+		rows:      rows,
+		expiresAt: time.Now().Add(cache.ttl),
+	}
+}
+
+type getLeaderboardRequest struct {
+	Window          int32  `form:"window" binding:"omitempty,oneof=7 30 90"`
+	RankBy          string `form:"rank_by" binding:"omitempty,oneof=hosted visited"`
+	ExcludeAdmins   *bool  `form:"exclude_admins"`
+	ExcludeInactive *bool  `form:"exclude_inactive"`
+	Limit           int32  `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// getLeaderboard ranks users by completed appointments within a recent window.
+// Defaults: last 30 days, ranked by hosted, admins and inactive users excluded,
+// top 10.
+func (server *Server) getLeaderboard(ctx *gin.Context) {
+	req := getLeaderboardRequest{
+		Window: 30,
+		RankBy: "hosted",
+		Limit:  10,
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Days rather than timestamps so cache keys stay stable for the whole day
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	arg := db.GetLeaderboardParams{
+		RankBy:          req.RankBy,
+		Since:           today.AddDate(0, 0, -int(req.Window)),
+		ExcludeAdmins:   req.ExcludeAdmins == nil || *req.ExcludeAdmins,
+		ExcludeInactive: req.ExcludeInactive == nil || *req.ExcludeInactive,
+		MaxResults:      req.Limit,
+	}
+
+	if rows, ok := server.leaderboard.get(arg); ok {
+		ctx.JSON(http.StatusOK, rows)
+		return
+	}
+
+	rows, err := server.store.GetLeaderboard(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.leaderboard.set(arg, rows)
+	ctx.JSON(http.StatusOK, rows)
+}
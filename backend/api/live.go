@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/live"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// liveHeartbeat keeps idle streams from being closed by proxies
+	liveHeartbeat = 25 * time.Second
+	// streamTicketDuration is how long a stream ticket may wait to be used
+	streamTicketDuration = 30 * time.Second
+)
+
+type streamTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createStreamTicket issues a single-use ticket that opens the live stream
+// as the caller, for EventSource clients that cannot send the bearer token:
+// GET /live/appointments?ticket=...
+func (server *Server) createStreamTicket(ctx *gin.Context) {
+	if err := server.store.DeleteExpiredStreamTickets(ctx); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ticket, err := util.RandomToken(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	expiresAt := time.Now().Add(streamTicketDuration)
+	if err := server.store.CreateStreamTicket(ctx, db.CreateStreamTicketParams{
+		TicketHash: util.HashToken(ticket),
+		UserID:     authPayload(ctx).UserID,
+		ExpiresAt:  expiresAt,
+	}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, streamTicketResponse{Ticket: ticket, ExpiresAt: expiresAt})
+}
+
+type streamLiveEventsRequest struct {
+	UserID int64 `form:"user_id" binding:"omitempty,min=1"`
+}
+
+// streamLiveEvents is a Server-Sent Events stream of today's bookings,
+// status changes, check-ins and check-outs. Each SSE event is named after
+// the event (appointment.created, visitor.checked_in, ...) and carries a
+// live.Event as JSON. A "resync" event means some events may have been
+// missed and the dashboard should reload.
+//
+// Admins see the whole site unless they pass user_id; everyone else only
+// sees appointments they host or visit.
+func (server *Server) streamLiveEvents(ctx *gin.Context) {
+	var req streamLiveEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	userID := int32(req.UserID)
+	if !payload.IsAdmin() {
+		if userID != 0 && userID != payload.UserID {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("you can only follow your own appointments")))
+			return
+		}
+		userID = payload.UserID
+	}
+
+	// The stream outlives SERVER_WRITE_TIMEOUT, which is meant for ordinary
+	// requests
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	events, unsubscribe := server.hub.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(liveHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind, or the server is shutting down;
+				// either way the client reconnects and reloads
+				return false
+			}
+			if server.wantsLiveEvent(event, userID) {
+				ctx.SSEvent(event.Event, event)
+			}
+			return true
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			return true
+		}
+	})
+}
+
+// wantsLiveEvent filters events down to today's appointments for the user,
+// or the whole site when userID is zero. Reschedules always pass, so an
+// appointment moved off today disappears from the dashboard too.
+func (server *Server) wantsLiveEvent(event live.Event, userID int32) bool {
+	if event.Event == live.EventResync {
+		return true
+	}
+	if userID != 0 && event.HostID != userID && event.VisitorID != userID {
+		return false
+	}
+
+	today := time.Now().In(server.location).Format("2006-01-02")
+	return event.AppointmentDate == today || event.Event == db.WebhookAppointmentRescheduled
+}
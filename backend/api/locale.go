@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	localizerContextKey = "localizer"
+	localeContextKey    = "locale"
+)
+
+// localizer translates the API's error messages for each request.
+type localizer struct {
+	catalog *notifications.Catalog
+	store   db.Store
+}
+
+// localeMiddleware makes errorResponse write errors in the caller's language.
+func localeMiddleware(catalog *notifications.Catalog, store db.Store) gin.HandlerFunc {
+	l := &localizer{catalog: catalog, store: store}
+	return func(ctx *gin.Context) {
+		ctx.Set(localizerContextKey, l)
+		ctx.Next()
+	}
+}
+
+// locale is the signed-in caller's preferred_locale, else the best match for
+// Accept-Language, else DEFAULT_LOCALE. It is only worked out once an error
+// needs it, and then kept for the rest of the request.
+func (l *localizer) locale(ctx *gin.Context) string {
+	if locale := ctx.GetString(localeContextKey); locale != "" {
+		return locale
+	}
+
+	locale := l.catalog.DefaultLocale()
+	if tag := acceptLanguage(ctx.GetHeader("Accept-Language"), l.catalog.Locales()); tag != "" {
+		locale = tag
+	}
+	if payload := optionalAuthPayload(ctx); payload != nil {
+		if user, err := l.store.GetUserByID(ctx, payload.UserID); err == nil && user.PreferredLocale.Valid {
+			locale = user.PreferredLocale.String
+		}
+	}
+
+	ctx.Set(localeContextKey, locale)
+	return locale
+}
+
+func (l *localizer) translate(ctx *gin.Context, err error) string {
+	locale := l.locale(ctx)
+	if e, ok := err.(*apiError); ok {
+		return fmt.Sprintf(l.catalog.Error(e.format, locale), e.args...)
+	}
+	return l.catalog.Error(err.Error(), locale)
+}
+
+// acceptLanguage returns the Accept-Language tag with the highest weight that
+// one of the supported locales covers, or "" if there is none.
+func acceptLanguage(header string, supported []string) string {
+	best, bestWeight := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				weight = parsed
+			}
+		}
+		if weight <= bestWeight {
+			continue
+		}
+
+		normalized, err := notifications.NormalizeLocale(tag)
+		if err != nil {
+			continue
+		}
+		base, _, _ := strings.Cut(normalized, "-")
+		for _, locale := range supported {
+			if locale == normalized || locale == base {
+				best, bestWeight = normalized, weight
+				break
+			}
+		}
+	}
+	return best
+}
+
+// apiError is an error whose format string is translated before its
+// arguments are filled in, for messages that carry values.
+type apiError struct {
+	format string
+	args   []any
+}
+
+func apiErrorf(format string, args ...any) error {
+	return &apiError{format: format, args: args}
+}
+
+func (err *apiError) Error() string {
+	return fmt.Sprintf(err.format, err.args...)
+}
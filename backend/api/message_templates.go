@@ -0,0 +1,154 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/gin-gonic/gin"
+)
+
+type messageTemplateURI struct {
+	Locale string `uri:"locale" binding:"required,max=35"`
+	Key    string `uri:"key" binding:"required,max=100"`
+}
+
+type listMessageTemplatesResponse struct {
+	DefaultLocale string               `json:"default_locale"`
+	Locales       []string             `json:"locales"` // Built-in translations
+	Keys          []string             `json:"keys"`
+	Overrides     []db.MessageTemplate `json:"overrides"`
+}
+
+// listMessageTemplates lists the built-in locales, every message key and the
+// overrides admins have saved.
+func (server *Server) listMessageTemplates(ctx *gin.Context) {
+	overrides, err := server.store.ListMessageTemplates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listMessageTemplatesResponse{
+		DefaultLocale: server.catalog.DefaultLocale(),
+		Locales:       server.catalog.Locales(),
+		Keys:          server.catalog.Keys(),
+		Overrides:     overrides,
+	})
+}
+
+type effectiveMessageTemplate struct {
+	Key     string `json:"key"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Source  string `json:"source"` // e.g. override:es or builtin:en
+}
+
+// getMessageTemplates returns the template used for every message in a
+// locale, and where it comes from after falling back.
+func (server *Server) getMessageTemplates(ctx *gin.Context) {
+	locale, err := notifications.NormalizeLocale(ctx.Param("locale"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	templates := []effectiveMessageTemplate{}
+	for _, key := range server.catalog.Keys() {
+		message, source, ok := server.catalog.Lookup(key, locale)
+		if !ok {
+			continue
+		}
+		templates = append(templates, effectiveMessageTemplate{
+			Key:     key,
+			Subject: message.Subject,
+			Body:    message.Body,
+			Source:  source,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"locale": locale, "templates": templates})
+}
+
+type putMessageTemplateRequest struct {
+	Subject string `json:"subject" binding:"max=255"`
+	Body    string `json:"body" binding:"required,max=4000"`
+}
+
+// putMessageTemplate saves an override for one message in one locale. The
+// template is test-rendered first so a typo cannot break delivery.
+func (server *Server) putMessageTemplate(ctx *gin.Context) {
+	var uri messageTemplateURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	var req putMessageTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	locale, err := notifications.NormalizeLocale(uri.Locale)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if err := server.catalog.Validate(uri.Key, notifications.Message{Subject: req.Subject, Body: req.Body}); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	template, err := server.store.UpsertMessageTemplate(ctx, db.UpsertMessageTemplateParams{
+		Locale:    locale,
+		Key:       uri.Key,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		UpdatedBy: sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.reloadCatalog(ctx)
+	ctx.JSON(http.StatusOK, template)
+}
+
+// deleteMessageTemplate removes an override, so the message falls back to
+// the built-in translation.
+func (server *Server) deleteMessageTemplate(ctx *gin.Context) {
+	var uri messageTemplateURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	locale, err := notifications.NormalizeLocale(uri.Locale)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	rows, err := server.store.DeleteMessageTemplate(ctx, db.DeleteMessageTemplateParams{Locale: locale, Key: uri.Key})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, apiErrorf("no %s override for %s", locale, uri.Key)))
+		return
+	}
+
+	server.reloadCatalog(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"message": "message template override deleted"})
+}
+
+// reloadCatalog makes a change take effect here at once; other replicas pick
+// it up on their next refresh.
+func (server *Server) reloadCatalog(ctx *gin.Context) {
+	if err := server.catalog.Reload(ctx); err != nil {
+		log.Println("cannot reload message templates:", err)
+	}
+}
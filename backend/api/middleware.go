@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/token"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+	kioskKeyHeaderKey       = "x-kiosk-key"
+	kioskContextKey         = "kiosk"
+)
+
+// authMiddleware creates a gin middleware for authorization
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, err := bearerPayload(ctx, tokenMaker)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
+
+// optionalAuthMiddleware stores the token payload when the caller sends a
+// valid bearer token and lets anonymous callers through, for public routes
+// that show more to signed-in staff.
+func optionalAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if payload, err := bearerPayload(ctx, tokenMaker); err == nil {
+			ctx.Set(authorizationPayloadKey, payload)
+		}
+		ctx.Next()
+	}
+}
+
+// bearerPayload verifies the bearer token in the authorization header
+func bearerPayload(ctx *gin.Context, tokenMaker token.Maker) (*token.Payload, error) {
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+	if len(authorizationHeader) == 0 {
+		return nil, errors.New("authorization header is not provided")
+	}
+
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) < 2 {
+		return nil, errors.New("invalid authorization header format")
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		return nil, apiErrorf("unsupported authorization type %s", authorizationType)
+	}
+
+	return tokenMaker.VerifyToken(fields[1])
+}
+
+// streamAuthMiddleware authenticates the live stream either with a bearer
+// token or with a single-use ticket from POST /live/tickets passed as the
+// ticket query parameter, for clients such as the browser's EventSource that
+// cannot set headers.
+func streamAuthMiddleware(store db.Store, tokenMaker token.Maker) gin.HandlerFunc {
+	auth := authMiddleware(tokenMaker)
+	return func(ctx *gin.Context) {
+		ticket := ctx.Query("ticket")
+		if ticket == "" {
+			auth(ctx)
+			return
+		}
+
+		owner, err := store.RedeemStreamTicket(ctx, util.HashToken(ticket))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, errors.New("stream ticket is invalid or has been used")))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, token.NewPayload(owner.ID, owner.Role.String, streamTicketDuration))
+		ctx.Next()
+	}
+}
+
+// logFormatter is gin's default request log line without the query string,
+// which may carry credentials such as stream tickets.
+func logFormatter(param gin.LogFormatterParams) string {
+	path, _, _ := strings.Cut(param.Path, "?")
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		path,
+		param.ErrorMessage,
+	)
+}
+
+// authPayload returns the token payload stored by authMiddleware
+func authPayload(ctx *gin.Context) *token.Payload {
+	return ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+}
+
+// optionalAuthPayload returns the token payload stored by
+// optionalAuthMiddleware, or nil for an anonymous caller
+func optionalAuthPayload(ctx *gin.Context) *token.Payload {
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		return payload.(*token.Payload)
+	}
+	return nil
+}
+
+// canSeeHostNotes reports whether the caller is staff for an appointment:
+// an admin or its host. Host notes are hidden from everyone else.
+func canSeeHostNotes(payload *token.Payload, hostID int32) bool {
+	return payload != nil && (payload.IsAdmin() || payload.UserID == hostID)
+}
+
+// canSeeContactSettings reports whether the caller may see how a user is
+// contacted: the user themselves or an admin.
+func canSeeContactSettings(payload *token.Payload, userID int32) bool {
+	return payload != nil && (payload.IsAdmin() || payload.UserID == userID)
+}
+
+// adminMiddleware rejects callers whose token does not carry the admin role.
+// It must run after authMiddleware.
+func adminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !authPayload(ctx).IsAdmin() {
+			err := errors.New("admin access required")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ctx, err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// kioskMiddleware authenticates a registered gate kiosk by the API key it was
+// issued, so the device can sync without a guard being logged in.
+func kioskMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(kioskKeyHeaderKey)
+		if len(key) == 0 {
+			err := errors.New("kiosk key is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+
+		kiosk, err := store.GetKioskByKeyHash(ctx, hashKioskKey(key))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, errors.New("invalid kiosk key")))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		if !kiosk.IsActive {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ctx, errors.New("kiosk has been deactivated")))
+			return
+		}
+
+		ctx.Set(kioskContextKey, kiosk)
+		ctx.Next()
+	}
+}
+
+// authKiosk returns the kiosk stored by kioskMiddleware
+func authKiosk(ctx *gin.Context) db.Kiosk {
+	return ctx.MustGet(kioskContextKey).(db.Kiosk)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type listInboxRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"` // Optional — defaults to 50
+}
+
+// listInbox returns the caller's in-app notifications, newest first.
+func (server *Server) listInbox(ctx *gin.Context) {
+	var req listInboxRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	notifications, err := server.store.ListInboxNotifications(ctx, db.ListInboxNotificationsParams{
+		UserID: sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// listAppointmentNotifications shows every notification queued for an
+// appointment with its delivery status, attempts and last error.
+func (server *Server) listAppointmentNotifications(ctx *gin.Context) {
+	var req getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	notifications, err := server.store.ListNotificationsByAppointment(ctx, sql.NullInt32{Int32: int32(req.ID), Valid: true})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+type retryNotificationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// retryNotification puts a dead-lettered notification back on the queue
+// with a fresh set of attempts.
+func (server *Server) retryNotification(ctx *gin.Context) {
+	var req retryNotificationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	notification, err := server.store.RequeueNotification(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no dead-lettered notification found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notification)
+}
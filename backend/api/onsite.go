@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listOnsiteVisitors returns everyone who is checked in and hasn't checked out.
+func (server *Server) listOnsiteVisitors(ctx *gin.Context) {
+	visitors, err := server.store.ListOnsiteVisitors(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"count":    len(visitors),
+		"visitors": visitors,
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -20,7 +21,7 @@ type verifyOTPRequest struct {
 func (server *Server) sendOTP(ctx *gin.Context) {
 	var req sendOTPRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -30,7 +31,7 @@ func (server *Server) sendOTP(ctx *gin.Context) {
 		log.Printf("❌ Failed to send OTP to %s: %v\n", req.PhoneNumber, err)
 
 		// Send generic error response to frontend
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send OTP"})
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, errors.New("Failed to send OTP")))
 		return
 	}
 
@@ -40,13 +41,13 @@ func (server *Server) sendOTP(ctx *gin.Context) {
 func (server *Server) verifyOTP(ctx *gin.Context) {
 	var req verifyOTPRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	ok, err := util.CheckPhoneVerification(req.PhoneNumber, req.OTPCode)
 	if err != nil || !ok {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid OTP"})
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, errors.New("Invalid OTP")))
 		return
 	}
 
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type listOverstaysRequest struct {
+	Active *bool  `form:"active"` // Optional — true for ongoing, false for resolved, omit for both
+	From   string `form:"from"`   // Optional — defaults to 30 days ago
+	To     string `form:"to"`     // Optional — defaults to today
+}
+
+type listOverstaysResponse struct {
+	Summary   db.GetOverstaySummaryRow `json:"summary"`
+	Overstays []db.ListOverstaysRow    `json:"overstays"`
+}
+
+// listOverstays returns flagged overstays by scheduled end date, with how
+// long each one lasted (or has lasted so far) and totals for reporting.
+func (server *Server) listOverstays(ctx *gin.Context) {
+	var req listOverstaysRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		parsed, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid to date format, use YYYY-MM-DD")))
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if req.From != "" {
+		parsed, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid from date format, use YYYY-MM-DD")))
+			return
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("to date must not be before from date")))
+		return
+	}
+
+	arg := db.ListOverstaysParams{FromDate: from, ToDate: to}
+	if req.Active != nil {
+		arg.Active = sql.NullBool{Bool: *req.Active, Valid: true}
+	}
+
+	overstays, err := server.store.ListOverstays(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	summary, err := server.store.GetOverstaySummary(ctx, db.GetOverstaySummaryParams{FromDate: from, ToDate: to})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listOverstaysResponse{Summary: summary, Overstays: overstays})
+}
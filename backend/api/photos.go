@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/storage"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPhotoSize bounds uploads; a gate camera still is well under this
+const maxPhotoSize = 5 << 20
+
+// photoExtensions lists the accepted image types by their sniffed content type
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type visitorPhotoResponse struct {
+	db.VisitorPhoto
+	URL string `json:"url"`
+}
+
+// uploadVisitorPhoto stores a photo taken at the gate for the appointment's
+// visitor. It expects a multipart form with the image in the "photo" field.
+func (server *Server) uploadVisitorPhoto(ctx *gin.Context) {
+	var req getAppointmentUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	appointment, err := server.store.GetAppointmentByID(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if appointment.Status.String == "cancelled" {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, fmt.Errorf("cannot capture a photo for a cancelled appointment")))
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxPhotoSize+64<<10)
+	file, header, err := ctx.Request.FormFile("photo")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, apiErrorf("photo file is required and must be at most %d MB", maxPhotoSize>>20)))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxPhotoSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(ctx, apiErrorf("photo must be at most %d MB", maxPhotoSize>>20)))
+		return
+	}
+
+	// Trust the bytes rather than the client's Content-Type header
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		ctx.JSON(http.StatusUnsupportedMediaType, errorResponse(ctx, fmt.Errorf("photo must be a JPEG, PNG or WebP image")))
+		return
+	}
+
+	suffix, err := util.RandomToken(8)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	key := fmt.Sprintf("visitors/%d/%d-%s%s", appointment.VisitorID, appointment.ID, suffix, ext)
+
+	body := io.MultiReader(bytes.NewReader(head[:n]), file)
+	if err := server.photos.Put(ctx, key, body, header.Size, contentType); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, fmt.Errorf("cannot store photo: %w", err)))
+		return
+	}
+
+	photo, err := server.store.CreateVisitorPhoto(ctx, db.CreateVisitorPhotoParams{
+		UserID:        appointment.VisitorID,
+		AppointmentID: appointment.ID,
+		StorageKey:    key,
+		ContentType:   contentType,
+		SizeBytes:     int32(header.Size),
+		CapturedBy:    sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+	})
+	if err != nil {
+		if err := server.photos.Delete(ctx, key); err != nil {
+			log.Printf("cannot remove orphaned photo %s: %v\n", key, err)
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	url, err := server.photos.SignedURL(ctx, key, server.config.PhotoURLTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, visitorPhotoResponse{VisitorPhoto: photo, URL: url})
+}
+
+// visitorPhotoURL returns a signed URL to the photo guards should compare
+// against, or nil when the visitor has never been photographed.
+func (server *Server) visitorPhotoURL(ctx *gin.Context, visitorID, appointmentID int32) (*string, error) {
+	photo, err := server.store.GetLatestVisitorPhoto(ctx, db.GetLatestVisitorPhotoParams{
+		UserID:        visitorID,
+		AppointmentID: appointmentID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	url, err := server.photos.SignedURL(ctx, photo.StorageKey, server.config.PhotoURLTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &url, nil
+}
+
+// purgeVisitorPhotos removes photos from storage and then their rows, so
+// the user or appointment they belong to can be deleted without orphaning
+// files. It stops at the first failure, leaving the rest to retry.
+func (server *Server) purgeVisitorPhotos(ctx *gin.Context, photos []db.VisitorPhoto) error {
+	for _, photo := range photos {
+		if err := server.photos.Delete(ctx, photo.StorageKey); err != nil {
+			return fmt.Errorf("cannot delete visitor photo %d: %w", photo.ID, err)
+		}
+		if err := server.store.DeleteVisitorPhoto(ctx, photo.ID); err != nil {
+			return fmt.Errorf("cannot delete visitor photo record %d: %w", photo.ID, err)
+		}
+	}
+	return nil
+}
+
+// serveVisitorPhoto serves photos kept in local storage through the signed
+// URLs handed out by the API. S3-compatible backends serve their own URLs.
+func (server *Server) serveVisitorPhoto(ctx *gin.Context) {
+	local, ok := server.photos.(*storage.LocalStorage)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("photos are not served by this server")))
+		return
+	}
+
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	file, err := local.Open(key, ctx.Query("expires"), ctx.Query("sig"))
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSignature) {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+			return
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("photo not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), file)
+}
@@ -2,26 +2,55 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/live"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/storage"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/token"
 	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
-	config util.Config
-	store  db.Store
-	router *gin.Engine
+	config      util.Config
+	store       db.Store
+	tokenMaker  token.Maker
+	leaderboard *leaderboardCache
+	photos      storage.Storage
+	outbox      *notifications.Outbox
+	catalog     *notifications.Catalog
+	location    *time.Location // SITE_TIMEZONE, which appointment times are in
+	hub         *live.Hub
+	router      *gin.Engine
 }
 
 // NewServer creates a new HTTP server and sets up routing.
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store, photos storage.Storage, hub *live.Hub, catalog *notifications.Catalog) (*Server, error) {
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	location, err := time.LoadLocation(config.SiteTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SITE_TIMEZONE: %w", err)
+	}
+
 	fmt.Println("Initializing Twilio client...")
 	util.InitTwilio()
 
 	server := &Server{
-		config: config,
-		store:  store,
+		config:      config,
+		store:       store,
+		tokenMaker:  tokenMaker,
+		leaderboard: newLeaderboardCache(config.LeaderboardCacheTTL),
+		photos:      photos,
+		outbox:      notifications.NewOutbox(config, catalog),
+		catalog:     catalog,
+		location:    location,
+		hub:         hub,
 	}
 	server.setupRouter()
 	return server, nil
@@ -33,53 +62,165 @@ func (server *Server) GetRouter() *gin.Engine {
 
 // setupRouter initializes the Gin router with all routes.
 func (server *Server) setupRouter() {
-	router := gin.Default()
+	// Probes hit every few seconds, so they are left out of the request log
+	router := gin.New()
+	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: logFormatter,
+		SkipPaths: []string{"/healthz", "/readyz"},
+	}), gin.Recovery(), localeMiddleware(server.catalog, server.store))
+
+	// Kubernetes probes
+	router.GET("/healthz", server.healthz)
+	router.GET("/readyz", server.readyz)
 
-	router.GET("/ping", func(ctx *gin.Context) {
-		ctx.JSON(200, gin.H{"message": "pong"})
-	})
+	// authRoutes need a bearer token from /auth/login; adminRoutes also need the admin role
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	adminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), adminMiddleware())
+	// optionalAuthRoutes are public but show staff-only fields to admins and hosts,
+	// and users' contact settings to themselves and admins
+	optionalAuthRoutes := router.Group("/").Use(optionalAuthMiddleware(server.tokenMaker))
 
 	// Appointment routes
 	router.POST("/appointments", server.createAppointment)
-	router.GET("/appointments/:id", server.getAppointmentByID)
+	authRoutes.GET("/appointments", server.listAppointments)
+	optionalAuthRoutes.GET("/appointments/:id", server.getAppointmentByID)
 	router.GET("/appointments/visitor/:id", server.listAppointmentsByVisitor)
-	router.GET("/appointments/host/:id", server.listAppointmentsByHost)
-	router.GET("/appointments/date", server.listAppointmentsByDate)
-	router.GET("/appointments/qr/:qr_code", server.getAppointmentByQRCode)
-	router.PUT("/appointments/status", server.updateAppointmentStatus)
+	optionalAuthRoutes.GET("/appointments/host/:id", server.listAppointmentsByHost)
+	optionalAuthRoutes.GET("/appointments/date", server.listAppointmentsByDate)
+	optionalAuthRoutes.GET("/appointments/qr/:qr_code", server.getAppointmentByQRCode)
+	authRoutes.PUT("/appointments/status", server.updateAppointmentStatus)
 	router.GET("/users/:id/stats", server.getUserAppointmentStats)
 	router.DELETE("/appointments/:id", server.deleteAppointment)
-	router.POST("/appointments/:id/cancel", server.cancelAppointment)
+	authRoutes.POST("/appointments/:id/cancel", server.cancelAppointment)
+	authRoutes.POST("/appointments/:id/reschedule", server.rescheduleAppointment)
+	authRoutes.GET("/appointments/:id/qr.png", server.getAppointmentQRPNG)
+	authRoutes.GET("/appointments/:id/qr.svg", server.getAppointmentQRSVG)
+	authRoutes.GET("/appointments/:id/badge.pdf", server.getAppointmentBadgePDF)
+	adminRoutes.POST("/appointments/:id/photo", server.uploadVisitorPhoto)
+	router.GET("/photos/*key", server.serveVisitorPhoto)
+	authRoutes.GET("/appointments/:id/declarations", server.getAppointmentDeclarations)
+	authRoutes.POST("/appointments/:id/declarations", server.submitAppointmentDeclaration)
+	authRoutes.POST("/appointments/:id/checkin_replies", server.replyToCheckIn)
+	authRoutes.GET("/appointments/:id/checkin_replies", server.listCheckInReplies)
+
+	// Kiosk routes: admins register devices, devices sync with their API key
+	kioskRoutes := router.Group("/kiosk").Use(kioskMiddleware(server.store))
+	adminRoutes.POST("/kiosks", server.registerKiosk)
+	adminRoutes.GET("/kiosks", server.listKiosks)
+	adminRoutes.PUT("/kiosks/active", server.updateKioskActive)
+	kioskRoutes.GET("/manifest", server.getKioskManifest)
+	kioskRoutes.POST("/sync", server.syncKioskEvents)
+
+	// Declaration form routes
+	adminRoutes.POST("/declarations/forms", server.createDeclarationForm)
+	adminRoutes.GET("/declarations/forms", server.listDeclarationForms)
+	adminRoutes.PUT("/declarations/forms/:id", server.updateDeclarationForm)
+	adminRoutes.GET("/declarations/forms/:id/versions", server.listDeclarationVersions)
+	adminRoutes.POST("/declarations/forms/:id/versions", server.publishDeclarationVersion)
+
+	// Calendar routes
+	authRoutes.GET("/calendar", server.getCalendar)
+	authRoutes.GET("/calendar_feed", server.getCalendarFeed)
+	authRoutes.POST("/calendar_feed/rotate", server.rotateCalendarFeed)
+	router.GET("/calendar/:token", server.serveCalendarFeed)
+	authRoutes.GET("/calendar_imports", server.listCalendarImports)
+	authRoutes.POST("/calendar_imports", server.createCalendarImport)
+	authRoutes.POST("/calendar_imports/upload", server.uploadCalendarImport)
+	authRoutes.POST("/calendar_imports/:id/sync", server.resyncCalendarImport)
+	authRoutes.DELETE("/calendar_imports/:id", server.deleteCalendarImport)
+
+	// Waitlist routes
+	authRoutes.POST("/waitlist", server.joinWaitlist)
+	authRoutes.GET("/waitlist/host/:id", server.listWaitlistByHost)
+	authRoutes.GET("/waitlist/visitor/:id", server.listWaitlistByVisitor)
+	authRoutes.DELETE("/waitlist/:id", server.leaveWaitlist)
+	router.GET("/waitlist/offers/:token", server.getWaitlistOffer)
+	router.POST("/waitlist/offers/:token/claim", server.claimWaitlistOffer)
 
 	// User routes
 	router.POST("/users", server.createUser)
 	router.POST("/auth/signup", server.signupUser)
 	router.POST("/auth/login", server.loginUser)
-	router.GET("/users/:id", server.getUserByID)
-	router.GET("/users/phone/:phone_number", server.getUserByPhone)
-	router.GET("/users", server.listUsers)
-	router.PUT("/users/name", server.updateUserName)
-	router.PUT("/users/role", server.updateUserRole)
+	optionalAuthRoutes.GET("/users/:id", server.getUserByID)
+	optionalAuthRoutes.GET("/users/phone/:phone_number", server.getUserByPhone)
+	optionalAuthRoutes.GET("/users", server.listUsers)
+	optionalAuthRoutes.PUT("/users/name", server.updateUserName)
+	adminRoutes.PUT("/users/role", server.updateUserRole)
+	optionalAuthRoutes.PUT("/users/department", server.updateUserDepartment)
+	authRoutes.PUT("/users/email", server.updateUserEmail)
+	authRoutes.PUT("/users/notification_channel", server.updateUserNotificationChannel)
+	authRoutes.PUT("/users/preferred_locale", server.updateUserPreferredLocale)
+	adminRoutes.PUT("/users/active", server.updateUserActive)
 	router.DELETE("/users/:id", server.deleteUser)
-	router.GET("/users/search", server.getUsersByName)
+	optionalAuthRoutes.GET("/users/search", server.getUsersByName)
 
 	// User appointment stats
 	router.GET("/users/:id/appointments/hosted", server.getTotalAppointmentsHosted)
 	router.GET("/users/:id/appointments/visited", server.getTotalAppointmentsVisited)
 	router.GET("/users/popular", server.getTopPopularUsers)
+	router.GET("/users/leaderboard", server.getLeaderboard)
 
 	// Appointment Stats routes
-	router.POST("/appointment_stats", server.createAppointmentStats)
 	router.GET("/appointment_stats/:user_id", server.getAppointmentStatsByUserID)
-	router.PUT("/appointment_stats/increment", server.incrementAppointmentCount)
-	router.PUT("/appointment_stats/decrement", server.decrementAppointmentCount)
-	router.PUT("/appointment_stats/reset", server.resetAppointmentCount)
 	router.GET("/appointment_stats/popular", server.getTopPopularUsers)
-	router.DELETE("/appointment_stats/:user_id", server.deleteAppointmentStats)
+
+	// Appointment counters are derived from appointments; these are admin-only
+	// compatibility shims plus the reconciler
+	adminRoutes.POST("/appointment_stats", server.createAppointmentStats)
+	adminRoutes.DELETE("/appointment_stats/:user_id", server.deleteAppointmentStats)
+	adminRoutes.PUT("/appointment_stats/increment", server.incrementAppointmentCount)
+	adminRoutes.PUT("/appointment_stats/decrement", server.decrementAppointmentCount)
+	adminRoutes.PUT("/appointment_stats/reset", server.resetAppointmentCount)
+	adminRoutes.POST("/appointment_stats/reconcile", server.reconcileAppointmentCounts)
+
+	// Visit analytics routes
+	adminRoutes.GET("/analytics/volumes", server.getVisitVolumes)
+	adminRoutes.GET("/analytics/heatmap", server.getVisitHeatmap)
+	adminRoutes.GET("/analytics/rates", server.getVisitRates)
+	adminRoutes.GET("/analytics/durations", server.getVisitDurations)
+
+	// On-site roster and evacuation routes
+	adminRoutes.GET("/onsite", server.listOnsiteVisitors)
+	adminRoutes.GET("/overstays", server.listOverstays)
+	adminRoutes.POST("/evacuations", server.startEvacuation)
+	adminRoutes.GET("/evacuations", server.listEvacuations)
+	adminRoutes.GET("/evacuations/:id", server.getEvacuation)
+	adminRoutes.PUT("/evacuations/:id/roll/:entry_id", server.markEvacuationRollEntry)
+	adminRoutes.POST("/evacuations/:id/close", server.closeEvacuation)
+
+	// Live dashboard stream; EventSource cannot send headers, so it may also be
+	// opened with ?ticket= from POST /live/tickets
+	authRoutes.POST("/live/tickets", server.createStreamTicket)
+	router.GET("/live/appointments", streamAuthMiddleware(server.store, server.tokenMaker), server.streamLiveEvents)
+
+	// Notification delivery routes
+	authRoutes.GET("/notifications/inbox", server.listInbox)
+	adminRoutes.GET("/appointments/:id/notifications", server.listAppointmentNotifications)
+	adminRoutes.POST("/notifications/:id/retry", server.retryNotification)
+
+	// Localized message template routes
+	adminRoutes.GET("/message_templates", server.listMessageTemplates)
+	adminRoutes.GET("/message_templates/:locale", server.getMessageTemplates)
+	adminRoutes.PUT("/message_templates/:locale/:key", server.putMessageTemplate)
+	adminRoutes.DELETE("/message_templates/:locale/:key", server.deleteMessageTemplate)
+
+	// Webhook subscription routes
+	adminRoutes.POST("/webhooks", server.createWebhook)
+	adminRoutes.GET("/webhooks", server.listWebhooks)
+	adminRoutes.PUT("/webhooks/:id", server.updateWebhook)
+	adminRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	adminRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	adminRoutes.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", server.redeliverWebhook)
+
+	// Visitor logbook exports. The CSV is streamed for any range; the PDF is
+	// built in memory, so ranges over maxPDFExportDays get a 400.
+	adminRoutes.GET("/exports/logbook.csv", server.exportLogbookCSV)
+	adminRoutes.GET("/exports/logbook.pdf", server.exportLogbookPDF)
 
 	// Availability routes
 	router.POST("/availability", server.createAvailabilitySlot)
 	router.GET("/availability/:user_id", server.getAvailabilityByUser)
+	router.GET("/availability/:user_id/free", server.getFreeSlots)
 	router.PUT("/availability/status", server.updateAvailabilityStatus)
 	router.DELETE("/availability", server.deleteAvailabilitySlot)
 	router.DELETE("/availability/:user_id", server.deleteAvailabilityByUser)
@@ -88,12 +229,16 @@ func (server *Server) setupRouter() {
 	router.POST("/otp/send", server.sendOTP)
 	router.POST("/otp/verify", server.verifyOTP)
 
-	// Appointment Log routes
-	router.POST("/appointment_logs", server.createAppointmentLog)
+	// Appointment Log routes; scans are recorded by guards, who sign in as admins
+	adminRoutes.POST("/appointment_logs", server.createAppointmentLog)
 	router.GET("/appointment_logs/:appointment_id", server.getAppointmentLogByAppointmentID)
-	router.PUT("/appointment_logs/check_in", server.updateCheckInTime)
-	router.PUT("/appointment_logs/check_out", server.updateCheckOutTime)
-	router.DELETE("/appointment_logs", server.deleteAppointmentLog)
+	adminRoutes.PUT("/appointment_logs/check_in", server.updateCheckInTime)
+	adminRoutes.PUT("/appointment_logs/check_out", server.updateCheckOutTime)
+	adminRoutes.DELETE("/appointment_logs", server.deleteAppointmentLog)
+
+	// Access event routes (one row per gate scan)
+	adminRoutes.POST("/access_events", server.createAccessEvent)
+	router.GET("/access_events/:appointment_id", server.listAccessEvents)
 
 	server.router = router
 }
@@ -103,7 +248,11 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-// errorResponse standardizes error responses.
-func errorResponse(err error) gin.H {
+// errorResponse standardizes error responses, written in the caller's
+// language once localeMiddleware has run.
+func errorResponse(ctx *gin.Context, err error) gin.H {
+	if l, ok := ctx.Get(localizerContextKey); ok {
+		return gin.H{"error": l.(*localizer).translate(ctx, err)}
+	}
 	return gin.H{"error": err.Error()}
 }
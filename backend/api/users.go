@@ -2,26 +2,32 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+// New accounts are always plain users; only an admin can promote them
+// through PUT /users/role.
 type createUserRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required,e164"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
-	Role        string `json:"role" binding:"omitempty,oneof=admin user"`
+	Department  string `json:"department" binding:"max=50"`
+	Email       string `json:"email" binding:"omitempty,email,max=255"`
 }
 
 func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -29,16 +35,18 @@ func (server *Server) createUser(ctx *gin.Context) {
 		PhoneNumber: req.PhoneNumber,
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
-		Role:        sql.NullString{String: req.Role, Valid: req.Role != ""},
+		Role:        sql.NullString{String: "user", Valid: true},
+		Department:  sql.NullString{String: req.Department, Valid: req.Department != ""},
+		Email:       sql.NullString{String: req.Email, Valid: req.Email != ""},
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	user, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -51,13 +59,13 @@ func (server *Server) createUser(ctx *gin.Context) {
 
 			startTime, err := time.Parse(timeLayout, startStr)
 			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("failed to parse start time: %w", err)))
+				ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, fmt.Errorf("failed to parse start time: %w", err)))
 				return
 			}
 
 			endTime, err := time.Parse(timeLayout, endStr)
 			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("failed to parse end time: %w", err)))
+				ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, fmt.Errorf("failed to parse end time: %w", err)))
 				return
 			}
 
@@ -70,7 +78,7 @@ func (server *Server) createUser(ctx *gin.Context) {
 
 			_, err = server.store.CreateAvailabilitySlot(ctx, slot)
 			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("failed to create availability: %w", err)))
+				ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, fmt.Errorf("failed to create availability: %w", err)))
 				return
 			}
 		}
@@ -83,13 +91,14 @@ type signupUserRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required,e164"`
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
-	Role        string `json:"role" binding:"omitempty,oneof=admin user"`
+	Department  string `json:"department" binding:"max=50"`
+	Email       string `json:"email" binding:"omitempty,email,max=255"`
 }
 
 func (server *Server) signupUser(ctx *gin.Context) {
 	var req signupUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -97,44 +106,84 @@ func (server *Server) signupUser(ctx *gin.Context) {
 		PhoneNumber: req.PhoneNumber,
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
-		Role:        sql.NullString{String: req.Role, Valid: req.Role != ""},
+		Role:        sql.NullString{String: "user", Valid: true},
+		Department:  sql.NullString{String: req.Department, Valid: req.Department != ""},
+		Email:       sql.NullString{String: req.Email, Valid: req.Email != ""},
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	user, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, user)
 }
 
+// loginUserRequest needs the code sent by POST /otp/send, so knowing a phone
+// number is not enough to get a token for it.
 type loginUserRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required,e164"`
+	OTPCode     string `json:"otp_code" binding:"required"`
+}
+
+// loginUserResponse keeps the user fields at the top level so existing
+// clients can keep reading them, and adds the access token alongside.
+type loginUserResponse struct {
+	db.User
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
 }
 
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUserByPhone(ctx, req.PhoneNumber)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ok, err := util.CheckPhoneVerification(req.PhoneNumber, req.OTPCode)
+	if err != nil || !ok {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, errors.New("Invalid OTP")))
+		return
+	}
+
+	accessToken, payload, err := server.tokenMaker.CreateToken(user.ID, user.Role.String, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := loginUserResponse{
+		User:                 user,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: payload.ExpiredAt,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// hideContactSettings clears a user's email and notification preferences
+// unless the caller may see them. The user lookups are public, for booking.
+func hideContactSettings(ctx *gin.Context, user *db.User) {
+	if !canSeeContactSettings(optionalAuthPayload(ctx), user.ID) {
+		user.Email = sql.NullString{}
+		user.NotificationChannel = ""
+		user.PreferredLocale = sql.NullString{}
+	}
 }
 
 type getUserUriRequest struct {
@@ -144,20 +193,22 @@ type getUserUriRequest struct {
 func (server *Server) getUserByID(ctx *gin.Context) {
 	var req getUserUriRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUserByID(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	hideContactSettings(ctx, &user)
+
 	ctx.JSON(http.StatusOK, user)
 }
 
@@ -168,20 +219,22 @@ type getUserByPhoneRequest struct {
 func (server *Server) getUserByPhone(ctx *gin.Context) {
 	var req getUserByPhoneRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUserByPhone(ctx, req.PhoneNumber)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	hideContactSettings(ctx, &user)
+
 	ctx.JSON(http.StatusOK, user)
 }
 
@@ -193,7 +246,7 @@ type listUsersRequest struct {
 func (server *Server) listUsers(ctx *gin.Context) {
 	var req listUsersRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -204,10 +257,14 @@ func (server *Server) listUsers(ctx *gin.Context) {
 
 	users, err := server.store.ListUsers(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	for i := range users {
+		hideContactSettings(ctx, &users[i])
+	}
+
 	ctx.JSON(http.StatusOK, users)
 }
 
@@ -220,7 +277,7 @@ type updateUserNameRequest struct {
 func (server *Server) updateUserName(ctx *gin.Context) {
 	var req updateUserNameRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -233,13 +290,15 @@ func (server *Server) updateUserName(ctx *gin.Context) {
 	user, err := server.store.UpdateUserName(ctx, arg)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	hideContactSettings(ctx, &user)
+
 	ctx.JSON(http.StatusOK, user)
 }
 
@@ -251,7 +310,7 @@ type updateUserRoleRequest struct {
 func (server *Server) updateUserRole(ctx *gin.Context) {
 	var req updateUserRoleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -263,10 +322,198 @@ func (server *Server) updateUserRole(ctx *gin.Context) {
 	user, err := server.store.UpdateUserRole(ctx, arg)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+type updateUserDepartmentRequest struct {
+	ID         int64  `json:"id" binding:"required,min=1"`
+	Department string `json:"department" binding:"max=50"`
+}
+
+func (server *Server) updateUserDepartment(ctx *gin.Context) {
+	var req updateUserDepartmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateUserDepartmentParams{
+		ID:         int32(req.ID),
+		Department: sql.NullString{String: req.Department, Valid: req.Department != ""},
+	}
+
+	user, err := server.store.UpdateUserDepartment(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	hideContactSettings(ctx, &user)
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+type updateUserEmailRequest struct {
+	ID    int64  `json:"id" binding:"omitempty,min=1"`            // Optional — admins only, defaults to the caller
+	Email string `json:"email" binding:"omitempty,email,max=255"` // Empty removes the address
+}
+
+func (server *Server) updateUserEmail(ctx *gin.Context) {
+	var req updateUserEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	userID, err := settingsTarget(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateUserEmailParams{
+		ID:    userID,
+		Email: sql.NullString{String: req.Email, Valid: req.Email != ""},
+	}
+
+	user, err := server.store.UpdateUserEmail(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// settingsTarget returns whose settings a request changes: the caller's own,
+// unless an admin names another user by ID.
+func settingsTarget(ctx *gin.Context, requestedID int64) (int32, error) {
+	payload := authPayload(ctx)
+	if requestedID == 0 || int32(requestedID) == payload.UserID {
+		return payload.UserID, nil
+	}
+	if !payload.IsAdmin() {
+		return 0, fmt.Errorf("only an admin can change another user's settings")
+	}
+	return int32(requestedID), nil
+}
+
+type updateUserNotificationChannelRequest struct {
+	ID      int64  `json:"id" binding:"omitempty,min=1"` // Optional — admins only, defaults to the caller
+	Channel string `json:"notification_channel" binding:"required,oneof=sms email in_app"`
+}
+
+func (server *Server) updateUserNotificationChannel(ctx *gin.Context) {
+	var req updateUserNotificationChannelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	userID, err := settingsTarget(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateUserNotificationChannelParams{
+		ID:                  userID,
+		NotificationChannel: req.Channel,
+	}
+
+	user, err := server.store.UpdateUserNotificationChannel(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+type updateUserPreferredLocaleRequest struct {
+	ID     int64  `json:"id" binding:"omitempty,min=1"`      // Optional — admins only, defaults to the caller
+	Locale string `json:"preferred_locale" binding:"max=35"` // Empty to go back to DEFAULT_LOCALE
+}
+
+func (server *Server) updateUserPreferredLocale(ctx *gin.Context) {
+	var req updateUserPreferredLocaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	userID, err := settingsTarget(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateUserPreferredLocaleParams{ID: userID}
+	if req.Locale != "" {
+		locale, err := notifications.NormalizeLocale(req.Locale)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		arg.PreferredLocale = sql.NullString{String: locale, Valid: true}
+	}
+
+	user, err := server.store.UpdateUserPreferredLocale(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+type updateUserActiveRequest struct {
+	ID       int64 `json:"id" binding:"required,min=1"`
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+func (server *Server) updateUserActive(ctx *gin.Context) {
+	var req updateUserActiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateUserActiveParams{
+		ID:       int32(req.ID),
+		IsActive: *req.IsActive,
+	}
+
+	user, err := server.store.UpdateUserActive(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -280,16 +527,20 @@ type getUsersByNameRequest struct {
 func (server *Server) getUsersByName(ctx *gin.Context) {
 	var req getUsersByNameRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	users, err := server.store.GetUsersByName(ctx, sql.NullString{String: req.Query, Valid: req.Query != ""})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	for i := range users {
+		hideContactSettings(ctx, &users[i])
+	}
+
 	ctx.JSON(http.StatusOK, users)
 }
 
@@ -300,17 +551,27 @@ type deleteUserRequest struct {
 func (server *Server) deleteUser(ctx *gin.Context) {
 	var req deleteUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	photos, err := server.store.ListVisitorPhotosByUser(ctx, int32(req.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err := server.purgeVisitorPhotos(ctx, photos); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	err := server.store.DeleteUser(ctx, int32(req.ID))
+	err = server.store.DeleteUser(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -320,7 +581,7 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 func (server *Server) getTopPopularUsers(ctx *gin.Context) {
 	users, err := server.store.GetTopPopularUsers(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -330,17 +591,17 @@ func (server *Server) getTopPopularUsers(ctx *gin.Context) {
 func (server *Server) getTotalAppointmentsVisited(ctx *gin.Context) {
 	var req getUserIDUriRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	count, err := server.store.GetTotalAppointmentsVisited(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -354,17 +615,17 @@ type getUserIDUriRequest struct {
 func (server *Server) getTotalAppointmentsHosted(ctx *gin.Context) {
 	var req getUserIDUriRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	count, err := server.store.GetTotalAppointmentsHosted(ctx, int32(req.ID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
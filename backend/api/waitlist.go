@@ -0,0 +1,224 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/waitlist"
+	"github.com/gin-gonic/gin"
+)
+
+type joinWaitlistRequest struct {
+	VisitorID int64  `json:"visitor_id" binding:"omitempty,min=1"` // Optional — admins only, defaults to the caller
+	HostID    int64  `json:"host_id" binding:"required,min=1"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date"` // Optional — defaults to start_date
+}
+
+func (server *Server) joinWaitlist(ctx *gin.Context) {
+	var req joinWaitlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid start_date format, use YYYY-MM-DD")))
+		return
+	}
+
+	endDate := startDate
+	if req.EndDate != "" {
+		endDate, err = time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid end_date format, use YYYY-MM-DD")))
+			return
+		}
+	}
+
+	if endDate.Before(startDate) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("end_date must not be before start_date")))
+		return
+	}
+
+	payload := authPayload(ctx)
+	visitorID := payload.UserID
+	if req.VisitorID != 0 && int32(req.VisitorID) != visitorID {
+		if !payload.IsAdmin() {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("only an admin can add another visitor to the waitlist")))
+			return
+		}
+		visitorID = int32(req.VisitorID)
+	}
+
+	arg := db.CreateWaitlistEntryParams{
+		VisitorID: visitorID,
+		HostID:    int32(req.HostID),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	entry, err := server.store.CreateWaitlistEntry(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+func (server *Server) listWaitlistByHost(ctx *gin.Context) {
+	var req listByIDUri
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if int32(req.ID) != payload.UserID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("you can only view your own waitlist")))
+		return
+	}
+
+	entries, err := server.store.ListWaitlistByHost(ctx, int32(req.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+func (server *Server) listWaitlistByVisitor(ctx *gin.Context) {
+	var req listByIDUri
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if int32(req.ID) != payload.UserID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("you can only view your own waitlist")))
+		return
+	}
+
+	entries, err := server.store.ListWaitlistByVisitor(ctx, int32(req.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+type waitlistEntryUriRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// leaveWaitlist takes the caller off the waitlist; admins may remove anyone.
+func (server *Server) leaveWaitlist(ctx *gin.Context) {
+	var req waitlistEntryUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	entry, err := server.store.GetWaitlistEntry(ctx, int32(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no active waitlist entry found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	payload := authPayload(ctx)
+	if entry.VisitorID != payload.UserID && !payload.IsAdmin() {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, fmt.Errorf("this waitlist entry belongs to another visitor")))
+		return
+	}
+
+	entry, err = server.store.CancelWaitlistEntry(ctx, entry.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no active waitlist entry found with this ID")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+type waitlistOfferUriRequest struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+func (server *Server) getWaitlistOffer(ctx *gin.Context) {
+	var req waitlistOfferUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	offer, err := server.store.GetWaitlistOfferByToken(ctx, req.Token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no waitlist offer found for this link")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, offer)
+}
+
+func (server *Server) claimWaitlistOffer(ctx *gin.Context) {
+	var req waitlistOfferUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.ClaimWaitlistOfferTx(ctx, db.ClaimWaitlistOfferTxParams{
+		ClaimToken: req.Token,
+		QrCode:     fmt.Sprintf("appointment-%d", time.Now().UnixMilli()),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no waitlist offer found for this link")))
+			return
+		}
+		if err == db.ErrOfferUnavailable {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// offerFreedSlot hands the slot of a cancelled or deleted appointment to the
+// waitlist. Failures are logged so they never block the cancellation itself.
+func (server *Server) offerFreedSlot(ctx *gin.Context, appointment db.Appointment) {
+	slot := db.FreedSlot{
+		HostID:          appointment.HostID,
+		AppointmentDate: appointment.AppointmentDate,
+		StartTime:       appointment.StartTime,
+		EndTime:         appointment.EndTime,
+	}
+
+	if err := waitlist.OfferFreedSlot(ctx, server.config, server.store, server.outbox, slot); err != nil {
+		log.Printf("cannot offer freed slot of appointment %d: %v\n", appointment.ID, err)
+	}
+}
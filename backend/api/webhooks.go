@@ -0,0 +1,256 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultWebhookAttempts is how often a delivery is tried before it is
+// dead-lettered, unless the subscription says otherwise
+const defaultWebhookAttempts = 8
+
+type webhookSubscriptionResponse struct {
+	db.WebhookSubscription
+	Secret string `json:"secret,omitempty"` // Only returned when the subscription is created
+}
+
+// validateWebhook checks the URL is absolute http(s) and every event is one
+// the server publishes.
+func validateWebhook(rawURL string, events []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+
+	for _, event := range events {
+		if !slices.Contains(db.WebhookEvents, event) {
+			return apiErrorf("unknown event %q, expected one of: %s", event, strings.Join(db.WebhookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+type createWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,max=2048"`
+	Secret      string   `json:"secret" binding:"omitempty,min=16,max=255"` // Optional — generated when omitted
+	Events      []string `json:"events" binding:"required,min=1"`
+	MaxAttempts int32    `json:"max_attempts" binding:"omitempty,min=1,max=20"`
+}
+
+// createWebhook subscribes a URL to events. The signing secret is only ever
+// shown in this response.
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if err := validateWebhook(req.URL, req.Events); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.Secret == "" {
+		secret, err := util.RandomToken(32)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		req.Secret = secret
+	}
+	if req.MaxAttempts == 0 {
+		req.MaxAttempts = defaultWebhookAttempts
+	}
+
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:         req.URL,
+		Secret:      req.Secret,
+		Events:      slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		MaxAttempts: req.MaxAttempts,
+		CreatedBy:   sql.NullInt32{Int32: authPayload(ctx).UserID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhookSubscriptionResponse{WebhookSubscription: subscription, Secret: subscription.Secret})
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]webhookSubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		rsp = append(rsp, webhookSubscriptionResponse{WebhookSubscription: subscription})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookUriRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type updateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	IsActive    *bool    `json:"is_active" binding:"required"`
+	MaxAttempts int32    `json:"max_attempts" binding:"omitempty,min=1,max=20"` // Optional — keeps the current value
+}
+
+// updateWebhook changes where and what a subscription receives, or pauses it.
+func (server *Server) updateWebhook(ctx *gin.Context) {
+	var uri webhookUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if err := validateWebhook(req.URL, req.Events); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	current, err := server.store.GetWebhookSubscription(ctx, int32(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if req.MaxAttempts == 0 {
+		req.MaxAttempts = current.MaxAttempts
+	}
+
+	subscription, err := server.store.UpdateWebhookSubscription(ctx, db.UpdateWebhookSubscriptionParams{
+		ID:          current.ID,
+		Url:         req.URL,
+		Events:      slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		IsActive:    *req.IsActive,
+		MaxAttempts: req.MaxAttempts,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhookSubscriptionResponse{WebhookSubscription: subscription})
+}
+
+// deleteWebhook removes a subscription together with its delivery log.
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var req webhookUriRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, err := server.store.GetWebhookSubscription(ctx, int32(req.ID)); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if err := server.store.DeleteWebhookSubscription(ctx, int32(req.ID)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "webhook deleted"})
+}
+
+type listWebhookDeliveriesRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=200"` // Optional — defaults to 50
+}
+
+// listWebhookDeliveries is the subscription's delivery log, newest first,
+// with each delivery's status, attempts and last error.
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookUriRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	if _, err := server.store.GetWebhookSubscription(ctx, int32(uri.ID)); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: sql.NullInt32{Int32: int32(uri.ID), Valid: true},
+		Limit:          req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+type redeliverWebhookRequest struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// redeliverWebhook queues a new delivery with the same payload as a past one,
+// whatever became of the original.
+func (server *Server) redeliverWebhook(ctx *gin.Context) {
+	var req redeliverWebhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	delivery, err := server.store.RedeliverWebhook(ctx, db.RedeliverWebhookParams{
+		DeliveryID:     int32(req.DeliveryID),
+		SubscriptionID: sql.NullInt32{Int32: int32(req.ID), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, fmt.Errorf("no delivery found with this ID for this webhook")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
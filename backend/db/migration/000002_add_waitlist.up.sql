@@ -0,0 +1,31 @@
+CREATE TABLE "waitlist_entries" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "visitor_id" integer NOT NULL,
+  "host_id" integer NOT NULL,
+  "start_date" date NOT NULL,
+  "end_date" date NOT NULL,
+  "status" varchar(10) DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'cancelled')),
+  "created_at" timestamp DEFAULT (now()),
+  CHECK (end_date >= start_date),
+  FOREIGN KEY ("visitor_id") REFERENCES "users" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("host_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "waitlist_entries" ("host_id", "status", "created_at");
+
+CREATE TABLE "waitlist_offers" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "waitlist_entry_id" integer NOT NULL,
+  "appointment_date" date NOT NULL,
+  "start_time" time NOT NULL,
+  "end_time" time NOT NULL,
+  "claim_token" text UNIQUE NOT NULL,
+  "status" varchar(10) DEFAULT 'pending' CHECK (status IN ('pending', 'claimed', 'expired')),
+  "appointment_id" integer,
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp DEFAULT (now()),
+  FOREIGN KEY ("waitlist_entry_id") REFERENCES "waitlist_entries" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "waitlist_offers" ("status", "expires_at");
@@ -0,0 +1,4 @@
+ALTER TABLE "appointments"
+  DROP COLUMN IF EXISTS "cancelled_at",
+  DROP COLUMN IF EXISTS "cancelled_by",
+  DROP COLUMN IF EXISTS "cancellation_reason";
@@ -0,0 +1,5 @@
+ALTER TABLE "appointments"
+  ADD COLUMN "cancellation_reason" text,
+  ADD COLUMN "cancelled_by" integer,
+  ADD COLUMN "cancelled_at" timestamp,
+  ADD FOREIGN KEY ("cancelled_by") REFERENCES "users" ("id") ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE "appointments"
+  DROP COLUMN IF EXISTS "visitor_notes",
+  DROP COLUMN IF EXISTS "host_notes",
+  DROP COLUMN IF EXISTS "location",
+  DROP COLUMN IF EXISTS "purpose_category",
+  DROP COLUMN IF EXISTS "purpose";
@@ -0,0 +1,6 @@
+ALTER TABLE "appointments"
+  ADD COLUMN "purpose" text,
+  ADD COLUMN "purpose_category" varchar(20) DEFAULT 'meeting' CHECK (purpose_category IN ('meeting', 'interview', 'delivery', 'maintenance', 'personal', 'other')),
+  ADD COLUMN "location" varchar(100),
+  ADD COLUMN "host_notes" text,
+  ADD COLUMN "visitor_notes" text;
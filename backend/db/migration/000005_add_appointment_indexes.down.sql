@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "appointments_appointment_date_start_time_id_idx";
+DROP INDEX IF EXISTS "appointments_host_id_appointment_date_idx";
+DROP INDEX IF EXISTS "appointments_visitor_id_appointment_date_idx";
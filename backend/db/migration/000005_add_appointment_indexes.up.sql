@@ -0,0 +1,3 @@
+CREATE INDEX ON "appointments" ("appointment_date", "start_time", "id");
+CREATE INDEX ON "appointments" ("host_id", "appointment_date");
+CREATE INDEX ON "appointments" ("visitor_id", "appointment_date");
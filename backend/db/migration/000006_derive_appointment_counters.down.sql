@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "appointments_sync_counters" ON "appointments";
+DROP FUNCTION IF EXISTS sync_appointment_counters();
@@ -0,0 +1,49 @@
+-- Appointment counters on users and appointment_stats are derived from the
+-- appointments table: every appointment that is not cancelled counts once
+-- for its host and once for its visitor. The trigger below is the only
+-- writer of these columns.
+CREATE OR REPLACE FUNCTION sync_appointment_counters() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.status IS DISTINCT FROM 'cancelled' THEN
+    UPDATE users SET appointments_hosted = GREATEST(appointments_hosted - 1, 0) WHERE id = OLD.host_id;
+    UPDATE users SET appointments_visited = GREATEST(appointments_visited - 1, 0) WHERE id = OLD.visitor_id;
+    UPDATE appointment_stats SET total_appointments = GREATEST(total_appointments - 1, 0)
+    WHERE user_id IN (OLD.host_id, OLD.visitor_id);
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.status IS DISTINCT FROM 'cancelled' THEN
+    UPDATE users SET appointments_hosted = appointments_hosted + 1 WHERE id = NEW.host_id;
+    UPDATE users SET appointments_visited = appointments_visited + 1 WHERE id = NEW.visitor_id;
+    INSERT INTO appointment_stats (user_id, total_appointments)
+    SELECT id, 1 FROM users WHERE id IN (NEW.host_id, NEW.visitor_id)
+    ON CONFLICT (user_id) DO UPDATE
+    SET total_appointments = appointment_stats.total_appointments + 1;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "appointments_sync_counters"
+AFTER INSERT OR DELETE OR UPDATE OF status, host_id, visitor_id ON "appointments"
+FOR EACH ROW EXECUTE FUNCTION sync_appointment_counters();
+
+-- Fix any drift accumulated before the trigger existed
+UPDATE "users" u
+SET "appointments_hosted" = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.host_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    ),
+    "appointments_visited" = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.visitor_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    );
+
+INSERT INTO "appointment_stats" ("user_id", "total_appointments")
+SELECT u.id, (
+  SELECT COUNT(*) FROM appointments a
+  WHERE (a.host_id = u.id OR a.visitor_id = u.id) AND a.status IS DISTINCT FROM 'cancelled'
+)
+FROM users u
+ON CONFLICT ("user_id") DO UPDATE
+SET "total_appointments" = EXCLUDED."total_appointments";
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "department" varchar(50);
+
+CREATE INDEX ON "users" ("department");
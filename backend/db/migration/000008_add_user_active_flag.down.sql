@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "appointments_status_appointment_date_idx";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "is_active";
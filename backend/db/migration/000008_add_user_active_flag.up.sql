@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "is_active" boolean NOT NULL DEFAULT true;
+
+CREATE INDEX ON "appointments" ("status", "appointment_date");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "evacuation_roll";
+DROP TABLE IF EXISTS "evacuations";
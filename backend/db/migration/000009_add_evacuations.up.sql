@@ -0,0 +1,34 @@
+CREATE TABLE "evacuations" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "status" varchar(10) DEFAULT 'active' CHECK (status IN ('active', 'closed')),
+  "started_by" integer,
+  "started_at" timestamp NOT NULL DEFAULT (now()),
+  "closed_by" integer,
+  "closed_at" timestamp,
+  FOREIGN KEY ("started_by") REFERENCES "users" ("id") ON DELETE SET NULL,
+  FOREIGN KEY ("closed_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+-- Only one evacuation can be running at a time
+CREATE UNIQUE INDEX "evacuations_one_active_idx" ON "evacuations" ("status") WHERE status = 'active';
+
+CREATE TABLE "evacuation_roll" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "evacuation_id" integer NOT NULL,
+  "appointment_id" integer,
+  "visitor_id" integer,
+  "visitor_name" text NOT NULL,
+  "visitor_phone" varchar(15) NOT NULL,
+  "host_name" text NOT NULL,
+  "location" varchar(100),
+  "check_in_time" timestamp NOT NULL,
+  "accounted_for" boolean NOT NULL DEFAULT false,
+  "accounted_by" integer,
+  "accounted_at" timestamp,
+  FOREIGN KEY ("evacuation_id") REFERENCES "evacuations" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE SET NULL,
+  FOREIGN KEY ("visitor_id") REFERENCES "users" ("id") ON DELETE SET NULL,
+  FOREIGN KEY ("accounted_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "evacuation_roll" ("evacuation_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "appointment_logs" DROP CONSTRAINT IF EXISTS "appointment_logs_appointment_id_key";
+
+DROP TABLE IF EXISTS "access_events";
@@ -0,0 +1,30 @@
+CREATE TABLE "access_events" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "appointment_id" integer NOT NULL,
+  "direction" varchar(3) NOT NULL CHECK (direction IN ('in', 'out')),
+  "scanned_at" timestamp NOT NULL DEFAULT (now()),
+  "gate" varchar(50),
+  "scanned_by" integer,
+  "created_at" timestamp DEFAULT (now()),
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("scanned_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "access_events" ("appointment_id", "scanned_at");
+
+-- Carry the existing check-in/check-out pairs over as events
+INSERT INTO "access_events" ("appointment_id", "direction", "scanned_at")
+SELECT "appointment_id", 'in', "check_in_time" FROM "appointment_logs"
+WHERE "check_in_time" IS NOT NULL;
+
+INSERT INTO "access_events" ("appointment_id", "direction", "scanned_at")
+SELECT "appointment_id", 'out', "check_out_time" FROM "appointment_logs"
+WHERE "check_out_time" IS NOT NULL;
+
+-- appointment_logs becomes a one-row-per-visit summary derived from access_events
+DELETE FROM "appointment_logs" l
+USING "appointment_logs" d
+WHERE l.appointment_id = d.appointment_id
+  AND l.id > d.id;
+
+ALTER TABLE "appointment_logs" ADD CONSTRAINT "appointment_logs_appointment_id_key" UNIQUE ("appointment_id");
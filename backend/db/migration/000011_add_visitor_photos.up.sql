@@ -0,0 +1,17 @@
+CREATE TABLE "visitor_photos" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "appointment_id" integer NOT NULL,
+  "storage_key" varchar(255) UNIQUE NOT NULL,
+  "content_type" varchar(50) NOT NULL,
+  "size_bytes" integer NOT NULL,
+  "captured_by" integer,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("captured_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "visitor_photos" ("appointment_id", "created_at");
+CREATE INDEX ON "visitor_photos" ("user_id", "created_at");
+CREATE INDEX ON "visitor_photos" ("created_at");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "declaration_responses";
+DROP TABLE IF EXISTS "declaration_form_versions";
+DROP TABLE IF EXISTS "declaration_forms";
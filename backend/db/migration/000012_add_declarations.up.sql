@@ -0,0 +1,35 @@
+CREATE TABLE "declaration_forms" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "title" varchar(150) NOT NULL,
+  "kind" varchar(20) NOT NULL CHECK (kind IN ('nda', 'questionnaire')),
+  "is_required" boolean NOT NULL DEFAULT true,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_by" integer,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+-- Form text and questions are never edited in place; each change is a new
+-- version so stored answers always point at exactly what the visitor saw.
+CREATE TABLE "declaration_form_versions" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "form_id" integer NOT NULL,
+  "version" integer NOT NULL,
+  "body" text NOT NULL,
+  "questions" jsonb NOT NULL DEFAULT '[]',
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("form_id") REFERENCES "declaration_forms" ("id") ON DELETE CASCADE,
+  UNIQUE ("form_id", "version")
+);
+
+CREATE TABLE "declaration_responses" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "appointment_id" integer NOT NULL,
+  "form_version_id" integer NOT NULL,
+  "answers" jsonb NOT NULL DEFAULT '{}',
+  "signed_name" varchar(100) NOT NULL,
+  "accepted_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("form_version_id") REFERENCES "declaration_form_versions" ("id") ON DELETE CASCADE,
+  UNIQUE ("appointment_id", "form_version_id")
+);
@@ -0,0 +1,12 @@
+CREATE TABLE "overstays" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "appointment_id" integer UNIQUE NOT NULL,
+  "scheduled_end" timestamp NOT NULL,
+  "detected_at" timestamp NOT NULL DEFAULT (now()),
+  "resolved_at" timestamp,
+  "overstay_minutes" integer,
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "overstays" ("resolved_at");
+CREATE INDEX ON "overstays" ("scheduled_end");
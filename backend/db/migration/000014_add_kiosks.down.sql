@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "access_events_kiosk_client_event_key";
+
+ALTER TABLE "access_events" DROP COLUMN IF EXISTS "client_event_id";
+ALTER TABLE "access_events" DROP COLUMN IF EXISTS "kiosk_id";
+
+DROP TABLE IF EXISTS "kiosks";
@@ -0,0 +1,18 @@
+CREATE TABLE "kiosks" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar(100) NOT NULL,
+  "gate" varchar(50),
+  "api_key_hash" varchar(64) UNIQUE NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "last_sync_at" timestamp,
+  "created_by" integer,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+-- Scans uploaded by a kiosk carry the device's own event id so that a batch
+-- can be re-sent after a dropped connection without recording scans twice.
+ALTER TABLE "access_events" ADD COLUMN "kiosk_id" integer REFERENCES "kiosks" ("id") ON DELETE SET NULL;
+ALTER TABLE "access_events" ADD COLUMN "client_event_id" varchar(64);
+
+CREATE UNIQUE INDEX "access_events_kiosk_client_event_key" ON "access_events" ("kiosk_id", "client_event_id");
@@ -0,0 +1,26 @@
+-- Outbox of messages waiting to be delivered. Rows are written in the same
+-- transaction as the event that caused them and picked up by the
+-- notification worker, so a crash can never lose or invent a message.
+CREATE TABLE "notifications" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "event" varchar(50) NOT NULL,
+  "appointment_id" integer,
+  "user_id" integer,
+  "channel" varchar(20) NOT NULL CHECK (channel IN ('sms', 'email', 'webhook', 'log')),
+  "recipient" varchar(255) NOT NULL,
+  "subject" varchar(255) NOT NULL DEFAULT '',
+  "body" text NOT NULL,
+  "payload" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'retrying', 'sent', 'dead')),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "max_attempts" integer NOT NULL DEFAULT 5,
+  "next_attempt_at" timestamp NOT NULL DEFAULT (now()),
+  "last_error" text,
+  "sent_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE SET NULL,
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX ON "notifications" ("status", "next_attempt_at");
+CREATE INDEX ON "notifications" ("appointment_id");
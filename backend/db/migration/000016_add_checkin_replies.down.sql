@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "checkin_replies";
+
+DROP INDEX IF EXISTS "notifications_inbox_idx";
+DELETE FROM "notifications" WHERE channel = 'in_app';
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_channel_check";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_channel_check"
+  CHECK (channel IN ('sms', 'email', 'webhook', 'log'));
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "notification_channel";
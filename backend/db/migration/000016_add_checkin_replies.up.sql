@@ -0,0 +1,27 @@
+-- How each user would like to be told about their visits. The notification
+-- outbox falls back to the configured channels when this one cannot reach them.
+ALTER TABLE "users"
+  ADD COLUMN "notification_channel" varchar(10) NOT NULL DEFAULT 'sms'
+  CHECK (notification_channel IN ('sms', 'email', 'in_app'));
+
+-- In-app notifications are delivered by the notification worker into the
+-- user's inbox, i.e. the outbox row itself
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_channel_check";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_channel_check"
+  CHECK (channel IN ('sms', 'email', 'webhook', 'log', 'in_app'));
+
+CREATE INDEX "notifications_inbox_idx" ON "notifications" ("user_id", "created_at") WHERE channel = 'in_app';
+
+-- Replies from a host to the gate after being told their visitor has arrived
+CREATE TABLE "checkin_replies" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "appointment_id" integer NOT NULL,
+  "host_id" integer NOT NULL,
+  "reply" varchar(20) NOT NULL CHECK (reply IN ('on_my_way', 'please_wait')),
+  "message" varchar(200),
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("host_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "checkin_replies" ("appointment_id", "created_at");
@@ -0,0 +1,3 @@
+ALTER TABLE "appointments" DROP COLUMN IF EXISTS "reminder_sent_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email";
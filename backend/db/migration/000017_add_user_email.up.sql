@@ -0,0 +1,5 @@
+-- Optional address for visitors who would rather be emailed than texted
+ALTER TABLE "users" ADD COLUMN "email" varchar(255);
+
+-- Set once the visitor has been reminded, and cleared again on reschedule
+ALTER TABLE "appointments" ADD COLUMN "reminder_sent_at" timestamp;
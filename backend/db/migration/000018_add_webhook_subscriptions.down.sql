@@ -0,0 +1,3 @@
+ALTER TABLE "notifications" DROP COLUMN IF EXISTS "subscription_id";
+
+DROP TABLE IF EXISTS "webhook_subscriptions";
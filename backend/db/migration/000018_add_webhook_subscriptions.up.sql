@@ -0,0 +1,19 @@
+-- Admin-managed webhook endpoints. Deliveries go through the notifications
+-- outbox, one row per subscriber, which doubles as the delivery log.
+CREATE TABLE "webhook_subscriptions" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "url" varchar(2048) NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "events" text[] NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "max_attempts" integer NOT NULL DEFAULT 8,
+  "created_by" integer,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
+
+ALTER TABLE "notifications" ADD COLUMN "subscription_id" integer
+  REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "notifications" ("subscription_id", "created_at") WHERE subscription_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE "appointments" DROP COLUMN IF EXISTS "ics_sequence";
+
+DROP TABLE IF EXISTS "calendar_feeds";
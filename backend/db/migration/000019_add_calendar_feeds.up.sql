@@ -0,0 +1,12 @@
+-- Secret token for each user's ICS subscription feed, issued on first use.
+-- Kept out of "users" so it never leaks through the user endpoints.
+CREATE TABLE "calendar_feeds" (
+  "user_id" integer PRIMARY KEY,
+  "token" varchar(64) UNIQUE NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+-- iCalendar SEQUENCE of the appointment's invite, bumped whenever an update
+-- (reschedule or cancellation) is sent so calendars apply it over the original
+ALTER TABLE "appointments" ADD COLUMN "ics_sequence" integer NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "busy_blocks";
+DROP TABLE IF EXISTS "calendar_imports";
@@ -0,0 +1,35 @@
+-- External calendars a host imports busy time from: an uploaded .ics file,
+-- kept so its recurring events can be expanded again as time moves on, or
+-- an ICS URL fetched on every sync.
+CREATE TABLE "calendar_imports" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "url" varchar(2048),
+  "ics_data" text,
+  "busy_count" integer NOT NULL DEFAULT 0,
+  "last_synced_at" timestamp,
+  "last_attempted_at" timestamp,
+  "last_error" text,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE,
+  CHECK (("url" IS NULL) <> ("ics_data" IS NULL))
+);
+
+CREATE INDEX ON "calendar_imports" ("user_id");
+
+-- Busy occurrences expanded from an import over the sync horizon, replaced
+-- wholesale on every sync. Unlike appointment times these come from other
+-- time zones, so they are stored as instants.
+CREATE TABLE "busy_blocks" (
+  "id" bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "import_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "summary" text NOT NULL DEFAULT '',
+  FOREIGN KEY ("import_id") REFERENCES "calendar_imports" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "busy_blocks" ("user_id", "starts_at");
+CREATE INDEX ON "busy_blocks" ("import_id");
@@ -0,0 +1,15 @@
+-- Language notifications are written in for the user, as a BCP 47 tag such
+-- as "es" or "pt-br". NULL means the site's DEFAULT_LOCALE.
+ALTER TABLE "users" ADD COLUMN "preferred_locale" varchar(35);
+
+-- Admin overrides of the built-in notification templates, per locale
+CREATE TABLE "message_templates" (
+  "locale" varchar(35) NOT NULL,
+  "key" varchar(100) NOT NULL,
+  "subject" text NOT NULL DEFAULT '',
+  "body" text NOT NULL,
+  "updated_by" integer,
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("locale", "key"),
+  FOREIGN KEY ("updated_by") REFERENCES "users" ("id") ON DELETE SET NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE "visitor_photos"
+  DROP CONSTRAINT IF EXISTS "visitor_photos_user_id_fkey",
+  DROP CONSTRAINT IF EXISTS "visitor_photos_appointment_id_fkey",
+  ADD CONSTRAINT "visitor_photos_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE,
+  ADD CONSTRAINT "visitor_photos_appointment_id_fkey" FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- Photos live in object storage, so deleting their row along with a user or
+-- appointment would orphan the file. The API purges them through storage
+-- first; the database refuses anything that did not.
+ALTER TABLE "visitor_photos"
+  DROP CONSTRAINT IF EXISTS "visitor_photos_user_id_fkey",
+  DROP CONSTRAINT IF EXISTS "visitor_photos_appointment_id_fkey",
+  ADD CONSTRAINT "visitor_photos_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT,
+  ADD CONSTRAINT "visitor_photos_appointment_id_fkey" FOREIGN KEY ("appointment_id") REFERENCES "appointments" ("id") ON DELETE RESTRICT;
@@ -0,0 +1,4 @@
+-- The tokens cannot be recovered from their hashes, so every feed is dropped
+-- and reissued on next use.
+DELETE FROM "calendar_feeds";
+ALTER TABLE "calendar_feeds" RENAME COLUMN "token_hash" TO "token";
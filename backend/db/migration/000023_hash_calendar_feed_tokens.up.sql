@@ -0,0 +1,4 @@
+-- Feed tokens are stored as their SHA-256, like kiosk keys, so a database
+-- leak does not hand out working feed URLs. Existing URLs keep working.
+ALTER TABLE "calendar_feeds" RENAME COLUMN "token" TO "token_hash";
+UPDATE "calendar_feeds" SET "token_hash" = encode(sha256(convert_to("token_hash", 'UTF8')), 'hex');
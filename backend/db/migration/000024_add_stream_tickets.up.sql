@@ -0,0 +1,11 @@
+-- Single-use tickets for the live dashboard stream. EventSource cannot send
+-- an authorization header, and a ticket in the URL is far less harmful to
+-- leak through logs than the bearer token itself. Only the hash is kept.
+CREATE TABLE "stream_tickets" (
+  "ticket_hash" varchar(64) PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "stream_tickets" ("expires_at");
@@ -0,0 +1,30 @@
+// Package migration embeds the schema migrations applied with golang-migrate,
+// so the server can tell whether the database is up to date.
+package migration
+
+import (
+	"embed"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+// Latest returns the version of the newest embedded migration, e.g. 24 for
+// 000024_add_stream_tickets.up.sql.
+func Latest() int64 {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return 0
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return latest
+}
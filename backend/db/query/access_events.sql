@@ -0,0 +1,34 @@
+-- name: CreateAccessEvent :one
+INSERT INTO access_events (
+  appointment_id, direction, scanned_at, gate, scanned_by, kiosk_id, client_event_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListAccessEventsByAppointment :many
+SELECT * FROM access_events
+WHERE appointment_id = $1
+ORDER BY scanned_at, id;
+
+-- name: GetLatestAccessEvent :one
+SELECT * FROM access_events
+WHERE appointment_id = $1
+ORDER BY scanned_at DESC, id DESC
+LIMIT 1;
+
+-- name: DeleteAccessEventsByAppointment :exec
+DELETE FROM access_events
+WHERE appointment_id = $1;
+
+-- name: GetPrecedingAccessEvent :one
+-- The last scan for the appointment at or before the given time, used to
+-- spot a repeated scan when offline kiosks upload late.
+SELECT * FROM access_events
+WHERE appointment_id = $1 AND scanned_at <= $2
+ORDER BY scanned_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetAccessEventByClientID :one
+SELECT * FROM access_events
+WHERE kiosk_id = $1 AND client_event_id = $2;
@@ -0,0 +1,63 @@
+-- name: GetDailyVisitVolumes :many
+SELECT
+  d.day::date AS day,
+  COUNT(a.id)::int AS total,
+  COUNT(a.id) FILTER (WHERE a.status = 'completed')::int AS completed,
+  COUNT(a.id) FILTER (WHERE a.status = 'cancelled')::int AS cancelled
+FROM generate_series(sqlc.arg(from_date)::date, sqlc.arg(to_date)::date, interval '1 day') AS d(day)
+LEFT JOIN (
+  SELECT a.id, a.appointment_date, a.status
+  FROM appointments a
+  JOIN users host ON a.host_id = host.id
+  WHERE (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+    AND (sqlc.narg(department)::text IS NULL OR host.department = sqlc.narg(department)::text)
+) a ON a.appointment_date = d.day::date
+GROUP BY d.day
+ORDER BY d.day;
+
+-- name: GetVisitHeatmap :many
+SELECT
+  EXTRACT(ISODOW FROM a.appointment_date)::int AS weekday,
+  EXTRACT(HOUR FROM a.start_time)::int AS hour,
+  COUNT(*)::int AS visits
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND a.status IS DISTINCT FROM 'cancelled'
+  AND (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+    AND (sqlc.narg(department)::text IS NULL OR host.department = sqlc.narg(department)::text)
+GROUP BY weekday, hour
+ORDER BY weekday, hour;
+
+-- name: GetVisitOutcomeCounts :one
+SELECT
+  COUNT(*)::int AS total,
+  COUNT(*) FILTER (WHERE a.status = 'completed')::int AS completed,
+  COUNT(*) FILTER (WHERE a.status = 'cancelled')::int AS cancelled,
+  COUNT(*) FILTER (
+    WHERE a.status = 'pending'
+      AND (a.appointment_date + a.end_time) < NOW()
+  )::int AS no_show
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+    AND (sqlc.narg(department)::text IS NULL OR host.department = sqlc.narg(department)::text);
+
+-- name: GetVisitDurationStats :one
+SELECT
+  COUNT(*)::int AS visits,
+  COALESCE(AVG(EXTRACT(EPOCH FROM (l.check_out_time - l.check_in_time)) / 60), 0)::float AS average_minutes,
+  COALESCE(
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (l.check_out_time - l.check_in_time)) / 60),
+    0
+  )::float AS median_minutes
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NOT NULL
+  AND l.check_out_time >= l.check_in_time
+  AND (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+    AND (sqlc.narg(department)::text IS NULL OR host.department = sqlc.narg(department)::text);
@@ -1,25 +1,28 @@
--- name: CreateAppointmentLog :one
-INSERT INTO appointment_logs (
-  appointment_id, check_in_time, check_out_time
-) VALUES (
-  $1, $2, $3
-)
-RETURNING *;
-
 -- name: GetAppointmentLogByAppointmentID :one
 SELECT * FROM appointment_logs
 WHERE appointment_id = $1;
 
--- name: UpdateCheckInTime :one
-UPDATE appointment_logs
-SET check_in_time = $2
-WHERE appointment_id = $1
-RETURNING *;
-
--- name: UpdateCheckOutTime :one
-UPDATE appointment_logs
-SET check_out_time = $2
-WHERE appointment_id = $1
+-- name: RefreshAppointmentLog :one
+-- Rebuilds the visit summary from access_events: the first entry is the
+-- check-in, and the check-out is only set while the latest scan is an exit.
+INSERT INTO appointment_logs (
+  appointment_id, check_in_time, check_out_time
+)
+SELECT
+  sqlc.arg(appointment_id)::int,
+  (
+    SELECT MIN(e.scanned_at) FROM access_events e
+    WHERE e.appointment_id = sqlc.arg(appointment_id)::int AND e.direction = 'in'
+  ),
+  (
+    SELECT CASE WHEN e.direction = 'out' THEN e.scanned_at END FROM access_events e
+    WHERE e.appointment_id = sqlc.arg(appointment_id)::int
+    ORDER BY e.scanned_at DESC, e.id DESC
+    LIMIT 1
+  )
+ON CONFLICT (appointment_id) DO UPDATE
+SET check_in_time = EXCLUDED.check_in_time,
+    check_out_time = EXCLUDED.check_out_time
 RETURNING *;
 
 -- name: DeleteAppointmentLog :exec
@@ -1,33 +1,61 @@
--- name: CreateAppointmentStats :one
-INSERT INTO appointment_stats (
-  user_id, total_appointments
-) VALUES (
-  $1, $2
-)
-RETURNING *;
-
 -- name: GetAppointmentStatsByUserID :one
 SELECT * FROM appointment_stats
 WHERE user_id = $1;
 
--- name: IncrementAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = total_appointments + 1
-WHERE user_id = $1
-RETURNING *;
-
--- name: DecrementAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = GREATEST(total_appointments - 1, 0)
-WHERE user_id = $1
-RETURNING *;
-
--- name: ResetAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = 0
-WHERE user_id = $1
-RETURNING *;
-
 -- name: DeleteAppointmentStats :exec
 DELETE FROM appointment_stats
 WHERE user_id = $1;
+
+-- name: ListAppointmentCountDrift :many
+WITH expected AS (
+  SELECT
+    u.id AS user_id,
+    COUNT(a.id) FILTER (WHERE a.host_id = u.id) AS hosted,
+    COUNT(a.id) FILTER (WHERE a.visitor_id = u.id) AS visited,
+    COUNT(a.id) AS total
+  FROM users u
+  LEFT JOIN appointments a
+    ON (a.host_id = u.id OR a.visitor_id = u.id)
+   AND a.status IS DISTINCT FROM 'cancelled'
+  WHERE sqlc.narg(user_id)::int IS NULL OR u.id = sqlc.narg(user_id)::int
+  GROUP BY u.id
+)
+SELECT
+  e.user_id::int AS user_id,
+  COALESCE(u.appointments_hosted, 0)::int AS stored_hosted,
+  e.hosted::int AS expected_hosted,
+  COALESCE(u.appointments_visited, 0)::int AS stored_visited,
+  e.visited::int AS expected_visited,
+  COALESCE(s.total_appointments, 0)::int AS stored_total,
+  e.total::int AS expected_total
+FROM expected e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN appointment_stats s ON s.user_id = e.user_id
+WHERE COALESCE(u.appointments_hosted, 0) <> e.hosted
+   OR COALESCE(u.appointments_visited, 0) <> e.visited
+   OR COALESCE(s.total_appointments, 0) <> e.total
+ORDER BY e.user_id;
+
+-- name: ReconcileUserAppointmentCounts :exec
+UPDATE users u
+SET appointments_hosted = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.host_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    ),
+    appointments_visited = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.visitor_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    )
+WHERE u.id = $1;
+
+-- name: ReconcileAppointmentStats :one
+INSERT INTO appointment_stats (user_id, total_appointments)
+SELECT u.id, (
+  SELECT COUNT(*) FROM appointments a
+  WHERE (a.host_id = u.id OR a.visitor_id = u.id) AND a.status IS DISTINCT FROM 'cancelled'
+)
+FROM users u
+WHERE u.id = $1
+ON CONFLICT (user_id) DO UPDATE
+SET total_appointments = EXCLUDED.total_appointments
+RETURNING *;
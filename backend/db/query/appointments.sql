@@ -1,21 +1,13 @@
 -- name: CreateAppointment :one
-WITH updated_visitor AS (
-  UPDATE "users"
-  SET "appointments_visited" = "appointments_visited" + 1
-  WHERE "id" = $1
-  RETURNING "id"
-),
-updated_host AS (
-  UPDATE "users"
-  SET "appointments_hosted" = "appointments_hosted" + 1
-  WHERE "id" = $2
-  RETURNING "id"
-)
+-- User counters and appointment_stats are kept in sync by the
+-- appointments_sync_counters trigger.
 INSERT INTO appointments (
-  visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code
+  visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code,
+  purpose, purpose_category, location, host_notes, visitor_notes
 ) 
 VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7,
+  $8, $9, $10, $11, $12
 )
 RETURNING *;
 
@@ -25,8 +17,10 @@ WHERE id = $1;
 
 -- name: ListAppointmentsByVisitor :many
 SELECT 
-  a.*, 
-  u.first_name || ' ' || u.last_name AS host_name,
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time,
+  a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at,
+  a.purpose, a.purpose_category, a.location, a.visitor_notes, a.ics_sequence,
+  (u.first_name || ' ' || u.last_name)::text AS host_name,
   u.role AS role
 FROM appointments a
 JOIN users u ON a.host_id = u.id
@@ -36,7 +30,7 @@ ORDER BY a.appointment_date DESC;
 -- name: ListAppointmentsByHost :many
 SELECT 
   a.*, 
-  u.first_name || ' ' || u.last_name AS visitor_name,
+  (u.first_name || ' ' || u.last_name)::text AS visitor_name,
   u.role AS role
 FROM appointments a
 JOIN users u ON a.visitor_id = u.id
@@ -81,12 +75,125 @@ SET status = $2
 WHERE id = $1
 RETURNING *;
 
+-- name: GetAppointmentForUpdate :one
+SELECT * FROM appointments
+WHERE id = $1
+FOR UPDATE;
+
 -- name: CancelAppointment :one
 UPDATE appointments
-SET status = 'cancelled'
+SET status = 'cancelled',
+    cancellation_reason = $2,
+    cancelled_by = $3,
+    cancelled_at = NOW(),
+    ics_sequence = ics_sequence + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: RescheduleAppointment :one
+UPDATE appointments
+SET appointment_date = $2,
+    start_time = $3,
+    end_time = $4,
+    reminder_sent_at = NULL,
+    ics_sequence = ics_sequence + 1
 WHERE id = $1
 RETURNING *;
 
+-- name: ListDueReminders :many
+-- Pending appointments starting within the lead time whose visitor has not
+-- been reminded yet.
+SELECT * FROM appointments
+WHERE status = 'pending'
+  AND reminder_sent_at IS NULL
+  AND appointment_date + start_time > LOCALTIMESTAMP
+  AND appointment_date + start_time <= LOCALTIMESTAMP + make_interval(secs => sqlc.arg(lead_seconds)::int)
+ORDER BY appointment_date, start_time
+LIMIT sqlc.arg(batch_size);
+
+-- name: MarkReminderSent :one
+-- Only one replica gets the row back, so a visitor is reminded once.
+UPDATE appointments
+SET reminder_sent_at = now()
+WHERE id = $1
+  AND reminder_sent_at IS NULL
+RETURNING *;
+
 -- name: DeleteAppointment :exec
 DELETE FROM appointments
 WHERE id = $1;
+
+-- name: CountOverlappingAppointments :one
+SELECT COUNT(*) FROM appointments
+WHERE host_id = $1
+  AND appointment_date = $2
+  AND status <> 'cancelled'
+  AND start_time < sqlc.arg(end_time)
+  AND end_time > sqlc.arg(start_time)
+  AND id IS DISTINCT FROM sqlc.narg(exclude_id);
+
+-- name: ListAppointmentsPageDesc :many
+SELECT 
+  a.*,
+  host.first_name || ' ' || host.last_name AS host_name,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+  AND (sqlc.narg(visitor_id)::int IS NULL OR a.visitor_id = sqlc.narg(visitor_id)::int)
+  AND (sqlc.narg(participant_id)::int IS NULL OR a.host_id = sqlc.narg(participant_id)::int OR a.visitor_id = sqlc.narg(participant_id)::int)
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR a.status = ANY(sqlc.arg(statuses)::text[]))
+  AND (sqlc.narg(from_date)::date IS NULL OR a.appointment_date >= sqlc.narg(from_date)::date)
+  AND (sqlc.narg(to_date)::date IS NULL OR a.appointment_date <= sqlc.narg(to_date)::date)
+  AND (
+    sqlc.narg(search)::text IS NULL
+    OR host.first_name || ' ' || host.last_name ILIKE '%' || sqlc.narg(search)::text || '%'
+    OR visitor.first_name || ' ' || visitor.last_name ILIKE '%' || sqlc.narg(search)::text || '%'
+  )
+  AND (
+    sqlc.narg(cursor_id)::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) < (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_time)::time, sqlc.narg(cursor_id)::int)
+  )
+ORDER BY a.appointment_date DESC, a.start_time DESC, a.id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListAppointmentsPageAsc :many
+SELECT 
+  a.*,
+  host.first_name || ' ' || host.last_name AS host_name,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE (sqlc.narg(host_id)::int IS NULL OR a.host_id = sqlc.narg(host_id)::int)
+  AND (sqlc.narg(visitor_id)::int IS NULL OR a.visitor_id = sqlc.narg(visitor_id)::int)
+  AND (sqlc.narg(participant_id)::int IS NULL OR a.host_id = sqlc.narg(participant_id)::int OR a.visitor_id = sqlc.narg(participant_id)::int)
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR a.status = ANY(sqlc.arg(statuses)::text[]))
+  AND (sqlc.narg(from_date)::date IS NULL OR a.appointment_date >= sqlc.narg(from_date)::date)
+  AND (sqlc.narg(to_date)::date IS NULL OR a.appointment_date <= sqlc.narg(to_date)::date)
+  AND (
+    sqlc.narg(search)::text IS NULL
+    OR host.first_name || ' ' || host.last_name ILIKE '%' || sqlc.narg(search)::text || '%'
+    OR visitor.first_name || ' ' || visitor.last_name ILIKE '%' || sqlc.narg(search)::text || '%'
+  )
+  AND (
+    sqlc.narg(cursor_id)::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) > (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_time)::time, sqlc.narg(cursor_id)::int)
+  )
+ORDER BY a.appointment_date ASC, a.start_time ASC, a.id ASC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetAppointmentBadge :one
+SELECT
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time,
+  a.status, a.qr_code, a.location, a.purpose, a.purpose_category, a.ics_sequence,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  host.department AS host_department,
+  visitor.email AS visitor_email,
+  host.email AS host_email
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE a.id = $1;
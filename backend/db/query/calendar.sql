@@ -0,0 +1,63 @@
+-- name: GetCalendarSummary :many
+SELECT
+  d.day::date AS day,
+  COALESCE(s.total, 0)::int AS total,
+  COALESCE(s.pending, 0)::int AS pending,
+  COALESCE(s.ongoing, 0)::int AS ongoing,
+  COALESCE(s.completed, 0)::int AS completed,
+  COALESCE(s.cancelled, 0)::int AS cancelled,
+  COALESCE(to_char(s.first_start_time, 'HH24:MI'), '')::text AS first_start_time,
+  COALESCE(to_char(s.last_end_time, 'HH24:MI'), '')::text AS last_end_time,
+  COALESCE(av.available_minutes, 0)::int AS available_minutes,
+  COALESCE(b.booked_minutes, 0)::int AS booked_minutes
+FROM generate_series(sqlc.arg(start_date)::date, sqlc.arg(end_date)::date, interval '1 day') AS d(day)
+LEFT JOIN LATERAL (
+  SELECT
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE a.status = 'pending') AS pending,
+    COUNT(*) FILTER (WHERE a.status = 'ongoing') AS ongoing,
+    COUNT(*) FILTER (WHERE a.status = 'completed') AS completed,
+    COUNT(*) FILTER (WHERE a.status = 'cancelled') AS cancelled,
+    MIN(a.start_time) FILTER (WHERE a.status <> 'cancelled') AS first_start_time,
+    MAX(a.end_time) FILTER (WHERE a.status <> 'cancelled') AS last_end_time
+  FROM appointments a
+  WHERE a.appointment_date = d.day::date
+    AND (sqlc.narg(user_id)::int IS NULL OR a.host_id = sqlc.narg(user_id)::int OR a.visitor_id = sqlc.narg(user_id)::int)
+) s ON true
+LEFT JOIN LATERAL (
+  SELECT SUM(EXTRACT(EPOCH FROM (av.end_time - av.start_time)) / 60) AS available_minutes
+  FROM availability av
+  WHERE av.day_of_week = EXTRACT(ISODOW FROM d.day)
+    AND av.status = 'available'
+    AND (sqlc.narg(user_id)::int IS NULL OR av.user_id = sqlc.narg(user_id)::int)
+) av ON true
+LEFT JOIN LATERAL (
+  SELECT SUM(EXTRACT(EPOCH FROM (a.end_time - a.start_time)) / 60) AS booked_minutes
+  FROM appointments a
+  WHERE a.appointment_date = d.day::date
+    AND a.status <> 'cancelled'
+    AND (sqlc.narg(user_id)::int IS NULL OR a.host_id = sqlc.narg(user_id)::int)
+) b ON true
+ORDER BY d.day;
+
+-- name: CreateCalendarFeed :one
+-- Issues the user's feed the first time; returns no row if they already have
+-- one, since its token cannot be shown again.
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO NOTHING
+RETURNING *;
+
+-- name: GetCalendarFeed :one
+SELECT * FROM calendar_feeds
+WHERE user_id = $1;
+
+-- name: RotateCalendarFeed :one
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()
+RETURNING *;
+
+-- name: GetCalendarFeedByTokenHash :one
+SELECT * FROM calendar_feeds
+WHERE token_hash = $1;
@@ -0,0 +1,76 @@
+-- name: CreateCalendarImport :one
+INSERT INTO calendar_imports (user_id, name, url, ics_data)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetCalendarImport :one
+SELECT * FROM calendar_imports
+WHERE id = $1;
+
+-- name: ListCalendarImportsByUser :many
+SELECT id, user_id, name, url, busy_count, last_synced_at, last_attempted_at, last_error, created_at
+FROM calendar_imports
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: DeleteCalendarImport :exec
+DELETE FROM calendar_imports
+WHERE id = $1;
+
+-- name: ListDueCalendarImports :many
+-- Imports not tried within the sync interval, oldest first, so a failing URL
+-- waits its turn instead of being retried on every tick.
+SELECT * FROM calendar_imports
+WHERE last_attempted_at IS NULL
+   OR last_attempted_at < now() - make_interval(secs => sqlc.arg(interval_seconds)::int)
+ORDER BY last_attempted_at NULLS FIRST
+LIMIT sqlc.arg(batch_size);
+
+-- name: RequestCalendarImportSync :one
+-- Makes the import due, so the worker syncs it on its next tick.
+UPDATE calendar_imports
+SET last_attempted_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkCalendarImportFailed :exec
+UPDATE calendar_imports
+SET last_attempted_at = now(),
+    last_error = $2
+WHERE id = $1;
+
+-- name: MarkCalendarImportSynced :exec
+UPDATE calendar_imports
+SET last_attempted_at = now(),
+    last_synced_at = now(),
+    last_error = NULL,
+    busy_count = $2
+WHERE id = $1;
+
+-- name: DeleteBusyBlocksByImport :exec
+DELETE FROM busy_blocks
+WHERE import_id = $1;
+
+-- name: CreateBusyBlock :exec
+INSERT INTO busy_blocks (import_id, user_id, starts_at, ends_at, summary)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListBusyBlocks :many
+SELECT * FROM busy_blocks
+WHERE user_id = $1
+  AND starts_at < sqlc.arg(until)
+  AND ends_at > sqlc.arg(since)
+ORDER BY starts_at;
+
+-- name: CountOverlappingBusyBlocks :one
+SELECT COUNT(*) FROM busy_blocks
+WHERE user_id = $1
+  AND starts_at < sqlc.arg(until)
+  AND ends_at > sqlc.arg(since);
+
+-- name: ListHostAppointmentTimes :many
+SELECT start_time, end_time FROM appointments
+WHERE host_id = $1
+  AND appointment_date = $2
+  AND status <> 'cancelled'
+ORDER BY start_time;
@@ -0,0 +1,12 @@
+-- name: CreateCheckInReply :one
+INSERT INTO checkin_replies (
+  appointment_id, host_id, reply, message
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListCheckInReplies :many
+SELECT * FROM checkin_replies
+WHERE appointment_id = $1
+ORDER BY created_at DESC, id DESC;
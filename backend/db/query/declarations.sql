@@ -0,0 +1,113 @@
+-- name: CreateDeclarationForm :one
+INSERT INTO declaration_forms (
+  title, kind, is_required, created_by
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetDeclarationForm :one
+SELECT * FROM declaration_forms
+WHERE id = $1;
+
+-- name: UpdateDeclarationForm :one
+UPDATE declaration_forms
+SET title = $2, is_required = $3, is_active = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateDeclarationFormVersion :one
+-- Versions are numbered per form; the unique (form_id, version) constraint
+-- rejects a concurrent publish instead of creating two identical numbers.
+INSERT INTO declaration_form_versions (
+  form_id, version, body, questions
+)
+SELECT
+  sqlc.arg(form_id)::int,
+  COALESCE(MAX(v.version), 0) + 1,
+  sqlc.arg(body)::text,
+  sqlc.arg(questions)::jsonb
+FROM declaration_form_versions v
+WHERE v.form_id = sqlc.arg(form_id)::int
+RETURNING *;
+
+-- name: GetDeclarationFormVersion :one
+SELECT * FROM declaration_form_versions
+WHERE id = $1;
+
+-- name: ListDeclarationFormVersions :many
+SELECT * FROM declaration_form_versions
+WHERE form_id = $1
+ORDER BY version DESC;
+
+-- name: ListDeclarationForms :many
+-- Each form with its current (latest) version.
+SELECT
+  f.id, f.title, f.kind, f.is_required, f.is_active, f.created_at,
+  v.id AS version_id, v.version, v.body, v.questions
+FROM declaration_forms f
+JOIN LATERAL (
+  SELECT * FROM declaration_form_versions
+  WHERE form_id = f.id
+  ORDER BY version DESC
+  LIMIT 1
+) v ON true
+WHERE sqlc.narg(active_only)::boolean IS NULL OR f.is_active = sqlc.narg(active_only)::boolean
+ORDER BY f.id;
+
+-- name: ListAppointmentDeclarations :many
+-- The current version of every active form, with this visit's latest response
+-- to any version of it. A form signed in an earlier version counts as signed,
+-- the same rule CountPendingDeclarations applies; response_version_id tells
+-- the two apart.
+SELECT
+  f.id AS form_id, f.title, f.kind, f.is_required,
+  v.id AS version_id, v.version, v.body, v.questions,
+  r.id AS response_id,
+  r.form_version_id AS response_version_id,
+  COALESCE(r.answers, 'null'::jsonb) AS answers,
+  r.signed_name, r.accepted_at
+FROM declaration_forms f
+JOIN LATERAL (
+  SELECT * FROM declaration_form_versions
+  WHERE form_id = f.id
+  ORDER BY version DESC
+  LIMIT 1
+) v ON true
+LEFT JOIN declaration_responses r ON r.id = (
+  SELECT dr.id FROM declaration_responses dr
+  JOIN declaration_form_versions dv ON dr.form_version_id = dv.id
+  WHERE dv.form_id = f.id AND dr.appointment_id = sqlc.arg(appointment_id)
+  ORDER BY dv.version DESC
+  LIMIT 1
+)
+WHERE f.is_active
+ORDER BY f.is_required DESC, f.id;
+
+-- name: CountPendingDeclarations :one
+-- Required, active forms this visit has not signed in any version. A visitor
+-- who signed an earlier version may re-enter after a new one is published;
+-- only visits that have signed nothing yet must sign the current version.
+SELECT COUNT(*)::int FROM declaration_forms f
+WHERE f.is_active AND f.is_required
+  AND EXISTS (
+    SELECT 1 FROM declaration_form_versions v
+    WHERE v.form_id = f.id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM declaration_responses r
+    JOIN declaration_form_versions v ON r.form_version_id = v.id
+    WHERE v.form_id = f.id AND r.appointment_id = sqlc.arg(appointment_id)
+  );
+
+-- name: UpsertDeclarationResponse :one
+INSERT INTO declaration_responses (
+  appointment_id, form_version_id, answers, signed_name
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (appointment_id, form_version_id) DO UPDATE
+SET answers = EXCLUDED.answers,
+    signed_name = EXCLUDED.signed_name,
+    accepted_at = now()
+RETURNING *;
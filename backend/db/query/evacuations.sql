@@ -0,0 +1,63 @@
+-- name: CreateEvacuation :one
+INSERT INTO evacuations (
+  started_by
+) VALUES (
+  $1
+)
+RETURNING *;
+
+-- name: GetEvacuation :one
+SELECT * FROM evacuations
+WHERE id = $1;
+
+-- name: ListEvacuations :many
+SELECT * FROM evacuations
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CloseEvacuation :one
+UPDATE evacuations
+SET status = 'closed',
+    closed_by = $2,
+    closed_at = NOW()
+WHERE id = $1
+  AND status = 'active'
+RETURNING *;
+
+-- name: SnapshotEvacuationRoll :many
+INSERT INTO evacuation_roll (
+  evacuation_id, appointment_id, visitor_id, visitor_name, visitor_phone, host_name, location, check_in_time
+)
+SELECT
+  sqlc.arg(evacuation_id)::int,
+  l.appointment_id,
+  a.visitor_id,
+  visitor.first_name || ' ' || visitor.last_name,
+  visitor.phone_number,
+  host.first_name || ' ' || host.last_name,
+  a.location,
+  l.check_in_time
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+RETURNING *;
+
+-- name: ListEvacuationRoll :many
+SELECT * FROM evacuation_roll
+WHERE evacuation_id = $1
+ORDER BY accounted_for, visitor_name;
+
+-- name: MarkEvacuationRollEntry :one
+UPDATE evacuation_roll r
+SET accounted_for = sqlc.arg(accounted_for)::bool,
+    accounted_by = CASE WHEN sqlc.arg(accounted_for)::bool THEN sqlc.narg(accounted_by)::int END,
+    accounted_at = CASE WHEN sqlc.arg(accounted_for)::bool THEN NOW() END
+FROM evacuations e
+WHERE r.id = sqlc.arg(id)
+  AND r.evacuation_id = sqlc.arg(evacuation_id)
+  AND e.id = r.evacuation_id
+  AND e.status = 'active'
+RETURNING r.*;
@@ -0,0 +1,25 @@
+-- name: ListLogbookPage :many
+SELECT
+  a.id,
+  a.appointment_date,
+  a.start_time,
+  a.end_time,
+  a.status,
+  a.purpose,
+  a.purpose_category,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  l.check_in_time,
+  l.check_out_time
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN appointment_logs l ON l.appointment_id = a.id
+WHERE a.appointment_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (
+    sqlc.narg(cursor_id)::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) > (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_time)::time, sqlc.narg(cursor_id)::int)
+  )
+ORDER BY a.appointment_date, a.start_time, a.id
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,57 @@
+-- name: CreateKiosk :one
+INSERT INTO kiosks (
+  name, gate, api_key_hash, created_by
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetKioskByKeyHash :one
+SELECT * FROM kiosks
+WHERE api_key_hash = $1;
+
+-- name: ListKiosks :many
+SELECT * FROM kiosks
+ORDER BY id;
+
+-- name: UpdateKioskActive :one
+UPDATE kiosks
+SET is_active = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: TouchKioskSync :exec
+UPDATE kiosks
+SET last_sync_at = now()
+WHERE id = $1;
+
+-- name: ListKioskManifest :many
+-- Everything a kiosk needs to validate the day's scans while offline.
+SELECT
+  a.id AS appointment_id, a.qr_code, a.status, a.appointment_date,
+  a.start_time, a.end_time, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  (l.check_in_time IS NOT NULL AND l.check_out_time IS NULL)::boolean AS on_site,
+  (
+    -- Same rule as CountPendingDeclarations: any signed version counts
+    SELECT COUNT(*) FROM declaration_forms f
+    WHERE f.is_active AND f.is_required
+      AND EXISTS (
+        SELECT 1 FROM declaration_form_versions v
+        WHERE v.form_id = f.id
+      )
+      AND NOT EXISTS (
+        SELECT 1 FROM declaration_responses r
+        JOIN declaration_form_versions v ON r.form_version_id = v.id
+        WHERE v.form_id = f.id AND r.appointment_id = a.id
+      )
+  )::int AS declarations_pending
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN appointment_logs l ON l.appointment_id = a.id
+WHERE a.appointment_date = $1
+  AND a.status IN ('pending', 'ongoing')
+  AND a.qr_code IS NOT NULL
+ORDER BY a.start_time, a.id;
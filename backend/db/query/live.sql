@@ -0,0 +1,41 @@
+-- name: NotifyLiveEvent :exec
+-- Tells live dashboards on every replica about a change to an appointment.
+-- Postgres only delivers it once the surrounding transaction commits.
+SELECT pg_notify('live_events', json_build_object(
+  'event', sqlc.arg(event)::text,
+  'appointment_id', a.id,
+  'host_id', a.host_id,
+  'visitor_id', a.visitor_id,
+  'host_name', host.first_name || ' ' || host.last_name,
+  'visitor_name', visitor.first_name || ' ' || visitor.last_name,
+  'status', a.status,
+  'appointment_date', a.appointment_date,
+  'start_time', to_char(a.start_time, 'HH24:MI'),
+  'end_time', to_char(a.end_time, 'HH24:MI'),
+  'occurred_at', now()
+)::text)
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE a.id = sqlc.arg(appointment_id);
+
+-- name: CreateStreamTicket :exec
+INSERT INTO stream_tickets (ticket_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: RedeemStreamTicket :one
+-- Deletes the ticket as it is used, so it works once. The role is read now
+-- rather than when the ticket was issued.
+WITH redeemed AS (
+  DELETE FROM stream_tickets
+  WHERE ticket_hash = $1
+    AND expires_at > now()
+  RETURNING user_id
+)
+SELECT u.id, u.role
+FROM redeemed
+JOIN users u ON u.id = redeemed.user_id;
+
+-- name: DeleteExpiredStreamTickets :exec
+DELETE FROM stream_tickets
+WHERE expires_at <= now();
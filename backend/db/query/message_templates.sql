@@ -0,0 +1,17 @@
+-- name: ListMessageTemplates :many
+SELECT * FROM message_templates
+ORDER BY locale, key;
+
+-- name: UpsertMessageTemplate :one
+INSERT INTO message_templates (locale, key, subject, body, updated_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (locale, key) DO UPDATE
+SET subject = EXCLUDED.subject,
+    body = EXCLUDED.body,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteMessageTemplate :execrows
+DELETE FROM message_templates
+WHERE locale = $1 AND key = $2;
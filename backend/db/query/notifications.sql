@@ -0,0 +1,74 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  event, appointment_id, user_id, channel, recipient, subject, body, payload, max_attempts
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: DeadLetterExpiredLeases :many
+-- A lease that ran out on the last attempt (e.g. the worker died mid-send)
+-- has no attempts left to retry with, so the notification is dead-lettered
+-- instead of being claimed again.
+UPDATE notifications
+SET status = 'dead',
+    last_error = 'lease expired before the last attempt finished'
+WHERE status = 'sending'
+  AND next_attempt_at <= LOCALTIMESTAMP
+  AND attempts >= max_attempts
+RETURNING *;
+
+-- name: ClaimDueNotifications :many
+-- Leases a batch of due notifications to one worker. SKIP LOCKED lets several
+-- workers run side by side, and a lease that runs out (e.g. the worker died
+-- mid-send) makes the row due again while it has attempts left.
+UPDATE notifications
+SET status = 'sending',
+    attempts = attempts + 1,
+    next_attempt_at = LOCALTIMESTAMP + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+  SELECT id FROM notifications
+  WHERE status IN ('pending', 'retrying', 'sending')
+    AND next_attempt_at <= LOCALTIMESTAMP
+    AND (status <> 'sending' OR attempts < max_attempts)
+  ORDER BY next_attempt_at, id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status = 'sent', sent_at = now(), last_error = NULL
+WHERE id = $1;
+
+-- name: MarkNotificationFailed :one
+-- Schedules another attempt after the given backoff, or moves the
+-- notification to the dead-letter state once it has used up its attempts.
+UPDATE notifications
+SET status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'retrying' END,
+    next_attempt_at = LOCALTIMESTAMP + make_interval(secs => sqlc.arg(backoff_seconds)::int),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListNotificationsByAppointment :many
+SELECT * FROM notifications
+WHERE appointment_id = $1
+ORDER BY created_at, id;
+
+-- name: RequeueNotification :one
+-- Gives a dead-lettered notification a fresh set of attempts.
+UPDATE notifications
+SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE id = $1 AND status = 'dead'
+RETURNING *;
+
+-- name: ListInboxNotifications :many
+-- The user's in-app notifications, newest first.
+SELECT * FROM notifications
+WHERE user_id = $1
+  AND channel = 'in_app'
+  AND status = 'sent'
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
@@ -0,0 +1,29 @@
+-- name: ListOnsiteVisitors :many
+SELECT
+  l.appointment_id,
+  l.check_in_time,
+  a.visitor_id,
+  a.host_id,
+  a.location,
+  a.end_time,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  host.first_name || ' ' || host.last_name AS host_name,
+  o.detected_at AS overstay_detected_at,
+  r.reply AS host_reply,
+  r.message AS host_reply_message,
+  r.created_at AS host_replied_at
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN overstays o ON o.appointment_id = l.appointment_id AND o.resolved_at IS NULL
+LEFT JOIN checkin_replies r ON r.appointment_id = l.appointment_id
+  AND NOT EXISTS (
+    SELECT 1 FROM checkin_replies newer
+    WHERE newer.appointment_id = r.appointment_id
+      AND (newer.created_at, newer.id) > (r.created_at, r.id)
+  )
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+ORDER BY l.check_in_time;
@@ -0,0 +1,68 @@
+-- name: FlagOverstays :many
+-- Flags every visitor still checked in past their scheduled end plus the
+-- grace period. Visits that are already flagged are skipped, so only new
+-- overstays come back.
+INSERT INTO overstays (appointment_id, scheduled_end)
+SELECT a.id, a.appointment_date + a.end_time
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+  AND a.appointment_date + a.end_time + make_interval(mins => sqlc.arg(grace_minutes)::int) < LOCALTIMESTAMP
+ON CONFLICT (appointment_id) DO NOTHING
+RETURNING *;
+
+-- name: ResolveOverstays :many
+-- Closes overstays whose visitor has checked out and records how long they
+-- stayed past the scheduled end.
+UPDATE overstays o
+SET resolved_at = l.check_out_time,
+    overstay_minutes = GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (l.check_out_time - o.scheduled_end)) / 60))::int
+FROM appointment_logs l
+WHERE l.appointment_id = o.appointment_id
+  AND o.resolved_at IS NULL
+  AND l.check_out_time IS NOT NULL
+RETURNING o.*;
+
+-- name: ListOverstays :many
+SELECT
+  o.id, o.appointment_id, o.scheduled_end, o.detected_at, o.resolved_at,
+  COALESCE(
+    o.overstay_minutes,
+    GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (LOCALTIMESTAMP - o.scheduled_end)) / 60))::int
+  )::int AS overstay_minutes,
+  a.visitor_id, a.host_id, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  (host.first_name || ' ' || host.last_name)::text AS host_name
+FROM overstays o
+JOIN appointments a ON o.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE (sqlc.narg(active)::boolean IS NULL OR (o.resolved_at IS NULL) = sqlc.narg(active)::boolean)
+  AND o.scheduled_end >= sqlc.arg(from_date)::date
+  AND o.scheduled_end < sqlc.arg(to_date)::date + 1
+ORDER BY o.scheduled_end DESC, o.id DESC;
+
+-- name: GetOverstaySummary :one
+SELECT
+  COUNT(*)::int AS total,
+  COUNT(*) FILTER (WHERE resolved_at IS NULL)::int AS active,
+  COALESCE(AVG(overstay_minutes), 0)::float8 AS avg_minutes,
+  COALESCE(MAX(overstay_minutes), 0)::int AS max_minutes
+FROM overstays
+WHERE scheduled_end >= sqlc.arg(from_date)::date
+  AND scheduled_end < sqlc.arg(to_date)::date + 1;
+
+-- name: GetOverstayNotice :one
+SELECT
+  o.id, o.appointment_id, o.scheduled_end, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  host.id AS host_id,
+  host.phone_number AS host_phone,
+  host.preferred_locale AS host_preferred_locale
+FROM overstays o
+JOIN appointments a ON o.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE o.id = $1;
@@ -1,8 +1,8 @@
 -- name: CreateUser :one
 INSERT INTO users (
-  phone_number, first_name, last_name, role
+  phone_number, first_name, last_name, role, department, email
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
@@ -32,6 +32,36 @@ SET role = $2
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateUserDepartment :one
+UPDATE users
+SET department = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserPreferredLocale :one
+UPDATE users
+SET preferred_locale = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserActive :one
+UPDATE users
+SET is_active = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserNotificationChannel :one
+UPDATE users
+SET notification_channel = $2
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
@@ -50,6 +80,27 @@ FROM users
 ORDER BY total_appointments DESC
 LIMIT 10;
 
+-- name: GetLeaderboard :many
+SELECT
+  u.id,
+  u.first_name,
+  u.last_name,
+  u.role,
+  u.department,
+  COUNT(a.id)::int AS completed_appointments
+FROM appointments a
+JOIN users u ON u.id = CASE
+  WHEN sqlc.arg(rank_by)::text = 'visited' THEN a.visitor_id
+  ELSE a.host_id
+END
+WHERE a.status = 'completed'
+  AND a.appointment_date >= sqlc.arg(since)::date
+  AND (NOT sqlc.arg(exclude_admins)::bool OR u.role IS DISTINCT FROM 'admin')
+  AND (NOT sqlc.arg(exclude_inactive)::bool OR u.is_active)
+GROUP BY u.id
+ORDER BY completed_appointments DESC, u.id
+LIMIT sqlc.arg(max_results);
+
 -- name: GetTotalAppointmentsHosted :one
 SELECT appointments_hosted
 FROM users
@@ -0,0 +1,41 @@
+-- name: CreateVisitorPhoto :one
+INSERT INTO visitor_photos (
+  user_id, appointment_id, storage_key, content_type, size_bytes, captured_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetLatestVisitorPhoto :one
+-- Prefers the photo taken for this visit and falls back to the visitor's most
+-- recent one from an earlier visit.
+SELECT * FROM visitor_photos
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY (appointment_id = sqlc.arg(appointment_id)) DESC, created_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListExpiredVisitorPhotos :many
+SELECT * FROM visitor_photos
+WHERE created_at < sqlc.arg(captured_before)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: DeleteVisitorPhoto :exec
+DELETE FROM visitor_photos
+WHERE id = $1;
+
+-- name: ListVisitorPhotosByAppointment :many
+SELECT * FROM visitor_photos
+WHERE appointment_id = $1
+ORDER BY id;
+
+-- name: ListVisitorPhotosByUser :many
+-- Every photo deleting the user would take with it: their own, and those of
+-- the appointments they host or visit, which are deleted with them.
+SELECT * FROM visitor_photos
+WHERE user_id = sqlc.arg(user_id)
+   OR appointment_id IN (
+     SELECT id FROM appointments
+     WHERE visitor_id = sqlc.arg(user_id) OR host_id = sqlc.arg(user_id)
+   )
+ORDER BY id;
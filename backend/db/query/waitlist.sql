@@ -0,0 +1,110 @@
+-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (
+  visitor_id, host_id, start_date, end_date
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWaitlistEntry :one
+SELECT * FROM waitlist_entries
+WHERE id = $1;
+
+-- name: ListWaitlistByHost :many
+SELECT 
+  w.*,
+  u.first_name || ' ' || u.last_name AS visitor_name
+FROM waitlist_entries w
+JOIN users u ON w.visitor_id = u.id
+WHERE w.host_id = $1
+  AND w.status IN ('waiting', 'offered')
+ORDER BY w.created_at, w.id;
+
+-- name: ListWaitlistByVisitor :many
+SELECT 
+  w.*,
+  u.first_name || ' ' || u.last_name AS host_name
+FROM waitlist_entries w
+JOIN users u ON w.host_id = u.id
+WHERE w.visitor_id = $1
+ORDER BY w.created_at DESC;
+
+-- name: GetNextWaitlistEntry :one
+-- Visitors who already let an offer of this very slot expire are skipped, so
+-- requeued entries do not get the same slot back.
+SELECT * FROM waitlist_entries
+WHERE host_id = $1
+  AND status = 'waiting'
+  AND sqlc.arg(slot_date)::date BETWEEN start_date AND end_date
+  AND NOT EXISTS (
+    SELECT 1 FROM waitlist_offers o
+    WHERE o.waitlist_entry_id = waitlist_entries.id
+      AND o.appointment_date = sqlc.arg(slot_date)::date
+      AND o.start_time = sqlc.arg(slot_start)::time
+  )
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateWaitlistEntryStatus :one
+UPDATE waitlist_entries
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: RequeueWaitlistEntry :exec
+UPDATE waitlist_entries
+SET status = 'waiting'
+WHERE id = $1
+  AND status = 'offered';
+
+-- name: CancelWaitlistEntry :one
+UPDATE waitlist_entries
+SET status = 'cancelled'
+WHERE id = $1
+  AND status IN ('waiting', 'offered')
+RETURNING *;
+
+-- name: CreateWaitlistOffer :one
+INSERT INTO waitlist_offers (
+  waitlist_entry_id, appointment_date, start_time, end_time, claim_token, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetWaitlistOfferByToken :one
+SELECT 
+  o.*,
+  w.visitor_id,
+  w.host_id,
+  host.first_name || ' ' || host.last_name AS host_name
+FROM waitlist_offers o
+JOIN waitlist_entries w ON o.waitlist_entry_id = w.id
+JOIN users host ON w.host_id = host.id
+WHERE o.claim_token = $1;
+
+-- name: GetWaitlistOfferForUpdate :one
+SELECT * FROM waitlist_offers
+WHERE claim_token = $1
+FOR UPDATE;
+
+-- name: ListExpiredWaitlistOffers :many
+SELECT * FROM waitlist_offers
+WHERE status = 'pending'
+  AND expires_at < NOW()
+ORDER BY expires_at;
+
+-- name: ExpireWaitlistOffer :one
+UPDATE waitlist_offers
+SET status = 'expired'
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
+
+-- name: ClaimWaitlistOffer :one
+UPDATE waitlist_offers
+SET status = 'claimed',
+    appointment_id = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,57 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  url, secret, events, max_attempts, created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+ORDER BY id;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = $2,
+    events = $3,
+    is_active = $4,
+    max_attempts = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues one delivery of the event for every active subscriber to it.
+INSERT INTO notifications (
+  event, appointment_id, subscription_id, channel, recipient, body, payload, max_attempts
+)
+SELECT sqlc.arg(event), sqlc.narg(appointment_id), s.id, 'webhook', s.url, '', sqlc.arg(payload), s.max_attempts
+FROM webhook_subscriptions s
+WHERE s.is_active
+  AND sqlc.arg(event)::text = ANY(s.events);
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM notifications
+WHERE subscription_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: RedeliverWebhook :one
+-- Queues a fresh copy of a past delivery, sent to the subscription's current URL.
+INSERT INTO notifications (
+  event, appointment_id, subscription_id, channel, recipient, body, payload, max_attempts
+)
+SELECT n.event, n.appointment_id, s.id, 'webhook', s.url, '', n.payload, s.max_attempts
+FROM notifications n
+JOIN webhook_subscriptions s ON s.id = n.subscription_id
+WHERE n.id = sqlc.arg(delivery_id)
+  AND n.subscription_id = sqlc.arg(subscription_id)
+RETURNING *;
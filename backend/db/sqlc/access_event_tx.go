@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDeclarationsPending is returned when a visitor tries to check in
+	// before completing every required declaration form.
+	ErrDeclarationsPending = errors.New("required declarations have not been completed")
+	// ErrAppointmentCancelled is returned for a gate scan of a cancelled
+	// appointment.
+	ErrAppointmentCancelled = errors.New("appointment has been cancelled")
+)
+
+type RecordAccessEventTxParams struct {
+	CreateAccessEventParams
+	// Notifications are queued only when this scan is the visit's first entry
+	Notifications []CreateNotificationParams
+}
+
+type RecordAccessEventTxResult struct {
+	Event AccessEvent    `json:"event"`
+	Log   AppointmentLog `json:"log"`
+}
+
+// RecordAccessEventTx stores a single gate scan and refreshes the visit
+// summary in appointment_logs from the full event history. Scans of a
+// cancelled appointment are refused with ErrAppointmentCancelled, and entries
+// with ErrDeclarationsPending until the required forms are done.
+func (store *SQLStore) RecordAccessEventTx(ctx context.Context, arg RecordAccessEventTxParams) (RecordAccessEventTxResult, error) {
+	var result RecordAccessEventTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = recordAccessEvent(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func recordAccessEvent(ctx context.Context, q *Queries, arg RecordAccessEventTxParams) (RecordAccessEventTxResult, error) {
+	var result RecordAccessEventTxResult
+
+	// Locked so the appointment cannot be cancelled while the scan is stored
+	appointment, err := q.GetAppointmentForUpdate(ctx, arg.AppointmentID)
+	if err != nil {
+		return result, err
+	}
+	if appointment.Status.String == "cancelled" {
+		return result, ErrAppointmentCancelled
+	}
+
+	firstEntry := false
+	if arg.Direction == "in" {
+		pending, err := q.CountPendingDeclarations(ctx, arg.AppointmentID)
+		if err != nil {
+			return result, err
+		}
+		if pending > 0 {
+			return result, ErrDeclarationsPending
+		}
+
+		previous, err := q.GetAppointmentLogByAppointmentID(ctx, arg.AppointmentID)
+		if err != nil && err != sql.ErrNoRows {
+			return result, err
+		}
+
+		// The host was already told if a guard marked the visit ongoing
+		firstEntry = !previous.CheckInTime.Valid && appointment.Status.String != "ongoing"
+	}
+
+	result.Event, err = q.CreateAccessEvent(ctx, arg.CreateAccessEventParams)
+	if err != nil {
+		return result, err
+	}
+
+	result.Log, err = q.RefreshAppointmentLog(ctx, arg.AppointmentID)
+	if err != nil {
+		return result, err
+	}
+
+	event := WebhookVisitorCheckedIn
+	if arg.Direction == "out" {
+		event = WebhookVisitorCheckedOut
+	}
+	if err := publishEvent(ctx, q, event, arg.AppointmentID, result); err != nil {
+		return result, err
+	}
+
+	if firstEntry {
+		err = enqueueNotifications(ctx, q, arg.AppointmentID, arg.Notifications)
+	}
+	return result, err
+}
+
+type ApplyKioskScanTxParams struct {
+	RecordAccessEventTxParams
+	// DuplicateWindow is how close a scan may follow one in the same
+	// direction before it is treated as the same scan from another device.
+	DuplicateWindow time.Duration
+}
+
+type ApplyKioskScanTxResult struct {
+	Event     AccessEvent `json:"event"`
+	Duplicate bool        `json:"duplicate"`
+}
+
+// ApplyKioskScanTx records a scan uploaded by an offline kiosk. A scan the
+// kiosk already uploaded, or a repeat of the previous scan within the
+// duplicate window, is reported as a duplicate of the existing event instead
+// of being stored again.
+func (store *SQLStore) ApplyKioskScanTx(ctx context.Context, arg ApplyKioskScanTxParams) (ApplyKioskScanTxResult, error) {
+	var result ApplyKioskScanTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		existing, err := q.GetAccessEventByClientID(ctx, GetAccessEventByClientIDParams{
+			KioskID:       arg.KioskID,
+			ClientEventID: arg.ClientEventID,
+		})
+		if err == nil {
+			result = ApplyKioskScanTxResult{Event: existing, Duplicate: true}
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		preceding, err := q.GetPrecedingAccessEvent(ctx, GetPrecedingAccessEventParams{
+			AppointmentID: arg.AppointmentID,
+			ScannedAt:     arg.ScannedAt,
+		})
+		if err == nil && preceding.Direction == arg.Direction && arg.ScannedAt.Sub(preceding.ScannedAt) <= arg.DuplicateWindow {
+			result = ApplyKioskScanTxResult{Event: preceding, Duplicate: true}
+			return nil
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		recorded, err := recordAccessEvent(ctx, q, arg.RecordAccessEventTxParams)
+		if err != nil {
+			return err
+		}
+
+		result = ApplyKioskScanTxResult{Event: recorded.Event}
+		return nil
+	})
+
+	return result, err
+}
+
+// DeleteVisitLogTx removes every scan for an appointment along with its summary.
+func (store *SQLStore) DeleteVisitLogTx(ctx context.Context, appointmentID int32) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteAccessEventsByAppointment(ctx, appointmentID); err != nil {
+			return err
+		}
+		return q.DeleteAppointmentLog(ctx, appointmentID)
+	})
+}
@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: access_events.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createAccessEvent = `-- name: CreateAccessEvent :one
+INSERT INTO access_events (
+  appointment_id, direction, scanned_at, gate, scanned_by, kiosk_id, client_event_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, appointment_id, direction, scanned_at, gate, scanned_by, created_at, kiosk_id, client_event_id
+`
+
+type CreateAccessEventParams struct {
+	AppointmentID int32          `json:"appointment_id"`
+	Direction     string         `json:"direction"`
+	ScannedAt     time.Time      `json:"scanned_at"`
+	Gate          sql.NullString `json:"gate"`
+	ScannedBy     sql.NullInt32  `json:"scanned_by"`
+	KioskID       sql.NullInt32  `json:"kiosk_id"`
+	ClientEventID sql.NullString `json:"client_event_id"`
+}
+
+func (q *Queries) CreateAccessEvent(ctx context.Context, arg CreateAccessEventParams) (AccessEvent, error) {
+	row := q.queryRow(ctx, q.createAccessEventStmt, createAccessEvent,
+		arg.AppointmentID,
+		arg.Direction,
+		arg.ScannedAt,
+		arg.Gate,
+		arg.ScannedBy,
+		arg.KioskID,
+		arg.ClientEventID,
+	)
+	var i AccessEvent
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.Direction,
+		&i.ScannedAt,
+		&i.Gate,
+		&i.ScannedBy,
+		&i.CreatedAt,
+		&i.KioskID,
+		&i.ClientEventID,
+	)
+	return i, err
+}
+
+const deleteAccessEventsByAppointment = `-- name: DeleteAccessEventsByAppointment :exec
+DELETE FROM access_events
+WHERE appointment_id = $1
+`
+
+func (q *Queries) DeleteAccessEventsByAppointment(ctx context.Context, appointmentID int32) error {
+	_, err := q.exec(ctx, q.deleteAccessEventsByAppointmentStmt, deleteAccessEventsByAppointment, appointmentID)
+	return err
+}
+
+const getAccessEventByClientID = `-- name: GetAccessEventByClientID :one
+SELECT id, appointment_id, direction, scanned_at, gate, scanned_by, created_at, kiosk_id, client_event_id FROM access_events
+WHERE kiosk_id = $1 AND client_event_id = $2
+`
+
+type GetAccessEventByClientIDParams struct {
+	KioskID       sql.NullInt32  `json:"kiosk_id"`
+	ClientEventID sql.NullString `json:"client_event_id"`
+}
+
+func (q *Queries) GetAccessEventByClientID(ctx context.Context, arg GetAccessEventByClientIDParams) (AccessEvent, error) {
+	row := q.queryRow(ctx, q.getAccessEventByClientIDStmt, getAccessEventByClientID, arg.KioskID, arg.ClientEventID)
+	var i AccessEvent
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.Direction,
+		&i.ScannedAt,
+		&i.Gate,
+		&i.ScannedBy,
+		&i.CreatedAt,
+		&i.KioskID,
+		&i.ClientEventID,
+	)
+	return i, err
+}
+
+const getLatestAccessEvent = `-- name: GetLatestAccessEvent :one
+SELECT id, appointment_id, direction, scanned_at, gate, scanned_by, created_at, kiosk_id, client_event_id FROM access_events
+WHERE appointment_id = $1
+ORDER BY scanned_at DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestAccessEvent(ctx context.Context, appointmentID int32) (AccessEvent, error) {
+	row := q.queryRow(ctx, q.getLatestAccessEventStmt, getLatestAccessEvent, appointmentID)
+	var i AccessEvent
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.Direction,
+		&i.ScannedAt,
+		&i.Gate,
+		&i.ScannedBy,
+		&i.CreatedAt,
+		&i.KioskID,
+		&i.ClientEventID,
+	)
+	return i, err
+}
+
+const getPrecedingAccessEvent = `-- name: GetPrecedingAccessEvent :one
+SELECT id, appointment_id, direction, scanned_at, gate, scanned_by, created_at, kiosk_id, client_event_id FROM access_events
+WHERE appointment_id = $1 AND scanned_at <= $2
+ORDER BY scanned_at DESC, id DESC
+LIMIT 1
+`
+
+type GetPrecedingAccessEventParams struct {
+	AppointmentID int32     `json:"appointment_id"`
+	ScannedAt     time.Time `json:"scanned_at"`
+}
+
+// The last scan for the appointment at or before the given time, used to
+// spot a repeated scan when offline kiosks upload late.
+func (q *Queries) GetPrecedingAccessEvent(ctx context.Context, arg GetPrecedingAccessEventParams) (AccessEvent, error) {
+	row := q.queryRow(ctx, q.getPrecedingAccessEventStmt, getPrecedingAccessEvent, arg.AppointmentID, arg.ScannedAt)
+	var i AccessEvent
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.Direction,
+		&i.ScannedAt,
+		&i.Gate,
+		&i.ScannedBy,
+		&i.CreatedAt,
+		&i.KioskID,
+		&i.ClientEventID,
+	)
+	return i, err
+}
+
+const listAccessEventsByAppointment = `-- name: ListAccessEventsByAppointment :many
+SELECT id, appointment_id, direction, scanned_at, gate, scanned_by, created_at, kiosk_id, client_event_id FROM access_events
+WHERE appointment_id = $1
+ORDER BY scanned_at, id
+`
+
+func (q *Queries) ListAccessEventsByAppointment(ctx context.Context, appointmentID int32) ([]AccessEvent, error) {
+	rows, err := q.query(ctx, q.listAccessEventsByAppointmentStmt, listAccessEventsByAppointment, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccessEvent{}
+	for rows.Next() {
+		var i AccessEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.Direction,
+			&i.ScannedAt,
+			&i.Gate,
+			&i.ScannedBy,
+			&i.CreatedAt,
+			&i.KioskID,
+			&i.ClientEventID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,224 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: analytics.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const getDailyVisitVolumes = `-- name: GetDailyVisitVolumes :many
+SELECT
+  d.day::date AS day,
+  COUNT(a.id)::int AS total,
+  COUNT(a.id) FILTER (WHERE a.status = 'completed')::int AS completed,
+  COUNT(a.id) FILTER (WHERE a.status = 'cancelled')::int AS cancelled
+FROM generate_series($1::date, $2::date, interval '1 day') AS d(day)
+LEFT JOIN (
+  SELECT a.id, a.appointment_date, a.status
+  FROM appointments a
+  JOIN users host ON a.host_id = host.id
+  WHERE ($3::int IS NULL OR a.host_id = $3::int)
+    AND ($4::text IS NULL OR host.department = $4::text)
+) a ON a.appointment_date = d.day::date
+GROUP BY d.day
+ORDER BY d.day
+`
+
+type GetDailyVisitVolumesParams struct {
+	FromDate   time.Time      `json:"from_date"`
+	ToDate     time.Time      `json:"to_date"`
+	HostID     sql.NullInt32  `json:"host_id"`
+	Department sql.NullString `json:"department"`
+}
+
+type GetDailyVisitVolumesRow struct {
+	Day       time.Time `json:"day"`
+	Total     int32     `json:"total"`
+	Completed int32     `json:"completed"`
+	Cancelled int32     `json:"cancelled"`
+}
+
+func (q *Queries) GetDailyVisitVolumes(ctx context.Context, arg GetDailyVisitVolumesParams) ([]GetDailyVisitVolumesRow, error) {
+	rows, err := q.query(ctx, q.getDailyVisitVolumesStmt, getDailyVisitVolumes,
+		arg.FromDate,
+		arg.ToDate,
+		arg.HostID,
+		arg.Department,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDailyVisitVolumesRow{}
+	for rows.Next() {
+		var i GetDailyVisitVolumesRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Total,
+			&i.Completed,
+			&i.Cancelled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVisitDurationStats = `-- name: GetVisitDurationStats :one
+SELECT
+  COUNT(*)::int AS visits,
+  COALESCE(AVG(EXTRACT(EPOCH FROM (l.check_out_time - l.check_in_time)) / 60), 0)::float AS average_minutes,
+  COALESCE(
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (l.check_out_time - l.check_in_time)) / 60),
+    0
+  )::float AS median_minutes
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN $1::date AND $2::date
+  AND l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NOT NULL
+  AND l.check_out_time >= l.check_in_time
+  AND ($3::int IS NULL OR a.host_id = $3::int)
+    AND ($4::text IS NULL OR host.department = $4::text)
+`
+
+type GetVisitDurationStatsParams struct {
+	FromDate   time.Time      `json:"from_date"`
+	ToDate     time.Time      `json:"to_date"`
+	HostID     sql.NullInt32  `json:"host_id"`
+	Department sql.NullString `json:"department"`
+}
+
+type GetVisitDurationStatsRow struct {
+	Visits         int32   `json:"visits"`
+	AverageMinutes float64 `json:"average_minutes"`
+	MedianMinutes  float64 `json:"median_minutes"`
+}
+
+func (q *Queries) GetVisitDurationStats(ctx context.Context, arg GetVisitDurationStatsParams) (GetVisitDurationStatsRow, error) {
+	row := q.queryRow(ctx, q.getVisitDurationStatsStmt, getVisitDurationStats,
+		arg.FromDate,
+		arg.ToDate,
+		arg.HostID,
+		arg.Department,
+	)
+	var i GetVisitDurationStatsRow
+	err := row.Scan(&i.Visits, &i.AverageMinutes, &i.MedianMinutes)
+	return i, err
+}
+
+const getVisitHeatmap = `-- name: GetVisitHeatmap :many
+SELECT
+  EXTRACT(ISODOW FROM a.appointment_date)::int AS weekday,
+  EXTRACT(HOUR FROM a.start_time)::int AS hour,
+  COUNT(*)::int AS visits
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN $1::date AND $2::date
+  AND a.status IS DISTINCT FROM 'cancelled'
+  AND ($3::int IS NULL OR a.host_id = $3::int)
+    AND ($4::text IS NULL OR host.department = $4::text)
+GROUP BY weekday, hour
+ORDER BY weekday, hour
+`
+
+type GetVisitHeatmapParams struct {
+	FromDate   time.Time      `json:"from_date"`
+	ToDate     time.Time      `json:"to_date"`
+	HostID     sql.NullInt32  `json:"host_id"`
+	Department sql.NullString `json:"department"`
+}
+
+type GetVisitHeatmapRow struct {
+	Weekday int32 `json:"weekday"`
+	Hour    int32 `json:"hour"`
+	Visits  int32 `json:"visits"`
+}
+
+func (q *Queries) GetVisitHeatmap(ctx context.Context, arg GetVisitHeatmapParams) ([]GetVisitHeatmapRow, error) {
+	rows, err := q.query(ctx, q.getVisitHeatmapStmt, getVisitHeatmap,
+		arg.FromDate,
+		arg.ToDate,
+		arg.HostID,
+		arg.Department,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVisitHeatmapRow{}
+	for rows.Next() {
+		var i GetVisitHeatmapRow
+		if err := rows.Scan(&i.Weekday, &i.Hour, &i.Visits); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVisitOutcomeCounts = `-- name: GetVisitOutcomeCounts :one
+SELECT
+  COUNT(*)::int AS total,
+  COUNT(*) FILTER (WHERE a.status = 'completed')::int AS completed,
+  COUNT(*) FILTER (WHERE a.status = 'cancelled')::int AS cancelled,
+  COUNT(*) FILTER (
+    WHERE a.status = 'pending'
+      AND (a.appointment_date + a.end_time) < NOW()
+  )::int AS no_show
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+WHERE a.appointment_date BETWEEN $1::date AND $2::date
+  AND ($3::int IS NULL OR a.host_id = $3::int)
+    AND ($4::text IS NULL OR host.department = $4::text)
+`
+
+type GetVisitOutcomeCountsParams struct {
+	FromDate   time.Time      `json:"from_date"`
+	ToDate     time.Time      `json:"to_date"`
+	HostID     sql.NullInt32  `json:"host_id"`
+	Department sql.NullString `json:"department"`
+}
+
+type GetVisitOutcomeCountsRow struct {
+	Total     int32 `json:"total"`
+	Completed int32 `json:"completed"`
+	Cancelled int32 `json:"cancelled"`
+	NoShow    int32 `json:"no_show"`
+}
+
+func (q *Queries) GetVisitOutcomeCounts(ctx context.Context, arg GetVisitOutcomeCountsParams) (GetVisitOutcomeCountsRow, error) {
+	row := q.queryRow(ctx, q.getVisitOutcomeCountsStmt, getVisitOutcomeCounts,
+		arg.FromDate,
+		arg.ToDate,
+		arg.HostID,
+		arg.Department,
+	)
+	var i GetVisitOutcomeCountsRow
+	err := row.Scan(
+		&i.Total,
+		&i.Completed,
+		&i.Cancelled,
+		&i.NoShow,
+	)
+	return i, err
+}
@@ -7,36 +7,8 @@ package db
 
 import (
 	"context"
-	"database/sql"
 )
 
-const createAppointmentLog = `-- name: CreateAppointmentLog :one
-INSERT INTO appointment_logs (
-  appointment_id, check_in_time, check_out_time
-) VALUES (
-  $1, $2, $3
-)
-RETURNING id, appointment_id, check_in_time, check_out_time
-`
-
-type CreateAppointmentLogParams struct {
-	AppointmentID int32        `json:"appointment_id"`
-	CheckInTime   sql.NullTime `json:"check_in_time"`
-	CheckOutTime  sql.NullTime `json:"check_out_time"`
-}
-
-func (q *Queries) CreateAppointmentLog(ctx context.Context, arg CreateAppointmentLogParams) (AppointmentLog, error) {
-	row := q.queryRow(ctx, q.createAppointmentLogStmt, createAppointmentLog, arg.AppointmentID, arg.CheckInTime, arg.CheckOutTime)
-	var i AppointmentLog
-	err := row.Scan(
-		&i.ID,
-		&i.AppointmentID,
-		&i.CheckInTime,
-		&i.CheckOutTime,
-	)
-	return i, err
-}
-
 const deleteAppointmentLog = `-- name: DeleteAppointmentLog :exec
 DELETE FROM appointment_logs
 WHERE appointment_id = $1
@@ -64,44 +36,32 @@ func (q *Queries) GetAppointmentLogByAppointmentID(ctx context.Context, appointm
 	return i, err
 }
 
-const updateCheckInTime = `-- name: UpdateCheckInTime :one
-UPDATE appointment_logs
-SET check_in_time = $2
-WHERE appointment_id = $1
-RETURNING id, appointment_id, check_in_time, check_out_time
-`
-
-type UpdateCheckInTimeParams struct {
-	AppointmentID int32        `json:"appointment_id"`
-	CheckInTime   sql.NullTime `json:"check_in_time"`
-}
-
-func (q *Queries) UpdateCheckInTime(ctx context.Context, arg UpdateCheckInTimeParams) (AppointmentLog, error) {
-	row := q.queryRow(ctx, q.updateCheckInTimeStmt, updateCheckInTime, arg.AppointmentID, arg.CheckInTime)
-	var i AppointmentLog
-	err := row.Scan(
-		&i.ID,
-		&i.AppointmentID,
-		&i.CheckInTime,
-		&i.CheckOutTime,
-	)
-	return i, err
-}
-
-const updateCheckOutTime = `-- name: UpdateCheckOutTime :one
-UPDATE appointment_logs
-SET check_out_time = $2
-WHERE appointment_id = $1
+const refreshAppointmentLog = `-- name: RefreshAppointmentLog :one
+INSERT INTO appointment_logs (
+  appointment_id, check_in_time, check_out_time
+)
+SELECT
+  $1::int,
+  (
+    SELECT MIN(e.scanned_at) FROM access_events e
+    WHERE e.appointment_id = $1::int AND e.direction = 'in'
+  ),
+  (
+    SELECT CASE WHEN e.direction = 'out' THEN e.scanned_at END FROM access_events e
+    WHERE e.appointment_id = $1::int
+    ORDER BY e.scanned_at DESC, e.id DESC
+    LIMIT 1
+  )
+ON CONFLICT (appointment_id) DO UPDATE
+SET check_in_time = EXCLUDED.check_in_time,
+    check_out_time = EXCLUDED.check_out_time
 RETURNING id, appointment_id, check_in_time, check_out_time
 `
 
-type UpdateCheckOutTimeParams struct {
-	AppointmentID int32        `json:"appointment_id"`
-	CheckOutTime  sql.NullTime `json:"check_out_time"`
-}
-
-func (q *Queries) UpdateCheckOutTime(ctx context.Context, arg UpdateCheckOutTimeParams) (AppointmentLog, error) {
-	row := q.queryRow(ctx, q.updateCheckOutTimeStmt, updateCheckOutTime, arg.AppointmentID, arg.CheckOutTime)
+// Rebuilds the visit summary from access_events: the first entry is the
+// check-in, and the check-out is only set while the latest scan is an exit.
+func (q *Queries) RefreshAppointmentLog(ctx context.Context, appointmentID int32) (AppointmentLog, error) {
+	row := q.queryRow(ctx, q.refreshAppointmentLogStmt, refreshAppointmentLog, appointmentID)
 	var i AppointmentLog
 	err := row.Scan(
 		&i.ID,
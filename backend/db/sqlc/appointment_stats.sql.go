@@ -10,41 +10,6 @@ import (
 	"database/sql"
 )
 
-const createAppointmentStats = `-- name: CreateAppointmentStats :one
-INSERT INTO appointment_stats (
-  user_id, total_appointments
-) VALUES (
-  $1, $2
-)
-RETURNING user_id, total_appointments
-`
-
-type CreateAppointmentStatsParams struct {
-	UserID            int32         `json:"user_id"`
-	TotalAppointments sql.NullInt32 `json:"total_appointments"`
-}
-
-func (q *Queries) CreateAppointmentStats(ctx context.Context, arg CreateAppointmentStatsParams) (AppointmentStat, error) {
-	row := q.queryRow(ctx, q.createAppointmentStatsStmt, createAppointmentStats, arg.UserID, arg.TotalAppointments)
-	var i AppointmentStat
-	err := row.Scan(&i.UserID, &i.TotalAppointments)
-	return i, err
-}
-
-const decrementAppointmentCount = `-- name: DecrementAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = GREATEST(total_appointments - 1, 0)
-WHERE user_id = $1
-RETURNING user_id, total_appointments
-`
-
-func (q *Queries) DecrementAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error) {
-	row := q.queryRow(ctx, q.decrementAppointmentCountStmt, decrementAppointmentCount, userID)
-	var i AppointmentStat
-	err := row.Scan(&i.UserID, &i.TotalAppointments)
-	return i, err
-}
-
 const deleteAppointmentStats = `-- name: DeleteAppointmentStats :exec
 DELETE FROM appointment_stats
 WHERE user_id = $1
@@ -67,30 +32,112 @@ func (q *Queries) GetAppointmentStatsByUserID(ctx context.Context, userID int32)
 	return i, err
 }
 
-const incrementAppointmentCount = `-- name: IncrementAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = total_appointments + 1
-WHERE user_id = $1
-RETURNING user_id, total_appointments
+const listAppointmentCountDrift = `-- name: ListAppointmentCountDrift :many
+WITH expected AS (
+  SELECT
+    u.id AS user_id,
+    COUNT(a.id) FILTER (WHERE a.host_id = u.id) AS hosted,
+    COUNT(a.id) FILTER (WHERE a.visitor_id = u.id) AS visited,
+    COUNT(a.id) AS total
+  FROM users u
+  LEFT JOIN appointments a
+    ON (a.host_id = u.id OR a.visitor_id = u.id)
+   AND a.status IS DISTINCT FROM 'cancelled'
+  WHERE $1::int IS NULL OR u.id = $1::int
+  GROUP BY u.id
+)
+SELECT
+  e.user_id::int AS user_id,
+  COALESCE(u.appointments_hosted, 0)::int AS stored_hosted,
+  e.hosted::int AS expected_hosted,
+  COALESCE(u.appointments_visited, 0)::int AS stored_visited,
+  e.visited::int AS expected_visited,
+  COALESCE(s.total_appointments, 0)::int AS stored_total,
+  e.total::int AS expected_total
+FROM expected e
+JOIN users u ON u.id = e.user_id
+LEFT JOIN appointment_stats s ON s.user_id = e.user_id
+WHERE COALESCE(u.appointments_hosted, 0) <> e.hosted
+   OR COALESCE(u.appointments_visited, 0) <> e.visited
+   OR COALESCE(s.total_appointments, 0) <> e.total
+ORDER BY e.user_id
 `
 
-func (q *Queries) IncrementAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error) {
-	row := q.queryRow(ctx, q.incrementAppointmentCountStmt, incrementAppointmentCount, userID)
-	var i AppointmentStat
-	err := row.Scan(&i.UserID, &i.TotalAppointments)
-	return i, err
+type ListAppointmentCountDriftRow struct {
+	UserID          int32 `json:"user_id"`
+	StoredHosted    int32 `json:"stored_hosted"`
+	ExpectedHosted  int32 `json:"expected_hosted"`
+	StoredVisited   int32 `json:"stored_visited"`
+	ExpectedVisited int32 `json:"expected_visited"`
+	StoredTotal     int32 `json:"stored_total"`
+	ExpectedTotal   int32 `json:"expected_total"`
 }
 
-const resetAppointmentCount = `-- name: ResetAppointmentCount :one
-UPDATE appointment_stats
-SET total_appointments = 0
-WHERE user_id = $1
+func (q *Queries) ListAppointmentCountDrift(ctx context.Context, userID sql.NullInt32) ([]ListAppointmentCountDriftRow, error) {
+	rows, err := q.query(ctx, q.listAppointmentCountDriftStmt, listAppointmentCountDrift, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAppointmentCountDriftRow{}
+	for rows.Next() {
+		var i ListAppointmentCountDriftRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.StoredHosted,
+			&i.ExpectedHosted,
+			&i.StoredVisited,
+			&i.ExpectedVisited,
+			&i.StoredTotal,
+			&i.ExpectedTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reconcileAppointmentStats = `-- name: ReconcileAppointmentStats :one
+INSERT INTO appointment_stats (user_id, total_appointments)
+SELECT u.id, (
+  SELECT COUNT(*) FROM appointments a
+  WHERE (a.host_id = u.id OR a.visitor_id = u.id) AND a.status IS DISTINCT FROM 'cancelled'
+)
+FROM users u
+WHERE u.id = $1
+ON CONFLICT (user_id) DO UPDATE
+SET total_appointments = EXCLUDED.total_appointments
 RETURNING user_id, total_appointments
 `
 
-func (q *Queries) ResetAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error) {
-	row := q.queryRow(ctx, q.resetAppointmentCountStmt, resetAppointmentCount, userID)
+func (q *Queries) ReconcileAppointmentStats(ctx context.Context, id int32) (AppointmentStat, error) {
+	row := q.queryRow(ctx, q.reconcileAppointmentStatsStmt, reconcileAppointmentStats, id)
 	var i AppointmentStat
 	err := row.Scan(&i.UserID, &i.TotalAppointments)
 	return i, err
 }
+
+const reconcileUserAppointmentCounts = `-- name: ReconcileUserAppointmentCounts :exec
+UPDATE users u
+SET appointments_hosted = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.host_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    ),
+    appointments_visited = (
+      SELECT COUNT(*) FROM appointments a
+      WHERE a.visitor_id = u.id AND a.status IS DISTINCT FROM 'cancelled'
+    )
+WHERE u.id = $1
+`
+
+func (q *Queries) ReconcileUserAppointmentCounts(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.reconcileUserAppointmentCountsStmt, reconcileUserAppointmentCounts, id)
+	return err
+}
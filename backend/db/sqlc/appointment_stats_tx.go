@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+type ReconcileAppointmentCountsTxParams struct {
+	// UserID limits the reconciliation to one user when set
+	UserID sql.NullInt32
+	// DryRun only reports drift without fixing it
+	DryRun bool
+}
+
+type ReconcileAppointmentCountsTxResult struct {
+	Drift []ListAppointmentCountDriftRow `json:"drift"`
+	Fixed bool                           `json:"fixed"`
+}
+
+// ReconcileAppointmentCountsTx recomputes the per-user appointment counters
+// from the appointments table, reports every user whose stored counters
+// disagree, and unless DryRun is set rewrites them.
+func (store *SQLStore) ReconcileAppointmentCountsTx(ctx context.Context, arg ReconcileAppointmentCountsTxParams) (ReconcileAppointmentCountsTxResult, error) {
+	var result ReconcileAppointmentCountsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Drift, err = q.ListAppointmentCountDrift(ctx, arg.UserID)
+		if err != nil || arg.DryRun {
+			return err
+		}
+
+		for _, drift := range result.Drift {
+			if err := q.ReconcileUserAppointmentCounts(ctx, drift.UserID); err != nil {
+				return err
+			}
+			if _, err := q.ReconcileAppointmentStats(ctx, drift.UserID); err != nil {
+				return err
+			}
+		}
+
+		result.Fixed = true
+		return nil
+	})
+
+	return result, err
+}
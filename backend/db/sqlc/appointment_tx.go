@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAppointmentNotCancellable is returned when an appointment has already
+	// started, finished or been cancelled.
+	ErrAppointmentNotCancellable = errors.New("only pending appointments can be cancelled")
+	// ErrAppointmentNotReschedulable is returned when an appointment has
+	// already started, finished or been cancelled.
+	ErrAppointmentNotReschedulable = errors.New("only pending appointments can be rescheduled")
+	// ErrSlotTaken is returned when the host already has an appointment that
+	// overlaps the requested time.
+	ErrSlotTaken = errors.New("the host already has an appointment at that time")
+	// ErrHostBusy is returned when the requested time overlaps busy time
+	// imported from one of the host's calendars.
+	ErrHostBusy = errors.New("the host is busy at that time")
+)
+
+// checkHostFree returns ErrHostBusy if the host's imported busy time
+// overlaps [startsAt, endsAt).
+func checkHostFree(ctx context.Context, q *Queries, hostID int32, startsAt, endsAt time.Time) error {
+	busy, err := q.CountOverlappingBusyBlocks(ctx, CountOverlappingBusyBlocksParams{
+		UserID: hostID,
+		Since:  startsAt,
+		Until:  endsAt,
+	})
+	if err != nil {
+		return err
+	}
+	if busy > 0 {
+		return ErrHostBusy
+	}
+	return nil
+}
+
+type CreateAppointmentTxParams struct {
+	CreateAppointmentParams
+	// StartsAt and EndsAt are the slot as instants in the site's time zone,
+	// to check against the host's imported busy time
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Notifications []CreateNotificationParams
+}
+
+// CreateAppointmentTx books an appointment unless the host is busy then,
+// queues its booking notifications and publishes appointment.created.
+func (store *SQLStore) CreateAppointmentTx(ctx context.Context, arg CreateAppointmentTxParams) (Appointment, error) {
+	var appointment Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkHostFree(ctx, q, arg.HostID, arg.StartsAt, arg.EndsAt); err != nil {
+			return err
+		}
+
+		var err error
+		appointment, err = q.CreateAppointment(ctx, arg.CreateAppointmentParams)
+		if err != nil {
+			return err
+		}
+
+		if err := publishEvent(ctx, q, WebhookAppointmentCreated, appointment.ID, newAppointmentEvent(appointment)); err != nil {
+			return err
+		}
+		return enqueueNotifications(ctx, q, appointment.ID, arg.Notifications)
+	})
+
+	return appointment, err
+}
+
+type CancelAppointmentTxParams struct {
+	AppointmentID      int32
+	CancellationReason string
+	CancelledBy        int32
+	Notifications      []CreateNotificationParams
+}
+
+// CancelAppointmentTx cancels a pending appointment, records who cancelled
+// it and why, and queues the cancellation notices. The counters follow
+// through the appointments_sync_counters trigger.
+func (store *SQLStore) CancelAppointmentTx(ctx context.Context, arg CancelAppointmentTxParams) (Appointment, error) {
+	var appointment Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetAppointmentForUpdate(ctx, arg.AppointmentID)
+		if err != nil {
+			return err
+		}
+		if current.Status.String != "pending" {
+			return ErrAppointmentNotCancellable
+		}
+
+		appointment, err = q.CancelAppointment(ctx, CancelAppointmentParams{
+			ID:                 arg.AppointmentID,
+			CancellationReason: sql.NullString{String: arg.CancellationReason, Valid: true},
+			CancelledBy:        sql.NullInt32{Int32: arg.CancelledBy, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := publishEvent(ctx, q, WebhookAppointmentCancelled, appointment.ID, newAppointmentEvent(appointment)); err != nil {
+			return err
+		}
+		return enqueueNotifications(ctx, q, appointment.ID, arg.Notifications)
+	})
+
+	return appointment, err
+}
+
+type RescheduleAppointmentTxParams struct {
+	RescheduleAppointmentParams
+	// StartsAt and EndsAt are the new slot as instants in the site's time
+	// zone, to check against the host's imported busy time
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Notifications []CreateNotificationParams
+}
+
+// RescheduleAppointmentTx moves a pending appointment to a new time if the
+// host is free then, and queues the reschedule notices.
+func (store *SQLStore) RescheduleAppointmentTx(ctx context.Context, arg RescheduleAppointmentTxParams) (Appointment, error) {
+	var appointment Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetAppointmentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status.String != "pending" {
+			return ErrAppointmentNotReschedulable
+		}
+
+		taken, err := q.CountOverlappingAppointments(ctx, CountOverlappingAppointmentsParams{
+			HostID:          current.HostID,
+			AppointmentDate: arg.AppointmentDate,
+			StartTime:       arg.StartTime,
+			EndTime:         arg.EndTime,
+			ExcludeID:       sql.NullInt32{Int32: current.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrSlotTaken
+		}
+		if err := checkHostFree(ctx, q, current.HostID, arg.StartsAt, arg.EndsAt); err != nil {
+			return err
+		}
+
+		appointment, err = q.RescheduleAppointment(ctx, arg.RescheduleAppointmentParams)
+		if err != nil {
+			return err
+		}
+
+		if err := publishEvent(ctx, q, WebhookAppointmentRescheduled, appointment.ID, newAppointmentEvent(appointment)); err != nil {
+			return err
+		}
+		return enqueueNotifications(ctx, q, appointment.ID, arg.Notifications)
+	})
+
+	return appointment, err
+}
+
+type SendReminderTxParams struct {
+	AppointmentID int32
+	Notifications []CreateNotificationParams
+}
+
+// SendReminderTx marks the appointment as reminded and queues the reminder.
+// It returns sql.ErrNoRows when another worker has already sent it.
+func (store *SQLStore) SendReminderTx(ctx context.Context, arg SendReminderTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		appointment, err := q.MarkReminderSent(ctx, arg.AppointmentID)
+		if err != nil {
+			return err
+		}
+
+		return enqueueNotifications(ctx, q, appointment.ID, arg.Notifications)
+	})
+}
+
+// DeleteAppointmentTx deletes an appointment and returns the deleted row.
+func (store *SQLStore) DeleteAppointmentTx(ctx context.Context, appointmentID int32) (Appointment, error) {
+	var appointment Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		appointment, err = q.GetAppointmentForUpdate(ctx, appointmentID)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteAppointment(ctx, appointmentID)
+	})
+
+	return appointment, err
+}
@@ -9,17 +9,29 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const cancelAppointment = `-- name: CancelAppointment :one
 UPDATE appointments
-SET status = 'cancelled'
+SET status = 'cancelled',
+    cancellation_reason = $2,
+    cancelled_by = $3,
+    cancelled_at = NOW(),
+    ics_sequence = ics_sequence + 1
 WHERE id = $1
-RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at
+RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence
 `
 
-func (q *Queries) CancelAppointment(ctx context.Context, id int32) (Appointment, error) {
-	row := q.queryRow(ctx, q.cancelAppointmentStmt, cancelAppointment, id)
+type CancelAppointmentParams struct {
+	ID                 int32          `json:"id"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+}
+
+func (q *Queries) CancelAppointment(ctx context.Context, arg CancelAppointmentParams) (Appointment, error) {
+	row := q.queryRow(ctx, q.cancelAppointmentStmt, cancelAppointment, arg.ID, arg.CancellationReason, arg.CancelledBy)
 	var i Appointment
 	err := row.Scan(
 		&i.ID,
@@ -31,30 +43,61 @@ func (q *Queries) CancelAppointment(ctx context.Context, id int32) (Appointment,
 		&i.Status,
 		&i.QrCode,
 		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
 	)
 	return i, err
 }
 
+const countOverlappingAppointments = `-- name: CountOverlappingAppointments :one
+SELECT COUNT(*) FROM appointments
+WHERE host_id = $1
+  AND appointment_date = $2
+  AND status <> 'cancelled'
+  AND start_time < $3
+  AND end_time > $4
+  AND id IS DISTINCT FROM $5
+`
+
+type CountOverlappingAppointmentsParams struct {
+	HostID          int32         `json:"host_id"`
+	AppointmentDate time.Time     `json:"appointment_date"`
+	EndTime         time.Time     `json:"end_time"`
+	StartTime       time.Time     `json:"start_time"`
+	ExcludeID       sql.NullInt32 `json:"exclude_id"`
+}
+
+func (q *Queries) CountOverlappingAppointments(ctx context.Context, arg CountOverlappingAppointmentsParams) (int64, error) {
+	row := q.queryRow(ctx, q.countOverlappingAppointmentsStmt, countOverlappingAppointments,
+		arg.HostID,
+		arg.AppointmentDate,
+		arg.EndTime,
+		arg.StartTime,
+		arg.ExcludeID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAppointment = `-- name: CreateAppointment :one
-WITH updated_visitor AS (
-  UPDATE "users"
-  SET "appointments_visited" = "appointments_visited" + 1
-  WHERE "id" = $1
-  RETURNING "id"
-),
-updated_host AS (
-  UPDATE "users"
-  SET "appointments_hosted" = "appointments_hosted" + 1
-  WHERE "id" = $2
-  RETURNING "id"
-)
 INSERT INTO appointments (
-  visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code
+  visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code,
+  purpose, purpose_category, location, host_notes, visitor_notes
 ) 
 VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7,
+  $8, $9, $10, $11, $12
 )
-RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at
+RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence
 `
 
 type CreateAppointmentParams struct {
@@ -65,8 +108,15 @@ type CreateAppointmentParams struct {
 	EndTime         time.Time      `json:"end_time"`
 	Status          sql.NullString `json:"status"`
 	QrCode          sql.NullString `json:"qr_code"`
+	Purpose         sql.NullString `json:"purpose"`
+	PurposeCategory sql.NullString `json:"purpose_category"`
+	Location        sql.NullString `json:"location"`
+	HostNotes       sql.NullString `json:"host_notes"`
+	VisitorNotes    sql.NullString `json:"visitor_notes"`
 }
 
+// User counters and appointment_stats are kept in sync by the
+// appointments_sync_counters trigger.
 func (q *Queries) CreateAppointment(ctx context.Context, arg CreateAppointmentParams) (Appointment, error) {
 	row := q.queryRow(ctx, q.createAppointmentStmt, createAppointment,
 		arg.VisitorID,
@@ -76,6 +126,11 @@ func (q *Queries) CreateAppointment(ctx context.Context, arg CreateAppointmentPa
 		arg.EndTime,
 		arg.Status,
 		arg.QrCode,
+		arg.Purpose,
+		arg.PurposeCategory,
+		arg.Location,
+		arg.HostNotes,
+		arg.VisitorNotes,
 	)
 	var i Appointment
 	err := row.Scan(
@@ -88,6 +143,16 @@ func (q *Queries) CreateAppointment(ctx context.Context, arg CreateAppointmentPa
 		&i.Status,
 		&i.QrCode,
 		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
 	)
 	return i, err
 }
@@ -102,8 +167,68 @@ func (q *Queries) DeleteAppointment(ctx context.Context, id int32) error {
 	return err
 }
 
+const getAppointmentBadge = `-- name: GetAppointmentBadge :one
+SELECT
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time,
+  a.status, a.qr_code, a.location, a.purpose, a.purpose_category, a.ics_sequence,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  host.department AS host_department,
+  visitor.email AS visitor_email,
+  host.email AS host_email
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE a.id = $1
+`
+
+type GetAppointmentBadgeRow struct {
+	ID              int32          `json:"id"`
+	VisitorID       int32          `json:"visitor_id"`
+	HostID          int32          `json:"host_id"`
+	AppointmentDate time.Time      `json:"appointment_date"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	Status          sql.NullString `json:"status"`
+	QrCode          sql.NullString `json:"qr_code"`
+	Location        sql.NullString `json:"location"`
+	Purpose         sql.NullString `json:"purpose"`
+	PurposeCategory sql.NullString `json:"purpose_category"`
+	IcsSequence     int32          `json:"ics_sequence"`
+	VisitorName     string         `json:"visitor_name"`
+	HostName        string         `json:"host_name"`
+	HostDepartment  sql.NullString `json:"host_department"`
+	VisitorEmail    sql.NullString `json:"visitor_email"`
+	HostEmail       sql.NullString `json:"host_email"`
+}
+
+func (q *Queries) GetAppointmentBadge(ctx context.Context, id int32) (GetAppointmentBadgeRow, error) {
+	row := q.queryRow(ctx, q.getAppointmentBadgeStmt, getAppointmentBadge, id)
+	var i GetAppointmentBadgeRow
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Status,
+		&i.QrCode,
+		&i.Location,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.IcsSequence,
+		&i.VisitorName,
+		&i.HostName,
+		&i.HostDepartment,
+		&i.VisitorEmail,
+		&i.HostEmail,
+	)
+	return i, err
+}
+
 const getAppointmentByID = `-- name: GetAppointmentByID :one
-SELECT id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at FROM appointments
+SELECT id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence FROM appointments
 WHERE id = $1
 `
 
@@ -120,13 +245,23 @@ func (q *Queries) GetAppointmentByID(ctx context.Context, id int32) (Appointment
 		&i.Status,
 		&i.QrCode,
 		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
 	)
 	return i, err
 }
 
 const getAppointmentByQRCode = `-- name: GetAppointmentByQRCode :one
 SELECT 
-  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at,
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at, a.purpose, a.purpose_category, a.location, a.host_notes, a.visitor_notes, a.reminder_sent_at, a.ics_sequence,
   host.first_name || ' ' || host.last_name AS host_name,
   visitor.first_name || ' ' || visitor.last_name AS visitor_name
 FROM appointments a
@@ -137,17 +272,27 @@ LIMIT 1
 `
 
 type GetAppointmentByQRCodeRow struct {
-	ID              int32          `json:"id"`
-	VisitorID       int32          `json:"visitor_id"`
-	HostID          int32          `json:"host_id"`
-	AppointmentDate time.Time      `json:"appointment_date"`
-	StartTime       time.Time      `json:"start_time"`
-	EndTime         time.Time      `json:"end_time"`
-	Status          sql.NullString `json:"status"`
-	QrCode          sql.NullString `json:"qr_code"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
-	HostName        interface{}    `json:"host_name"`
-	VisitorName     interface{}    `json:"visitor_name"`
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	HostName           interface{}    `json:"host_name"`
+	VisitorName        interface{}    `json:"visitor_name"`
 }
 
 func (q *Queries) GetAppointmentByQRCode(ctx context.Context, qrCode sql.NullString) (GetAppointmentByQRCodeRow, error) {
@@ -163,12 +308,55 @@ func (q *Queries) GetAppointmentByQRCode(ctx context.Context, qrCode sql.NullStr
 		&i.Status,
 		&i.QrCode,
 		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
 		&i.HostName,
 		&i.VisitorName,
 	)
 	return i, err
 }
 
+const getAppointmentForUpdate = `-- name: GetAppointmentForUpdate :one
+SELECT id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence FROM appointments
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetAppointmentForUpdate(ctx context.Context, id int32) (Appointment, error) {
+	row := q.queryRow(ctx, q.getAppointmentForUpdateStmt, getAppointmentForUpdate, id)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Status,
+		&i.QrCode,
+		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
+	)
+	return i, err
+}
+
 const getUserAppointmentStats = `-- name: GetUserAppointmentStats :one
 SELECT 
   u.appointments_hosted,
@@ -195,7 +383,7 @@ func (q *Queries) GetUserAppointmentStats(ctx context.Context, id int32) (GetUse
 
 const listAppointmentsByDate = `-- name: ListAppointmentsByDate :many
 SELECT 
-    a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at,
+    a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at, a.purpose, a.purpose_category, a.location, a.host_notes, a.visitor_notes, a.reminder_sent_at, a.ics_sequence,
     host.first_name || ' ' || host.last_name AS host_name,
     visitor.first_name || ' ' || visitor.last_name AS visitor_name
 FROM appointments a
@@ -206,17 +394,27 @@ ORDER BY a.start_time
 `
 
 type ListAppointmentsByDateRow struct {
-	ID              int32          `json:"id"`
-	VisitorID       int32          `json:"visitor_id"`
-	HostID          int32          `json:"host_id"`
-	AppointmentDate time.Time      `json:"appointment_date"`
-	StartTime       time.Time      `json:"start_time"`
-	EndTime         time.Time      `json:"end_time"`
-	Status          sql.NullString `json:"status"`
-	QrCode          sql.NullString `json:"qr_code"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
-	HostName        interface{}    `json:"host_name"`
-	VisitorName     interface{}    `json:"visitor_name"`
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	HostName           interface{}    `json:"host_name"`
+	VisitorName        interface{}    `json:"visitor_name"`
 }
 
 func (q *Queries) ListAppointmentsByDate(ctx context.Context, appointmentDate time.Time) ([]ListAppointmentsByDateRow, error) {
@@ -238,6 +436,16 @@ func (q *Queries) ListAppointmentsByDate(ctx context.Context, appointmentDate ti
 			&i.Status,
 			&i.QrCode,
 			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.HostNotes,
+			&i.VisitorNotes,
+			&i.ReminderSentAt,
+			&i.IcsSequence,
 			&i.HostName,
 			&i.VisitorName,
 		); err != nil {
@@ -256,8 +464,8 @@ func (q *Queries) ListAppointmentsByDate(ctx context.Context, appointmentDate ti
 
 const listAppointmentsByHost = `-- name: ListAppointmentsByHost :many
 SELECT 
-  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, 
-  u.first_name || ' ' || u.last_name AS visitor_name,
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at, a.purpose, a.purpose_category, a.location, a.host_notes, a.visitor_notes, a.reminder_sent_at, a.ics_sequence, 
+  (u.first_name || ' ' || u.last_name)::text AS visitor_name,
   u.role AS role
 FROM appointments a
 JOIN users u ON a.visitor_id = u.id
@@ -266,17 +474,27 @@ ORDER BY a.appointment_date DESC
 `
 
 type ListAppointmentsByHostRow struct {
-	ID              int32          `json:"id"`
-	VisitorID       int32          `json:"visitor_id"`
-	HostID          int32          `json:"host_id"`
-	AppointmentDate time.Time      `json:"appointment_date"`
-	StartTime       time.Time      `json:"start_time"`
-	EndTime         time.Time      `json:"end_time"`
-	Status          sql.NullString `json:"status"`
-	QrCode          sql.NullString `json:"qr_code"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
-	VisitorName     interface{}    `json:"visitor_name"`
-	Role            sql.NullString `json:"role"`
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	VisitorName        string         `json:"visitor_name"`
+	Role               sql.NullString `json:"role"`
 }
 
 func (q *Queries) ListAppointmentsByHost(ctx context.Context, hostID int32) ([]ListAppointmentsByHostRow, error) {
@@ -298,6 +516,16 @@ func (q *Queries) ListAppointmentsByHost(ctx context.Context, hostID int32) ([]L
 			&i.Status,
 			&i.QrCode,
 			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.HostNotes,
+			&i.VisitorNotes,
+			&i.ReminderSentAt,
+			&i.IcsSequence,
 			&i.VisitorName,
 			&i.Role,
 		); err != nil {
@@ -316,8 +544,10 @@ func (q *Queries) ListAppointmentsByHost(ctx context.Context, hostID int32) ([]L
 
 const listAppointmentsByVisitor = `-- name: ListAppointmentsByVisitor :many
 SELECT 
-  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, 
-  u.first_name || ' ' || u.last_name AS host_name,
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time,
+  a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at,
+  a.purpose, a.purpose_category, a.location, a.visitor_notes, a.ics_sequence,
+  (u.first_name || ' ' || u.last_name)::text AS host_name,
   u.role AS role
 FROM appointments a
 JOIN users u ON a.host_id = u.id
@@ -326,17 +556,25 @@ ORDER BY a.appointment_date DESC
 `
 
 type ListAppointmentsByVisitorRow struct {
-	ID              int32          `json:"id"`
-	VisitorID       int32          `json:"visitor_id"`
-	HostID          int32          `json:"host_id"`
-	AppointmentDate time.Time      `json:"appointment_date"`
-	StartTime       time.Time      `json:"start_time"`
-	EndTime         time.Time      `json:"end_time"`
-	Status          sql.NullString `json:"status"`
-	QrCode          sql.NullString `json:"qr_code"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
-	HostName        interface{}    `json:"host_name"`
-	Role            sql.NullString `json:"role"`
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	HostName           string         `json:"host_name"`
+	Role               sql.NullString `json:"role"`
 }
 
 func (q *Queries) ListAppointmentsByVisitor(ctx context.Context, visitorID int32) ([]ListAppointmentsByVisitorRow, error) {
@@ -358,6 +596,14 @@ func (q *Queries) ListAppointmentsByVisitor(ctx context.Context, visitorID int32
 			&i.Status,
 			&i.QrCode,
 			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.VisitorNotes,
+			&i.IcsSequence,
 			&i.HostName,
 			&i.Role,
 		); err != nil {
@@ -374,11 +620,401 @@ func (q *Queries) ListAppointmentsByVisitor(ctx context.Context, visitorID int32
 	return items, nil
 }
 
+const listAppointmentsPageAsc = `-- name: ListAppointmentsPageAsc :many
+SELECT 
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at, a.purpose, a.purpose_category, a.location, a.host_notes, a.visitor_notes, a.reminder_sent_at, a.ics_sequence,
+  host.first_name || ' ' || host.last_name AS host_name,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE ($1::int IS NULL OR a.host_id = $1::int)
+  AND ($2::int IS NULL OR a.visitor_id = $2::int)
+  AND ($3::int IS NULL OR a.host_id = $3::int OR a.visitor_id = $3::int)
+  AND (cardinality($4::text[]) = 0 OR a.status = ANY($4::text[]))
+  AND ($5::date IS NULL OR a.appointment_date >= $5::date)
+  AND ($6::date IS NULL OR a.appointment_date <= $6::date)
+  AND (
+    $7::text IS NULL
+    OR host.first_name || ' ' || host.last_name ILIKE '%' || $7::text || '%'
+    OR visitor.first_name || ' ' || visitor.last_name ILIKE '%' || $7::text || '%'
+  )
+  AND (
+    $8::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) > ($9::date, $10::time, $8::int)
+  )
+ORDER BY a.appointment_date ASC, a.start_time ASC, a.id ASC
+LIMIT $11
+`
+
+type ListAppointmentsPageAscParams struct {
+	HostID        sql.NullInt32  `json:"host_id"`
+	VisitorID     sql.NullInt32  `json:"visitor_id"`
+	ParticipantID sql.NullInt32  `json:"participant_id"`
+	Statuses      []string       `json:"statuses"`
+	FromDate      sql.NullTime   `json:"from_date"`
+	ToDate        sql.NullTime   `json:"to_date"`
+	Search        sql.NullString `json:"search"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorDate    sql.NullTime   `json:"cursor_date"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+type ListAppointmentsPageAscRow struct {
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	HostName           interface{}    `json:"host_name"`
+	VisitorName        interface{}    `json:"visitor_name"`
+}
+
+func (q *Queries) ListAppointmentsPageAsc(ctx context.Context, arg ListAppointmentsPageAscParams) ([]ListAppointmentsPageAscRow, error) {
+	rows, err := q.query(ctx, q.listAppointmentsPageAscStmt, listAppointmentsPageAsc,
+		arg.HostID,
+		arg.VisitorID,
+		arg.ParticipantID,
+		pq.Array(arg.Statuses),
+		arg.FromDate,
+		arg.ToDate,
+		arg.Search,
+		arg.CursorID,
+		arg.CursorDate,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAppointmentsPageAscRow{}
+	for rows.Next() {
+		var i ListAppointmentsPageAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitorID,
+			&i.HostID,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Status,
+			&i.QrCode,
+			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.HostNotes,
+			&i.VisitorNotes,
+			&i.ReminderSentAt,
+			&i.IcsSequence,
+			&i.HostName,
+			&i.VisitorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAppointmentsPageDesc = `-- name: ListAppointmentsPageDesc :many
+SELECT 
+  a.id, a.visitor_id, a.host_id, a.appointment_date, a.start_time, a.end_time, a.status, a.qr_code, a.created_at, a.cancellation_reason, a.cancelled_by, a.cancelled_at, a.purpose, a.purpose_category, a.location, a.host_notes, a.visitor_notes, a.reminder_sent_at, a.ics_sequence,
+  host.first_name || ' ' || host.last_name AS host_name,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE ($1::int IS NULL OR a.host_id = $1::int)
+  AND ($2::int IS NULL OR a.visitor_id = $2::int)
+  AND ($3::int IS NULL OR a.host_id = $3::int OR a.visitor_id = $3::int)
+  AND (cardinality($4::text[]) = 0 OR a.status = ANY($4::text[]))
+  AND ($5::date IS NULL OR a.appointment_date >= $5::date)
+  AND ($6::date IS NULL OR a.appointment_date <= $6::date)
+  AND (
+    $7::text IS NULL
+    OR host.first_name || ' ' || host.last_name ILIKE '%' || $7::text || '%'
+    OR visitor.first_name || ' ' || visitor.last_name ILIKE '%' || $7::text || '%'
+  )
+  AND (
+    $8::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) < ($9::date, $10::time, $8::int)
+  )
+ORDER BY a.appointment_date DESC, a.start_time DESC, a.id DESC
+LIMIT $11
+`
+
+type ListAppointmentsPageDescParams struct {
+	HostID        sql.NullInt32  `json:"host_id"`
+	VisitorID     sql.NullInt32  `json:"visitor_id"`
+	ParticipantID sql.NullInt32  `json:"participant_id"`
+	Statuses      []string       `json:"statuses"`
+	FromDate      sql.NullTime   `json:"from_date"`
+	ToDate        sql.NullTime   `json:"to_date"`
+	Search        sql.NullString `json:"search"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorDate    sql.NullTime   `json:"cursor_date"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+type ListAppointmentsPageDescRow struct {
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
+	HostName           interface{}    `json:"host_name"`
+	VisitorName        interface{}    `json:"visitor_name"`
+}
+
+func (q *Queries) ListAppointmentsPageDesc(ctx context.Context, arg ListAppointmentsPageDescParams) ([]ListAppointmentsPageDescRow, error) {
+	rows, err := q.query(ctx, q.listAppointmentsPageDescStmt, listAppointmentsPageDesc,
+		arg.HostID,
+		arg.VisitorID,
+		arg.ParticipantID,
+		pq.Array(arg.Statuses),
+		arg.FromDate,
+		arg.ToDate,
+		arg.Search,
+		arg.CursorID,
+		arg.CursorDate,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAppointmentsPageDescRow{}
+	for rows.Next() {
+		var i ListAppointmentsPageDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitorID,
+			&i.HostID,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Status,
+			&i.QrCode,
+			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.HostNotes,
+			&i.VisitorNotes,
+			&i.ReminderSentAt,
+			&i.IcsSequence,
+			&i.HostName,
+			&i.VisitorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueReminders = `-- name: ListDueReminders :many
+SELECT id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence FROM appointments
+WHERE status = 'pending'
+  AND reminder_sent_at IS NULL
+  AND appointment_date + start_time > LOCALTIMESTAMP
+  AND appointment_date + start_time <= LOCALTIMESTAMP + make_interval(secs => $1::int)
+ORDER BY appointment_date, start_time
+LIMIT $2
+`
+
+type ListDueRemindersParams struct {
+	LeadSeconds int32 `json:"lead_seconds"`
+	BatchSize   int32 `json:"batch_size"`
+}
+
+// Pending appointments starting within the lead time whose visitor has not
+// been reminded yet.
+func (q *Queries) ListDueReminders(ctx context.Context, arg ListDueRemindersParams) ([]Appointment, error) {
+	rows, err := q.query(ctx, q.listDueRemindersStmt, listDueReminders, arg.LeadSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Appointment{}
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitorID,
+			&i.HostID,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Status,
+			&i.QrCode,
+			&i.CreatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.Location,
+			&i.HostNotes,
+			&i.VisitorNotes,
+			&i.ReminderSentAt,
+			&i.IcsSequence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markReminderSent = `-- name: MarkReminderSent :one
+UPDATE appointments
+SET reminder_sent_at = now()
+WHERE id = $1
+  AND reminder_sent_at IS NULL
+RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence
+`
+
+// Only one replica gets the row back, so a visitor is reminded once.
+func (q *Queries) MarkReminderSent(ctx context.Context, id int32) (Appointment, error) {
+	row := q.queryRow(ctx, q.markReminderSentStmt, markReminderSent, id)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Status,
+		&i.QrCode,
+		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
+	)
+	return i, err
+}
+
+const rescheduleAppointment = `-- name: RescheduleAppointment :one
+UPDATE appointments
+SET appointment_date = $2,
+    start_time = $3,
+    end_time = $4,
+    reminder_sent_at = NULL,
+    ics_sequence = ics_sequence + 1
+WHERE id = $1
+RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence
+`
+
+type RescheduleAppointmentParams struct {
+	ID              int32     `json:"id"`
+	AppointmentDate time.Time `json:"appointment_date"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+}
+
+func (q *Queries) RescheduleAppointment(ctx context.Context, arg RescheduleAppointmentParams) (Appointment, error) {
+	row := q.queryRow(ctx, q.rescheduleAppointmentStmt, rescheduleAppointment,
+		arg.ID,
+		arg.AppointmentDate,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Status,
+		&i.QrCode,
+		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
+	)
+	return i, err
+}
+
 const updateAppointmentStatus = `-- name: UpdateAppointmentStatus :one
 UPDATE appointments
 SET status = $2
 WHERE id = $1
-RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at
+RETURNING id, visitor_id, host_id, appointment_date, start_time, end_time, status, qr_code, created_at, cancellation_reason, cancelled_by, cancelled_at, purpose, purpose_category, location, host_notes, visitor_notes, reminder_sent_at, ics_sequence
 `
 
 type UpdateAppointmentStatusParams struct {
@@ -399,6 +1035,16 @@ func (q *Queries) UpdateAppointmentStatus(ctx context.Context, arg UpdateAppoint
 		&i.Status,
 		&i.QrCode,
 		&i.CreatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.Purpose,
+		&i.PurposeCategory,
+		&i.Location,
+		&i.HostNotes,
+		&i.VisitorNotes,
+		&i.ReminderSentAt,
+		&i.IcsSequence,
 	)
 	return i, err
 }
@@ -0,0 +1,172 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: calendar.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createCalendarFeed = `-- name: CreateCalendarFeed :one
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO NOTHING
+RETURNING user_id, token_hash, created_at
+`
+
+type CreateCalendarFeedParams struct {
+	UserID    int32  `json:"user_id"`
+	TokenHash string `json:"token_hash"`
+}
+
+// Issues the user's feed the first time; returns no row if they already have
+// one, since its token cannot be shown again.
+func (q *Queries) CreateCalendarFeed(ctx context.Context, arg CreateCalendarFeedParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.createCalendarFeedStmt, createCalendarFeed, arg.UserID, arg.TokenHash)
+	var i CalendarFeed
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
+	return i, err
+}
+
+const getCalendarFeed = `-- name: GetCalendarFeed :one
+SELECT user_id, token_hash, created_at FROM calendar_feeds
+WHERE user_id = $1
+`
+
+func (q *Queries) GetCalendarFeed(ctx context.Context, userID int32) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.getCalendarFeedStmt, getCalendarFeed, userID)
+	var i CalendarFeed
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
+	return i, err
+}
+
+const getCalendarFeedByTokenHash = `-- name: GetCalendarFeedByTokenHash :one
+SELECT user_id, token_hash, created_at FROM calendar_feeds
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.getCalendarFeedByTokenHashStmt, getCalendarFeedByTokenHash, tokenHash)
+	var i CalendarFeed
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
+	return i, err
+}
+
+const getCalendarSummary = `-- name: GetCalendarSummary :many
+SELECT
+  d.day::date AS day,
+  COALESCE(s.total, 0)::int AS total,
+  COALESCE(s.pending, 0)::int AS pending,
+  COALESCE(s.ongoing, 0)::int AS ongoing,
+  COALESCE(s.completed, 0)::int AS completed,
+  COALESCE(s.cancelled, 0)::int AS cancelled,
+  COALESCE(to_char(s.first_start_time, 'HH24:MI'), '')::text AS first_start_time,
+  COALESCE(to_char(s.last_end_time, 'HH24:MI'), '')::text AS last_end_time,
+  COALESCE(av.available_minutes, 0)::int AS available_minutes,
+  COALESCE(b.booked_minutes, 0)::int AS booked_minutes
+FROM generate_series($1::date, $2::date, interval '1 day') AS d(day)
+LEFT JOIN LATERAL (
+  SELECT
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE a.status = 'pending') AS pending,
+    COUNT(*) FILTER (WHERE a.status = 'ongoing') AS ongoing,
+    COUNT(*) FILTER (WHERE a.status = 'completed') AS completed,
+    COUNT(*) FILTER (WHERE a.status = 'cancelled') AS cancelled,
+    MIN(a.start_time) FILTER (WHERE a.status <> 'cancelled') AS first_start_time,
+    MAX(a.end_time) FILTER (WHERE a.status <> 'cancelled') AS last_end_time
+  FROM appointments a
+  WHERE a.appointment_date = d.day::date
+    AND ($3::int IS NULL OR a.host_id = $3::int OR a.visitor_id = $3::int)
+) s ON true
+LEFT JOIN LATERAL (
+  SELECT SUM(EXTRACT(EPOCH FROM (av.end_time - av.start_time)) / 60) AS available_minutes
+  FROM availability av
+  WHERE av.day_of_week = EXTRACT(ISODOW FROM d.day)
+    AND av.status = 'available'
+    AND ($3::int IS NULL OR av.user_id = $3::int)
+) av ON true
+LEFT JOIN LATERAL (
+  SELECT SUM(EXTRACT(EPOCH FROM (a.end_time - a.start_time)) / 60) AS booked_minutes
+  FROM appointments a
+  WHERE a.appointment_date = d.day::date
+    AND a.status <> 'cancelled'
+    AND ($3::int IS NULL OR a.host_id = $3::int)
+) b ON true
+ORDER BY d.day
+`
+
+type GetCalendarSummaryParams struct {
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	UserID    sql.NullInt32 `json:"user_id"`
+}
+
+type GetCalendarSummaryRow struct {
+	Day              time.Time `json:"day"`
+	Total            int32     `json:"total"`
+	Pending          int32     `json:"pending"`
+	Ongoing          int32     `json:"ongoing"`
+	Completed        int32     `json:"completed"`
+	Cancelled        int32     `json:"cancelled"`
+	FirstStartTime   string    `json:"first_start_time"`
+	LastEndTime      string    `json:"last_end_time"`
+	AvailableMinutes int32     `json:"available_minutes"`
+	BookedMinutes    int32     `json:"booked_minutes"`
+}
+
+func (q *Queries) GetCalendarSummary(ctx context.Context, arg GetCalendarSummaryParams) ([]GetCalendarSummaryRow, error) {
+	rows, err := q.query(ctx, q.getCalendarSummaryStmt, getCalendarSummary, arg.StartDate, arg.EndDate, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCalendarSummaryRow{}
+	for rows.Next() {
+		var i GetCalendarSummaryRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Total,
+			&i.Pending,
+			&i.Ongoing,
+			&i.Completed,
+			&i.Cancelled,
+			&i.FirstStartTime,
+			&i.LastEndTime,
+			&i.AvailableMinutes,
+			&i.BookedMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateCalendarFeed = `-- name: RotateCalendarFeed :one
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()
+RETURNING user_id, token_hash, created_at
+`
+
+type RotateCalendarFeedParams struct {
+	UserID    int32  `json:"user_id"`
+	TokenHash string `json:"token_hash"`
+}
+
+func (q *Queries) RotateCalendarFeed(ctx context.Context, arg RotateCalendarFeedParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.rotateCalendarFeedStmt, rotateCalendarFeed, arg.UserID, arg.TokenHash)
+	var i CalendarFeed
+	err := row.Scan(&i.UserID, &i.TokenHash, &i.CreatedAt)
+	return i, err
+}
@@ -0,0 +1,30 @@
+package db
+
+import "context"
+
+type SyncCalendarImportTxParams struct {
+	ImportID int32
+	Blocks   []CreateBusyBlockParams
+}
+
+// SyncCalendarImportTx replaces an import's busy blocks with a freshly
+// expanded set and records the sync.
+func (store *SQLStore) SyncCalendarImportTx(ctx context.Context, arg SyncCalendarImportTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteBusyBlocksByImport(ctx, arg.ImportID); err != nil {
+			return err
+		}
+
+		for _, block := range arg.Blocks {
+			block.ImportID = arg.ImportID
+			if err := q.CreateBusyBlock(ctx, block); err != nil {
+				return err
+			}
+		}
+
+		return q.MarkCalendarImportSynced(ctx, MarkCalendarImportSyncedParams{
+			ID:        arg.ImportID,
+			BusyCount: int32(len(arg.Blocks)),
+		})
+	})
+}
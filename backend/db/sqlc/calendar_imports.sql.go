@@ -0,0 +1,383 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: calendar_imports.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const countOverlappingBusyBlocks = `-- name: CountOverlappingBusyBlocks :one
+SELECT COUNT(*) FROM busy_blocks
+WHERE user_id = $1
+  AND starts_at < $2
+  AND ends_at > $3
+`
+
+type CountOverlappingBusyBlocksParams struct {
+	UserID int32     `json:"user_id"`
+	Until  time.Time `json:"until"`
+	Since  time.Time `json:"since"`
+}
+
+func (q *Queries) CountOverlappingBusyBlocks(ctx context.Context, arg CountOverlappingBusyBlocksParams) (int64, error) {
+	row := q.queryRow(ctx, q.countOverlappingBusyBlocksStmt, countOverlappingBusyBlocks, arg.UserID, arg.Until, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createBusyBlock = `-- name: CreateBusyBlock :exec
+INSERT INTO busy_blocks (import_id, user_id, starts_at, ends_at, summary)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateBusyBlockParams struct {
+	ImportID int32     `json:"import_id"`
+	UserID   int32     `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Summary  string    `json:"summary"`
+}
+
+func (q *Queries) CreateBusyBlock(ctx context.Context, arg CreateBusyBlockParams) error {
+	_, err := q.exec(ctx, q.createBusyBlockStmt, createBusyBlock,
+		arg.ImportID,
+		arg.UserID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Summary,
+	)
+	return err
+}
+
+const createCalendarImport = `-- name: CreateCalendarImport :one
+INSERT INTO calendar_imports (user_id, name, url, ics_data)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, url, ics_data, busy_count, last_synced_at, last_attempted_at, last_error, created_at
+`
+
+type CreateCalendarImportParams struct {
+	UserID  int32          `json:"user_id"`
+	Name    string         `json:"name"`
+	Url     sql.NullString `json:"url"`
+	IcsData sql.NullString `json:"ics_data"`
+}
+
+func (q *Queries) CreateCalendarImport(ctx context.Context, arg CreateCalendarImportParams) (CalendarImport, error) {
+	row := q.queryRow(ctx, q.createCalendarImportStmt, createCalendarImport,
+		arg.UserID,
+		arg.Name,
+		arg.Url,
+		arg.IcsData,
+	)
+	var i CalendarImport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.IcsData,
+		&i.BusyCount,
+		&i.LastSyncedAt,
+		&i.LastAttemptedAt,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteBusyBlocksByImport = `-- name: DeleteBusyBlocksByImport :exec
+DELETE FROM busy_blocks
+WHERE import_id = $1
+`
+
+func (q *Queries) DeleteBusyBlocksByImport(ctx context.Context, importID int32) error {
+	_, err := q.exec(ctx, q.deleteBusyBlocksByImportStmt, deleteBusyBlocksByImport, importID)
+	return err
+}
+
+const deleteCalendarImport = `-- name: DeleteCalendarImport :exec
+DELETE FROM calendar_imports
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCalendarImport(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.deleteCalendarImportStmt, deleteCalendarImport, id)
+	return err
+}
+
+const getCalendarImport = `-- name: GetCalendarImport :one
+SELECT id, user_id, name, url, ics_data, busy_count, last_synced_at, last_attempted_at, last_error, created_at FROM calendar_imports
+WHERE id = $1
+`
+
+func (q *Queries) GetCalendarImport(ctx context.Context, id int32) (CalendarImport, error) {
+	row := q.queryRow(ctx, q.getCalendarImportStmt, getCalendarImport, id)
+	var i CalendarImport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.IcsData,
+		&i.BusyCount,
+		&i.LastSyncedAt,
+		&i.LastAttemptedAt,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listBusyBlocks = `-- name: ListBusyBlocks :many
+SELECT id, import_id, user_id, starts_at, ends_at, summary FROM busy_blocks
+WHERE user_id = $1
+  AND starts_at < $2
+  AND ends_at > $3
+ORDER BY starts_at
+`
+
+type ListBusyBlocksParams struct {
+	UserID int32     `json:"user_id"`
+	Until  time.Time `json:"until"`
+	Since  time.Time `json:"since"`
+}
+
+func (q *Queries) ListBusyBlocks(ctx context.Context, arg ListBusyBlocksParams) ([]BusyBlock, error) {
+	rows, err := q.query(ctx, q.listBusyBlocksStmt, listBusyBlocks, arg.UserID, arg.Until, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []BusyBlock{}
+	for rows.Next() {
+		var i BusyBlock
+		if err := rows.Scan(
+			&i.ID,
+			&i.ImportID,
+			&i.UserID,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Summary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCalendarImportsByUser = `-- name: ListCalendarImportsByUser :many
+SELECT id, user_id, name, url, busy_count, last_synced_at, last_attempted_at, last_error, created_at
+FROM calendar_imports
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+type ListCalendarImportsByUserRow struct {
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Url             sql.NullString `json:"url"`
+	BusyCount       int32          `json:"busy_count"`
+	LastSyncedAt    sql.NullTime   `json:"last_synced_at"`
+	LastAttemptedAt sql.NullTime   `json:"last_attempted_at"`
+	LastError       sql.NullString `json:"last_error"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+func (q *Queries) ListCalendarImportsByUser(ctx context.Context, userID int32) ([]ListCalendarImportsByUserRow, error) {
+	rows, err := q.query(ctx, q.listCalendarImportsByUserStmt, listCalendarImportsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCalendarImportsByUserRow{}
+	for rows.Next() {
+		var i ListCalendarImportsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Url,
+			&i.BusyCount,
+			&i.LastSyncedAt,
+			&i.LastAttemptedAt,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueCalendarImports = `-- name: ListDueCalendarImports :many
+SELECT id, user_id, name, url, ics_data, busy_count, last_synced_at, last_attempted_at, last_error, created_at FROM calendar_imports
+WHERE last_attempted_at IS NULL
+   OR last_attempted_at < now() - make_interval(secs => $1::int)
+ORDER BY last_attempted_at NULLS FIRST
+LIMIT $2
+`
+
+type ListDueCalendarImportsParams struct {
+	IntervalSeconds int32 `json:"interval_seconds"`
+	BatchSize       int32 `json:"batch_size"`
+}
+
+// Imports not tried within the sync interval, oldest first, so a failing URL
+// waits its turn instead of being retried on every tick.
+func (q *Queries) ListDueCalendarImports(ctx context.Context, arg ListDueCalendarImportsParams) ([]CalendarImport, error) {
+	rows, err := q.query(ctx, q.listDueCalendarImportsStmt, listDueCalendarImports, arg.IntervalSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CalendarImport{}
+	for rows.Next() {
+		var i CalendarImport
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Url,
+			&i.IcsData,
+			&i.BusyCount,
+			&i.LastSyncedAt,
+			&i.LastAttemptedAt,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHostAppointmentTimes = `-- name: ListHostAppointmentTimes :many
+SELECT start_time, end_time FROM appointments
+WHERE host_id = $1
+  AND appointment_date = $2
+  AND status <> 'cancelled'
+ORDER BY start_time
+`
+
+type ListHostAppointmentTimesParams struct {
+	HostID          int32     `json:"host_id"`
+	AppointmentDate time.Time `json:"appointment_date"`
+}
+
+type ListHostAppointmentTimesRow struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+func (q *Queries) ListHostAppointmentTimes(ctx context.Context, arg ListHostAppointmentTimesParams) ([]ListHostAppointmentTimesRow, error) {
+	rows, err := q.query(ctx, q.listHostAppointmentTimesStmt, listHostAppointmentTimes, arg.HostID, arg.AppointmentDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListHostAppointmentTimesRow{}
+	for rows.Next() {
+		var i ListHostAppointmentTimesRow
+		if err := rows.Scan(&i.StartTime, &i.EndTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markCalendarImportFailed = `-- name: MarkCalendarImportFailed :exec
+UPDATE calendar_imports
+SET last_attempted_at = now(),
+    last_error = $2
+WHERE id = $1
+`
+
+type MarkCalendarImportFailedParams struct {
+	ID        int32          `json:"id"`
+	LastError sql.NullString `json:"last_error"`
+}
+
+func (q *Queries) MarkCalendarImportFailed(ctx context.Context, arg MarkCalendarImportFailedParams) error {
+	_, err := q.exec(ctx, q.markCalendarImportFailedStmt, markCalendarImportFailed, arg.ID, arg.LastError)
+	return err
+}
+
+const markCalendarImportSynced = `-- name: MarkCalendarImportSynced :exec
+UPDATE calendar_imports
+SET last_attempted_at = now(),
+    last_synced_at = now(),
+    last_error = NULL,
+    busy_count = $2
+WHERE id = $1
+`
+
+type MarkCalendarImportSyncedParams struct {
+	ID        int32 `json:"id"`
+	BusyCount int32 `json:"busy_count"`
+}
+
+func (q *Queries) MarkCalendarImportSynced(ctx context.Context, arg MarkCalendarImportSyncedParams) error {
+	_, err := q.exec(ctx, q.markCalendarImportSyncedStmt, markCalendarImportSynced, arg.ID, arg.BusyCount)
+	return err
+}
+
+const requestCalendarImportSync = `-- name: RequestCalendarImportSync :one
+UPDATE calendar_imports
+SET last_attempted_at = NULL
+WHERE id = $1
+RETURNING id, user_id, name, url, ics_data, busy_count, last_synced_at, last_attempted_at, last_error, created_at
+`
+
+// Makes the import due, so the worker syncs it on its next tick.
+func (q *Queries) RequestCalendarImportSync(ctx context.Context, id int32) (CalendarImport, error) {
+	row := q.queryRow(ctx, q.requestCalendarImportSyncStmt, requestCalendarImportSync, id)
+	var i CalendarImport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.IcsData,
+		&i.BusyCount,
+		&i.LastSyncedAt,
+		&i.LastAttemptedAt,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: checkin_replies.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createCheckInReply = `-- name: CreateCheckInReply :one
+INSERT INTO checkin_replies (
+  appointment_id, host_id, reply, message
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, appointment_id, host_id, reply, message, created_at
+`
+
+type CreateCheckInReplyParams struct {
+	AppointmentID int32          `json:"appointment_id"`
+	HostID        int32          `json:"host_id"`
+	Reply         string         `json:"reply"`
+	Message       sql.NullString `json:"message"`
+}
+
+func (q *Queries) CreateCheckInReply(ctx context.Context, arg CreateCheckInReplyParams) (CheckinReply, error) {
+	row := q.queryRow(ctx, q.createCheckInReplyStmt, createCheckInReply,
+		arg.AppointmentID,
+		arg.HostID,
+		arg.Reply,
+		arg.Message,
+	)
+	var i CheckinReply
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.HostID,
+		&i.Reply,
+		&i.Message,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCheckInReplies = `-- name: ListCheckInReplies :many
+SELECT id, appointment_id, host_id, reply, message, created_at FROM checkin_replies
+WHERE appointment_id = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListCheckInReplies(ctx context.Context, appointmentID int32) ([]CheckinReply, error) {
+	rows, err := q.query(ctx, q.listCheckInRepliesStmt, listCheckInReplies, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CheckinReply{}
+	for rows.Next() {
+		var i CheckinReply
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.HostID,
+			&i.Reply,
+			&i.Message,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -27,26 +27,89 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.cancelAppointmentStmt, err = db.PrepareContext(ctx, cancelAppointment); err != nil {
 		return nil, fmt.Errorf("error preparing query CancelAppointment: %w", err)
 	}
-	if q.createAppointmentStmt, err = db.PrepareContext(ctx, createAppointment); err != nil {
-		return nil, fmt.Errorf("error preparing query CreateAppointment: %w", err)
+	if q.cancelWaitlistEntryStmt, err = db.PrepareContext(ctx, cancelWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CancelWaitlistEntry: %w", err)
+	}
+	if q.claimDueNotificationsStmt, err = db.PrepareContext(ctx, claimDueNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimDueNotifications: %w", err)
+	}
+	if q.claimWaitlistOfferStmt, err = db.PrepareContext(ctx, claimWaitlistOffer); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimWaitlistOffer: %w", err)
+	}
+	if q.closeEvacuationStmt, err = db.PrepareContext(ctx, closeEvacuation); err != nil {
+		return nil, fmt.Errorf("error preparing query CloseEvacuation: %w", err)
+	}
+	if q.countOverlappingAppointmentsStmt, err = db.PrepareContext(ctx, countOverlappingAppointments); err != nil {
+		return nil, fmt.Errorf("error preparing query CountOverlappingAppointments: %w", err)
 	}
-	if q.createAppointmentLogStmt, err = db.PrepareContext(ctx, createAppointmentLog); err != nil {
-		return nil, fmt.Errorf("error preparing query CreateAppointmentLog: %w", err)
+	if q.countOverlappingBusyBlocksStmt, err = db.PrepareContext(ctx, countOverlappingBusyBlocks); err != nil {
+		return nil, fmt.Errorf("error preparing query CountOverlappingBusyBlocks: %w", err)
 	}
-	if q.createAppointmentStatsStmt, err = db.PrepareContext(ctx, createAppointmentStats); err != nil {
-		return nil, fmt.Errorf("error preparing query CreateAppointmentStats: %w", err)
+	if q.countPendingDeclarationsStmt, err = db.PrepareContext(ctx, countPendingDeclarations); err != nil {
+		return nil, fmt.Errorf("error preparing query CountPendingDeclarations: %w", err)
+	}
+	if q.createAccessEventStmt, err = db.PrepareContext(ctx, createAccessEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAccessEvent: %w", err)
+	}
+	if q.createAppointmentStmt, err = db.PrepareContext(ctx, createAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAppointment: %w", err)
 	}
 	if q.createAvailabilitySlotStmt, err = db.PrepareContext(ctx, createAvailabilitySlot); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAvailabilitySlot: %w", err)
 	}
+	if q.createBusyBlockStmt, err = db.PrepareContext(ctx, createBusyBlock); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBusyBlock: %w", err)
+	}
+	if q.createCalendarFeedStmt, err = db.PrepareContext(ctx, createCalendarFeed); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCalendarFeed: %w", err)
+	}
+	if q.createCalendarImportStmt, err = db.PrepareContext(ctx, createCalendarImport); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCalendarImport: %w", err)
+	}
+	if q.createCheckInReplyStmt, err = db.PrepareContext(ctx, createCheckInReply); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCheckInReply: %w", err)
+	}
+	if q.createDeclarationFormStmt, err = db.PrepareContext(ctx, createDeclarationForm); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeclarationForm: %w", err)
+	}
+	if q.createDeclarationFormVersionStmt, err = db.PrepareContext(ctx, createDeclarationFormVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeclarationFormVersion: %w", err)
+	}
+	if q.createEvacuationStmt, err = db.PrepareContext(ctx, createEvacuation); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEvacuation: %w", err)
+	}
+	if q.createKioskStmt, err = db.PrepareContext(ctx, createKiosk); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateKiosk: %w", err)
+	}
+	if q.createNotificationStmt, err = db.PrepareContext(ctx, createNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateNotification: %w", err)
+	}
 	if q.createOTPStmt, err = db.PrepareContext(ctx, createOTP); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateOTP: %w", err)
 	}
+	if q.createStreamTicketStmt, err = db.PrepareContext(ctx, createStreamTicket); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateStreamTicket: %w", err)
+	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
-	if q.decrementAppointmentCountStmt, err = db.PrepareContext(ctx, decrementAppointmentCount); err != nil {
-		return nil, fmt.Errorf("error preparing query DecrementAppointmentCount: %w", err)
+	if q.createVisitorPhotoStmt, err = db.PrepareContext(ctx, createVisitorPhoto); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateVisitorPhoto: %w", err)
+	}
+	if q.createWaitlistEntryStmt, err = db.PrepareContext(ctx, createWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWaitlistEntry: %w", err)
+	}
+	if q.createWaitlistOfferStmt, err = db.PrepareContext(ctx, createWaitlistOffer); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWaitlistOffer: %w", err)
+	}
+	if q.createWebhookSubscriptionStmt, err = db.PrepareContext(ctx, createWebhookSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookSubscription: %w", err)
+	}
+	if q.deadLetterExpiredLeasesStmt, err = db.PrepareContext(ctx, deadLetterExpiredLeases); err != nil {
+		return nil, fmt.Errorf("error preparing query DeadLetterExpiredLeases: %w", err)
+	}
+	if q.deleteAccessEventsByAppointmentStmt, err = db.PrepareContext(ctx, deleteAccessEventsByAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAccessEventsByAppointment: %w", err)
 	}
 	if q.deleteAppointmentStmt, err = db.PrepareContext(ctx, deleteAppointment); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAppointment: %w", err)
@@ -63,21 +126,57 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteAvailabilitySlotStmt, err = db.PrepareContext(ctx, deleteAvailabilitySlot); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAvailabilitySlot: %w", err)
 	}
+	if q.deleteBusyBlocksByImportStmt, err = db.PrepareContext(ctx, deleteBusyBlocksByImport); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBusyBlocksByImport: %w", err)
+	}
+	if q.deleteCalendarImportStmt, err = db.PrepareContext(ctx, deleteCalendarImport); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCalendarImport: %w", err)
+	}
 	if q.deleteExpiredOTPsStmt, err = db.PrepareContext(ctx, deleteExpiredOTPs); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteExpiredOTPs: %w", err)
 	}
+	if q.deleteExpiredStreamTicketsStmt, err = db.PrepareContext(ctx, deleteExpiredStreamTickets); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredStreamTickets: %w", err)
+	}
+	if q.deleteMessageTemplateStmt, err = db.PrepareContext(ctx, deleteMessageTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMessageTemplate: %w", err)
+	}
 	if q.deleteOTPByPhoneStmt, err = db.PrepareContext(ctx, deleteOTPByPhone); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteOTPByPhone: %w", err)
 	}
 	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
 	}
+	if q.deleteVisitorPhotoStmt, err = db.PrepareContext(ctx, deleteVisitorPhoto); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteVisitorPhoto: %w", err)
+	}
+	if q.deleteWebhookSubscriptionStmt, err = db.PrepareContext(ctx, deleteWebhookSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWebhookSubscription: %w", err)
+	}
+	if q.enqueueWebhookDeliveriesStmt, err = db.PrepareContext(ctx, enqueueWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query EnqueueWebhookDeliveries: %w", err)
+	}
+	if q.expireWaitlistOfferStmt, err = db.PrepareContext(ctx, expireWaitlistOffer); err != nil {
+		return nil, fmt.Errorf("error preparing query ExpireWaitlistOffer: %w", err)
+	}
+	if q.flagOverstaysStmt, err = db.PrepareContext(ctx, flagOverstays); err != nil {
+		return nil, fmt.Errorf("error preparing query FlagOverstays: %w", err)
+	}
+	if q.getAccessEventByClientIDStmt, err = db.PrepareContext(ctx, getAccessEventByClientID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccessEventByClientID: %w", err)
+	}
+	if q.getAppointmentBadgeStmt, err = db.PrepareContext(ctx, getAppointmentBadge); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAppointmentBadge: %w", err)
+	}
 	if q.getAppointmentByIDStmt, err = db.PrepareContext(ctx, getAppointmentByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAppointmentByID: %w", err)
 	}
 	if q.getAppointmentByQRCodeStmt, err = db.PrepareContext(ctx, getAppointmentByQRCode); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAppointmentByQRCode: %w", err)
 	}
+	if q.getAppointmentForUpdateStmt, err = db.PrepareContext(ctx, getAppointmentForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAppointmentForUpdate: %w", err)
+	}
 	if q.getAppointmentLogByAppointmentIDStmt, err = db.PrepareContext(ctx, getAppointmentLogByAppointmentID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAppointmentLogByAppointmentID: %w", err)
 	}
@@ -87,9 +186,57 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getAvailabilityByUserStmt, err = db.PrepareContext(ctx, getAvailabilityByUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAvailabilityByUser: %w", err)
 	}
+	if q.getCalendarFeedStmt, err = db.PrepareContext(ctx, getCalendarFeed); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCalendarFeed: %w", err)
+	}
+	if q.getCalendarFeedByTokenHashStmt, err = db.PrepareContext(ctx, getCalendarFeedByTokenHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCalendarFeedByTokenHash: %w", err)
+	}
+	if q.getCalendarImportStmt, err = db.PrepareContext(ctx, getCalendarImport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCalendarImport: %w", err)
+	}
+	if q.getCalendarSummaryStmt, err = db.PrepareContext(ctx, getCalendarSummary); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCalendarSummary: %w", err)
+	}
+	if q.getDailyVisitVolumesStmt, err = db.PrepareContext(ctx, getDailyVisitVolumes); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDailyVisitVolumes: %w", err)
+	}
+	if q.getDeclarationFormStmt, err = db.PrepareContext(ctx, getDeclarationForm); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeclarationForm: %w", err)
+	}
+	if q.getDeclarationFormVersionStmt, err = db.PrepareContext(ctx, getDeclarationFormVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeclarationFormVersion: %w", err)
+	}
+	if q.getEvacuationStmt, err = db.PrepareContext(ctx, getEvacuation); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEvacuation: %w", err)
+	}
+	if q.getKioskByKeyHashStmt, err = db.PrepareContext(ctx, getKioskByKeyHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetKioskByKeyHash: %w", err)
+	}
+	if q.getLatestAccessEventStmt, err = db.PrepareContext(ctx, getLatestAccessEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestAccessEvent: %w", err)
+	}
+	if q.getLatestVisitorPhotoStmt, err = db.PrepareContext(ctx, getLatestVisitorPhoto); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestVisitorPhoto: %w", err)
+	}
+	if q.getLeaderboardStmt, err = db.PrepareContext(ctx, getLeaderboard); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLeaderboard: %w", err)
+	}
+	if q.getNextWaitlistEntryStmt, err = db.PrepareContext(ctx, getNextWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNextWaitlistEntry: %w", err)
+	}
 	if q.getOTPByPhoneStmt, err = db.PrepareContext(ctx, getOTPByPhone); err != nil {
 		return nil, fmt.Errorf("error preparing query GetOTPByPhone: %w", err)
 	}
+	if q.getOverstayNoticeStmt, err = db.PrepareContext(ctx, getOverstayNotice); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOverstayNotice: %w", err)
+	}
+	if q.getOverstaySummaryStmt, err = db.PrepareContext(ctx, getOverstaySummary); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOverstaySummary: %w", err)
+	}
+	if q.getPrecedingAccessEventStmt, err = db.PrepareContext(ctx, getPrecedingAccessEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPrecedingAccessEvent: %w", err)
+	}
 	if q.getTopPopularUsersStmt, err = db.PrepareContext(ctx, getTopPopularUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTopPopularUsers: %w", err)
 	}
@@ -111,8 +258,35 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUsersByNameStmt, err = db.PrepareContext(ctx, getUsersByName); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUsersByName: %w", err)
 	}
-	if q.incrementAppointmentCountStmt, err = db.PrepareContext(ctx, incrementAppointmentCount); err != nil {
-		return nil, fmt.Errorf("error preparing query IncrementAppointmentCount: %w", err)
+	if q.getVisitDurationStatsStmt, err = db.PrepareContext(ctx, getVisitDurationStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetVisitDurationStats: %w", err)
+	}
+	if q.getVisitHeatmapStmt, err = db.PrepareContext(ctx, getVisitHeatmap); err != nil {
+		return nil, fmt.Errorf("error preparing query GetVisitHeatmap: %w", err)
+	}
+	if q.getVisitOutcomeCountsStmt, err = db.PrepareContext(ctx, getVisitOutcomeCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetVisitOutcomeCounts: %w", err)
+	}
+	if q.getWaitlistEntryStmt, err = db.PrepareContext(ctx, getWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistEntry: %w", err)
+	}
+	if q.getWaitlistOfferByTokenStmt, err = db.PrepareContext(ctx, getWaitlistOfferByToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistOfferByToken: %w", err)
+	}
+	if q.getWaitlistOfferForUpdateStmt, err = db.PrepareContext(ctx, getWaitlistOfferForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWaitlistOfferForUpdate: %w", err)
+	}
+	if q.getWebhookSubscriptionStmt, err = db.PrepareContext(ctx, getWebhookSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookSubscription: %w", err)
+	}
+	if q.listAccessEventsByAppointmentStmt, err = db.PrepareContext(ctx, listAccessEventsByAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccessEventsByAppointment: %w", err)
+	}
+	if q.listAppointmentCountDriftStmt, err = db.PrepareContext(ctx, listAppointmentCountDrift); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAppointmentCountDrift: %w", err)
+	}
+	if q.listAppointmentDeclarationsStmt, err = db.PrepareContext(ctx, listAppointmentDeclarations); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAppointmentDeclarations: %w", err)
 	}
 	if q.listAppointmentsByDateStmt, err = db.PrepareContext(ctx, listAppointmentsByDate); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAppointmentsByDate: %w", err)
@@ -123,11 +297,152 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listAppointmentsByVisitorStmt, err = db.PrepareContext(ctx, listAppointmentsByVisitor); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAppointmentsByVisitor: %w", err)
 	}
+	if q.listAppointmentsPageAscStmt, err = db.PrepareContext(ctx, listAppointmentsPageAsc); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAppointmentsPageAsc: %w", err)
+	}
+	if q.listAppointmentsPageDescStmt, err = db.PrepareContext(ctx, listAppointmentsPageDesc); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAppointmentsPageDesc: %w", err)
+	}
+	if q.listBusyBlocksStmt, err = db.PrepareContext(ctx, listBusyBlocks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBusyBlocks: %w", err)
+	}
+	if q.listCalendarImportsByUserStmt, err = db.PrepareContext(ctx, listCalendarImportsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCalendarImportsByUser: %w", err)
+	}
+	if q.listCheckInRepliesStmt, err = db.PrepareContext(ctx, listCheckInReplies); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCheckInReplies: %w", err)
+	}
+	if q.listDeclarationFormVersionsStmt, err = db.PrepareContext(ctx, listDeclarationFormVersions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDeclarationFormVersions: %w", err)
+	}
+	if q.listDeclarationFormsStmt, err = db.PrepareContext(ctx, listDeclarationForms); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDeclarationForms: %w", err)
+	}
+	if q.listDueCalendarImportsStmt, err = db.PrepareContext(ctx, listDueCalendarImports); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueCalendarImports: %w", err)
+	}
+	if q.listDueRemindersStmt, err = db.PrepareContext(ctx, listDueReminders); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueReminders: %w", err)
+	}
+	if q.listEvacuationRollStmt, err = db.PrepareContext(ctx, listEvacuationRoll); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEvacuationRoll: %w", err)
+	}
+	if q.listEvacuationsStmt, err = db.PrepareContext(ctx, listEvacuations); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEvacuations: %w", err)
+	}
+	if q.listExpiredVisitorPhotosStmt, err = db.PrepareContext(ctx, listExpiredVisitorPhotos); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredVisitorPhotos: %w", err)
+	}
+	if q.listExpiredWaitlistOffersStmt, err = db.PrepareContext(ctx, listExpiredWaitlistOffers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredWaitlistOffers: %w", err)
+	}
+	if q.listHostAppointmentTimesStmt, err = db.PrepareContext(ctx, listHostAppointmentTimes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListHostAppointmentTimes: %w", err)
+	}
+	if q.listInboxNotificationsStmt, err = db.PrepareContext(ctx, listInboxNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInboxNotifications: %w", err)
+	}
+	if q.listKioskManifestStmt, err = db.PrepareContext(ctx, listKioskManifest); err != nil {
+		return nil, fmt.Errorf("error preparing query ListKioskManifest: %w", err)
+	}
+	if q.listKiosksStmt, err = db.PrepareContext(ctx, listKiosks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListKiosks: %w", err)
+	}
+	if q.listLogbookPageStmt, err = db.PrepareContext(ctx, listLogbookPage); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLogbookPage: %w", err)
+	}
+	if q.listMessageTemplatesStmt, err = db.PrepareContext(ctx, listMessageTemplates); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMessageTemplates: %w", err)
+	}
+	if q.listNotificationsByAppointmentStmt, err = db.PrepareContext(ctx, listNotificationsByAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNotificationsByAppointment: %w", err)
+	}
+	if q.listOnsiteVisitorsStmt, err = db.PrepareContext(ctx, listOnsiteVisitors); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOnsiteVisitors: %w", err)
+	}
+	if q.listOverstaysStmt, err = db.PrepareContext(ctx, listOverstays); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOverstays: %w", err)
+	}
 	if q.listUsersStmt, err = db.PrepareContext(ctx, listUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUsers: %w", err)
 	}
-	if q.resetAppointmentCountStmt, err = db.PrepareContext(ctx, resetAppointmentCount); err != nil {
-		return nil, fmt.Errorf("error preparing query ResetAppointmentCount: %w", err)
+	if q.listVisitorPhotosByAppointmentStmt, err = db.PrepareContext(ctx, listVisitorPhotosByAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query ListVisitorPhotosByAppointment: %w", err)
+	}
+	if q.listVisitorPhotosByUserStmt, err = db.PrepareContext(ctx, listVisitorPhotosByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListVisitorPhotosByUser: %w", err)
+	}
+	if q.listWaitlistByHostStmt, err = db.PrepareContext(ctx, listWaitlistByHost); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWaitlistByHost: %w", err)
+	}
+	if q.listWaitlistByVisitorStmt, err = db.PrepareContext(ctx, listWaitlistByVisitor); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWaitlistByVisitor: %w", err)
+	}
+	if q.listWebhookDeliveriesStmt, err = db.PrepareContext(ctx, listWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookDeliveries: %w", err)
+	}
+	if q.listWebhookSubscriptionsStmt, err = db.PrepareContext(ctx, listWebhookSubscriptions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookSubscriptions: %w", err)
+	}
+	if q.markCalendarImportFailedStmt, err = db.PrepareContext(ctx, markCalendarImportFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkCalendarImportFailed: %w", err)
+	}
+	if q.markCalendarImportSyncedStmt, err = db.PrepareContext(ctx, markCalendarImportSynced); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkCalendarImportSynced: %w", err)
+	}
+	if q.markEvacuationRollEntryStmt, err = db.PrepareContext(ctx, markEvacuationRollEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkEvacuationRollEntry: %w", err)
+	}
+	if q.markNotificationFailedStmt, err = db.PrepareContext(ctx, markNotificationFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationFailed: %w", err)
+	}
+	if q.markNotificationSentStmt, err = db.PrepareContext(ctx, markNotificationSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationSent: %w", err)
+	}
+	if q.markReminderSentStmt, err = db.PrepareContext(ctx, markReminderSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkReminderSent: %w", err)
+	}
+	if q.notifyLiveEventStmt, err = db.PrepareContext(ctx, notifyLiveEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query NotifyLiveEvent: %w", err)
+	}
+	if q.reconcileAppointmentStatsStmt, err = db.PrepareContext(ctx, reconcileAppointmentStats); err != nil {
+		return nil, fmt.Errorf("error preparing query ReconcileAppointmentStats: %w", err)
+	}
+	if q.reconcileUserAppointmentCountsStmt, err = db.PrepareContext(ctx, reconcileUserAppointmentCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ReconcileUserAppointmentCounts: %w", err)
+	}
+	if q.redeemStreamTicketStmt, err = db.PrepareContext(ctx, redeemStreamTicket); err != nil {
+		return nil, fmt.Errorf("error preparing query RedeemStreamTicket: %w", err)
+	}
+	if q.redeliverWebhookStmt, err = db.PrepareContext(ctx, redeliverWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query RedeliverWebhook: %w", err)
+	}
+	if q.refreshAppointmentLogStmt, err = db.PrepareContext(ctx, refreshAppointmentLog); err != nil {
+		return nil, fmt.Errorf("error preparing query RefreshAppointmentLog: %w", err)
+	}
+	if q.requestCalendarImportSyncStmt, err = db.PrepareContext(ctx, requestCalendarImportSync); err != nil {
+		return nil, fmt.Errorf("error preparing query RequestCalendarImportSync: %w", err)
+	}
+	if q.requeueNotificationStmt, err = db.PrepareContext(ctx, requeueNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueNotification: %w", err)
+	}
+	if q.requeueWaitlistEntryStmt, err = db.PrepareContext(ctx, requeueWaitlistEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueWaitlistEntry: %w", err)
+	}
+	if q.rescheduleAppointmentStmt, err = db.PrepareContext(ctx, rescheduleAppointment); err != nil {
+		return nil, fmt.Errorf("error preparing query RescheduleAppointment: %w", err)
+	}
+	if q.resolveOverstaysStmt, err = db.PrepareContext(ctx, resolveOverstays); err != nil {
+		return nil, fmt.Errorf("error preparing query ResolveOverstays: %w", err)
+	}
+	if q.rotateCalendarFeedStmt, err = db.PrepareContext(ctx, rotateCalendarFeed); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateCalendarFeed: %w", err)
+	}
+	if q.snapshotEvacuationRollStmt, err = db.PrepareContext(ctx, snapshotEvacuationRoll); err != nil {
+		return nil, fmt.Errorf("error preparing query SnapshotEvacuationRoll: %w", err)
+	}
+	if q.touchKioskSyncStmt, err = db.PrepareContext(ctx, touchKioskSync); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchKioskSync: %w", err)
 	}
 	if q.updateAppointmentStatusStmt, err = db.PrepareContext(ctx, updateAppointmentStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAppointmentStatus: %w", err)
@@ -135,18 +450,45 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateAvailabilityStatusStmt, err = db.PrepareContext(ctx, updateAvailabilityStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAvailabilityStatus: %w", err)
 	}
-	if q.updateCheckInTimeStmt, err = db.PrepareContext(ctx, updateCheckInTime); err != nil {
-		return nil, fmt.Errorf("error preparing query UpdateCheckInTime: %w", err)
+	if q.updateDeclarationFormStmt, err = db.PrepareContext(ctx, updateDeclarationForm); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDeclarationForm: %w", err)
+	}
+	if q.updateKioskActiveStmt, err = db.PrepareContext(ctx, updateKioskActive); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateKioskActive: %w", err)
 	}
-	if q.updateCheckOutTimeStmt, err = db.PrepareContext(ctx, updateCheckOutTime); err != nil {
-		return nil, fmt.Errorf("error preparing query UpdateCheckOutTime: %w", err)
+	if q.updateUserActiveStmt, err = db.PrepareContext(ctx, updateUserActive); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserActive: %w", err)
+	}
+	if q.updateUserDepartmentStmt, err = db.PrepareContext(ctx, updateUserDepartment); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserDepartment: %w", err)
+	}
+	if q.updateUserEmailStmt, err = db.PrepareContext(ctx, updateUserEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserEmail: %w", err)
 	}
 	if q.updateUserNameStmt, err = db.PrepareContext(ctx, updateUserName); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserName: %w", err)
 	}
+	if q.updateUserNotificationChannelStmt, err = db.PrepareContext(ctx, updateUserNotificationChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserNotificationChannel: %w", err)
+	}
+	if q.updateUserPreferredLocaleStmt, err = db.PrepareContext(ctx, updateUserPreferredLocale); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserPreferredLocale: %w", err)
+	}
 	if q.updateUserRoleStmt, err = db.PrepareContext(ctx, updateUserRole); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserRole: %w", err)
 	}
+	if q.updateWaitlistEntryStatusStmt, err = db.PrepareContext(ctx, updateWaitlistEntryStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWaitlistEntryStatus: %w", err)
+	}
+	if q.updateWebhookSubscriptionStmt, err = db.PrepareContext(ctx, updateWebhookSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWebhookSubscription: %w", err)
+	}
+	if q.upsertDeclarationResponseStmt, err = db.PrepareContext(ctx, upsertDeclarationResponse); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertDeclarationResponse: %w", err)
+	}
+	if q.upsertMessageTemplateStmt, err = db.PrepareContext(ctx, upsertMessageTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertMessageTemplate: %w", err)
+	}
 	return &q, nil
 }
 
@@ -157,19 +499,49 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing cancelAppointmentStmt: %w", cerr)
 		}
 	}
-	if q.createAppointmentStmt != nil {
-		if cerr := q.createAppointmentStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createAppointmentStmt: %w", cerr)
+	if q.cancelWaitlistEntryStmt != nil {
+		if cerr := q.cancelWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cancelWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.claimDueNotificationsStmt != nil {
+		if cerr := q.claimDueNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimDueNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.claimWaitlistOfferStmt != nil {
+		if cerr := q.claimWaitlistOfferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimWaitlistOfferStmt: %w", cerr)
+		}
+	}
+	if q.closeEvacuationStmt != nil {
+		if cerr := q.closeEvacuationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing closeEvacuationStmt: %w", cerr)
+		}
+	}
+	if q.countOverlappingAppointmentsStmt != nil {
+		if cerr := q.countOverlappingAppointmentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countOverlappingAppointmentsStmt: %w", cerr)
+		}
+	}
+	if q.countOverlappingBusyBlocksStmt != nil {
+		if cerr := q.countOverlappingBusyBlocksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countOverlappingBusyBlocksStmt: %w", cerr)
+		}
+	}
+	if q.countPendingDeclarationsStmt != nil {
+		if cerr := q.countPendingDeclarationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countPendingDeclarationsStmt: %w", cerr)
 		}
 	}
-	if q.createAppointmentLogStmt != nil {
-		if cerr := q.createAppointmentLogStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createAppointmentLogStmt: %w", cerr)
+	if q.createAccessEventStmt != nil {
+		if cerr := q.createAccessEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAccessEventStmt: %w", cerr)
 		}
 	}
-	if q.createAppointmentStatsStmt != nil {
-		if cerr := q.createAppointmentStatsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createAppointmentStatsStmt: %w", cerr)
+	if q.createAppointmentStmt != nil {
+		if cerr := q.createAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAppointmentStmt: %w", cerr)
 		}
 	}
 	if q.createAvailabilitySlotStmt != nil {
@@ -177,19 +549,94 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createAvailabilitySlotStmt: %w", cerr)
 		}
 	}
+	if q.createBusyBlockStmt != nil {
+		if cerr := q.createBusyBlockStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBusyBlockStmt: %w", cerr)
+		}
+	}
+	if q.createCalendarFeedStmt != nil {
+		if cerr := q.createCalendarFeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCalendarFeedStmt: %w", cerr)
+		}
+	}
+	if q.createCalendarImportStmt != nil {
+		if cerr := q.createCalendarImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCalendarImportStmt: %w", cerr)
+		}
+	}
+	if q.createCheckInReplyStmt != nil {
+		if cerr := q.createCheckInReplyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCheckInReplyStmt: %w", cerr)
+		}
+	}
+	if q.createDeclarationFormStmt != nil {
+		if cerr := q.createDeclarationFormStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeclarationFormStmt: %w", cerr)
+		}
+	}
+	if q.createDeclarationFormVersionStmt != nil {
+		if cerr := q.createDeclarationFormVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeclarationFormVersionStmt: %w", cerr)
+		}
+	}
+	if q.createEvacuationStmt != nil {
+		if cerr := q.createEvacuationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEvacuationStmt: %w", cerr)
+		}
+	}
+	if q.createKioskStmt != nil {
+		if cerr := q.createKioskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createKioskStmt: %w", cerr)
+		}
+	}
+	if q.createNotificationStmt != nil {
+		if cerr := q.createNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createNotificationStmt: %w", cerr)
+		}
+	}
 	if q.createOTPStmt != nil {
 		if cerr := q.createOTPStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createOTPStmt: %w", cerr)
 		}
 	}
+	if q.createStreamTicketStmt != nil {
+		if cerr := q.createStreamTicketStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createStreamTicketStmt: %w", cerr)
+		}
+	}
 	if q.createUserStmt != nil {
 		if cerr := q.createUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
-	if q.decrementAppointmentCountStmt != nil {
-		if cerr := q.decrementAppointmentCountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing decrementAppointmentCountStmt: %w", cerr)
+	if q.createVisitorPhotoStmt != nil {
+		if cerr := q.createVisitorPhotoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createVisitorPhotoStmt: %w", cerr)
+		}
+	}
+	if q.createWaitlistEntryStmt != nil {
+		if cerr := q.createWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.createWaitlistOfferStmt != nil {
+		if cerr := q.createWaitlistOfferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWaitlistOfferStmt: %w", cerr)
+		}
+	}
+	if q.createWebhookSubscriptionStmt != nil {
+		if cerr := q.createWebhookSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.deadLetterExpiredLeasesStmt != nil {
+		if cerr := q.deadLetterExpiredLeasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deadLetterExpiredLeasesStmt: %w", cerr)
+		}
+	}
+	if q.deleteAccessEventsByAppointmentStmt != nil {
+		if cerr := q.deleteAccessEventsByAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAccessEventsByAppointmentStmt: %w", cerr)
 		}
 	}
 	if q.deleteAppointmentStmt != nil {
@@ -217,11 +664,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteAvailabilitySlotStmt: %w", cerr)
 		}
 	}
+	if q.deleteBusyBlocksByImportStmt != nil {
+		if cerr := q.deleteBusyBlocksByImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBusyBlocksByImportStmt: %w", cerr)
+		}
+	}
+	if q.deleteCalendarImportStmt != nil {
+		if cerr := q.deleteCalendarImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCalendarImportStmt: %w", cerr)
+		}
+	}
 	if q.deleteExpiredOTPsStmt != nil {
 		if cerr := q.deleteExpiredOTPsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteExpiredOTPsStmt: %w", cerr)
 		}
 	}
+	if q.deleteExpiredStreamTicketsStmt != nil {
+		if cerr := q.deleteExpiredStreamTicketsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredStreamTicketsStmt: %w", cerr)
+		}
+	}
+	if q.deleteMessageTemplateStmt != nil {
+		if cerr := q.deleteMessageTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMessageTemplateStmt: %w", cerr)
+		}
+	}
 	if q.deleteOTPByPhoneStmt != nil {
 		if cerr := q.deleteOTPByPhoneStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteOTPByPhoneStmt: %w", cerr)
@@ -232,6 +699,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
 		}
 	}
+	if q.deleteVisitorPhotoStmt != nil {
+		if cerr := q.deleteVisitorPhotoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteVisitorPhotoStmt: %w", cerr)
+		}
+	}
+	if q.deleteWebhookSubscriptionStmt != nil {
+		if cerr := q.deleteWebhookSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWebhookSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.enqueueWebhookDeliveriesStmt != nil {
+		if cerr := q.enqueueWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enqueueWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.expireWaitlistOfferStmt != nil {
+		if cerr := q.expireWaitlistOfferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing expireWaitlistOfferStmt: %w", cerr)
+		}
+	}
+	if q.flagOverstaysStmt != nil {
+		if cerr := q.flagOverstaysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing flagOverstaysStmt: %w", cerr)
+		}
+	}
+	if q.getAccessEventByClientIDStmt != nil {
+		if cerr := q.getAccessEventByClientIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccessEventByClientIDStmt: %w", cerr)
+		}
+	}
+	if q.getAppointmentBadgeStmt != nil {
+		if cerr := q.getAppointmentBadgeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAppointmentBadgeStmt: %w", cerr)
+		}
+	}
 	if q.getAppointmentByIDStmt != nil {
 		if cerr := q.getAppointmentByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAppointmentByIDStmt: %w", cerr)
@@ -242,6 +744,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAppointmentByQRCodeStmt: %w", cerr)
 		}
 	}
+	if q.getAppointmentForUpdateStmt != nil {
+		if cerr := q.getAppointmentForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAppointmentForUpdateStmt: %w", cerr)
+		}
+	}
 	if q.getAppointmentLogByAppointmentIDStmt != nil {
 		if cerr := q.getAppointmentLogByAppointmentIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAppointmentLogByAppointmentIDStmt: %w", cerr)
@@ -257,11 +764,91 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAvailabilityByUserStmt: %w", cerr)
 		}
 	}
+	if q.getCalendarFeedStmt != nil {
+		if cerr := q.getCalendarFeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCalendarFeedStmt: %w", cerr)
+		}
+	}
+	if q.getCalendarFeedByTokenHashStmt != nil {
+		if cerr := q.getCalendarFeedByTokenHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCalendarFeedByTokenHashStmt: %w", cerr)
+		}
+	}
+	if q.getCalendarImportStmt != nil {
+		if cerr := q.getCalendarImportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCalendarImportStmt: %w", cerr)
+		}
+	}
+	if q.getCalendarSummaryStmt != nil {
+		if cerr := q.getCalendarSummaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCalendarSummaryStmt: %w", cerr)
+		}
+	}
+	if q.getDailyVisitVolumesStmt != nil {
+		if cerr := q.getDailyVisitVolumesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDailyVisitVolumesStmt: %w", cerr)
+		}
+	}
+	if q.getDeclarationFormStmt != nil {
+		if cerr := q.getDeclarationFormStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeclarationFormStmt: %w", cerr)
+		}
+	}
+	if q.getDeclarationFormVersionStmt != nil {
+		if cerr := q.getDeclarationFormVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeclarationFormVersionStmt: %w", cerr)
+		}
+	}
+	if q.getEvacuationStmt != nil {
+		if cerr := q.getEvacuationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEvacuationStmt: %w", cerr)
+		}
+	}
+	if q.getKioskByKeyHashStmt != nil {
+		if cerr := q.getKioskByKeyHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getKioskByKeyHashStmt: %w", cerr)
+		}
+	}
+	if q.getLatestAccessEventStmt != nil {
+		if cerr := q.getLatestAccessEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestAccessEventStmt: %w", cerr)
+		}
+	}
+	if q.getLatestVisitorPhotoStmt != nil {
+		if cerr := q.getLatestVisitorPhotoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestVisitorPhotoStmt: %w", cerr)
+		}
+	}
+	if q.getLeaderboardStmt != nil {
+		if cerr := q.getLeaderboardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLeaderboardStmt: %w", cerr)
+		}
+	}
+	if q.getNextWaitlistEntryStmt != nil {
+		if cerr := q.getNextWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNextWaitlistEntryStmt: %w", cerr)
+		}
+	}
 	if q.getOTPByPhoneStmt != nil {
 		if cerr := q.getOTPByPhoneStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getOTPByPhoneStmt: %w", cerr)
 		}
 	}
+	if q.getOverstayNoticeStmt != nil {
+		if cerr := q.getOverstayNoticeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOverstayNoticeStmt: %w", cerr)
+		}
+	}
+	if q.getOverstaySummaryStmt != nil {
+		if cerr := q.getOverstaySummaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOverstaySummaryStmt: %w", cerr)
+		}
+	}
+	if q.getPrecedingAccessEventStmt != nil {
+		if cerr := q.getPrecedingAccessEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPrecedingAccessEventStmt: %w", cerr)
+		}
+	}
 	if q.getTopPopularUsersStmt != nil {
 		if cerr := q.getTopPopularUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTopPopularUsersStmt: %w", cerr)
@@ -297,9 +884,54 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUsersByNameStmt: %w", cerr)
 		}
 	}
-	if q.incrementAppointmentCountStmt != nil {
-		if cerr := q.incrementAppointmentCountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing incrementAppointmentCountStmt: %w", cerr)
+	if q.getVisitDurationStatsStmt != nil {
+		if cerr := q.getVisitDurationStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getVisitDurationStatsStmt: %w", cerr)
+		}
+	}
+	if q.getVisitHeatmapStmt != nil {
+		if cerr := q.getVisitHeatmapStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getVisitHeatmapStmt: %w", cerr)
+		}
+	}
+	if q.getVisitOutcomeCountsStmt != nil {
+		if cerr := q.getVisitOutcomeCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getVisitOutcomeCountsStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistEntryStmt != nil {
+		if cerr := q.getWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistOfferByTokenStmt != nil {
+		if cerr := q.getWaitlistOfferByTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistOfferByTokenStmt: %w", cerr)
+		}
+	}
+	if q.getWaitlistOfferForUpdateStmt != nil {
+		if cerr := q.getWaitlistOfferForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWaitlistOfferForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookSubscriptionStmt != nil {
+		if cerr := q.getWebhookSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.listAccessEventsByAppointmentStmt != nil {
+		if cerr := q.listAccessEventsByAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccessEventsByAppointmentStmt: %w", cerr)
+		}
+	}
+	if q.listAppointmentCountDriftStmt != nil {
+		if cerr := q.listAppointmentCountDriftStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAppointmentCountDriftStmt: %w", cerr)
+		}
+	}
+	if q.listAppointmentDeclarationsStmt != nil {
+		if cerr := q.listAppointmentDeclarationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAppointmentDeclarationsStmt: %w", cerr)
 		}
 	}
 	if q.listAppointmentsByDateStmt != nil {
@@ -317,14 +949,249 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listAppointmentsByVisitorStmt: %w", cerr)
 		}
 	}
+	if q.listAppointmentsPageAscStmt != nil {
+		if cerr := q.listAppointmentsPageAscStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAppointmentsPageAscStmt: %w", cerr)
+		}
+	}
+	if q.listAppointmentsPageDescStmt != nil {
+		if cerr := q.listAppointmentsPageDescStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAppointmentsPageDescStmt: %w", cerr)
+		}
+	}
+	if q.listBusyBlocksStmt != nil {
+		if cerr := q.listBusyBlocksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBusyBlocksStmt: %w", cerr)
+		}
+	}
+	if q.listCalendarImportsByUserStmt != nil {
+		if cerr := q.listCalendarImportsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCalendarImportsByUserStmt: %w", cerr)
+		}
+	}
+	if q.listCheckInRepliesStmt != nil {
+		if cerr := q.listCheckInRepliesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCheckInRepliesStmt: %w", cerr)
+		}
+	}
+	if q.listDeclarationFormVersionsStmt != nil {
+		if cerr := q.listDeclarationFormVersionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDeclarationFormVersionsStmt: %w", cerr)
+		}
+	}
+	if q.listDeclarationFormsStmt != nil {
+		if cerr := q.listDeclarationFormsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDeclarationFormsStmt: %w", cerr)
+		}
+	}
+	if q.listDueCalendarImportsStmt != nil {
+		if cerr := q.listDueCalendarImportsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueCalendarImportsStmt: %w", cerr)
+		}
+	}
+	if q.listDueRemindersStmt != nil {
+		if cerr := q.listDueRemindersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueRemindersStmt: %w", cerr)
+		}
+	}
+	if q.listEvacuationRollStmt != nil {
+		if cerr := q.listEvacuationRollStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEvacuationRollStmt: %w", cerr)
+		}
+	}
+	if q.listEvacuationsStmt != nil {
+		if cerr := q.listEvacuationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEvacuationsStmt: %w", cerr)
+		}
+	}
+	if q.listExpiredVisitorPhotosStmt != nil {
+		if cerr := q.listExpiredVisitorPhotosStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredVisitorPhotosStmt: %w", cerr)
+		}
+	}
+	if q.listExpiredWaitlistOffersStmt != nil {
+		if cerr := q.listExpiredWaitlistOffersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredWaitlistOffersStmt: %w", cerr)
+		}
+	}
+	if q.listHostAppointmentTimesStmt != nil {
+		if cerr := q.listHostAppointmentTimesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listHostAppointmentTimesStmt: %w", cerr)
+		}
+	}
+	if q.listInboxNotificationsStmt != nil {
+		if cerr := q.listInboxNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInboxNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.listKioskManifestStmt != nil {
+		if cerr := q.listKioskManifestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listKioskManifestStmt: %w", cerr)
+		}
+	}
+	if q.listKiosksStmt != nil {
+		if cerr := q.listKiosksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listKiosksStmt: %w", cerr)
+		}
+	}
+	if q.listLogbookPageStmt != nil {
+		if cerr := q.listLogbookPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLogbookPageStmt: %w", cerr)
+		}
+	}
+	if q.listMessageTemplatesStmt != nil {
+		if cerr := q.listMessageTemplatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMessageTemplatesStmt: %w", cerr)
+		}
+	}
+	if q.listNotificationsByAppointmentStmt != nil {
+		if cerr := q.listNotificationsByAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNotificationsByAppointmentStmt: %w", cerr)
+		}
+	}
+	if q.listOnsiteVisitorsStmt != nil {
+		if cerr := q.listOnsiteVisitorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOnsiteVisitorsStmt: %w", cerr)
+		}
+	}
+	if q.listOverstaysStmt != nil {
+		if cerr := q.listOverstaysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOverstaysStmt: %w", cerr)
+		}
+	}
 	if q.listUsersStmt != nil {
 		if cerr := q.listUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUsersStmt: %w", cerr)
 		}
 	}
-	if q.resetAppointmentCountStmt != nil {
-		if cerr := q.resetAppointmentCountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing resetAppointmentCountStmt: %w", cerr)
+	if q.listVisitorPhotosByAppointmentStmt != nil {
+		if cerr := q.listVisitorPhotosByAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listVisitorPhotosByAppointmentStmt: %w", cerr)
+		}
+	}
+	if q.listVisitorPhotosByUserStmt != nil {
+		if cerr := q.listVisitorPhotosByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listVisitorPhotosByUserStmt: %w", cerr)
+		}
+	}
+	if q.listWaitlistByHostStmt != nil {
+		if cerr := q.listWaitlistByHostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWaitlistByHostStmt: %w", cerr)
+		}
+	}
+	if q.listWaitlistByVisitorStmt != nil {
+		if cerr := q.listWaitlistByVisitorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWaitlistByVisitorStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookDeliveriesStmt != nil {
+		if cerr := q.listWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookSubscriptionsStmt != nil {
+		if cerr := q.listWebhookSubscriptionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookSubscriptionsStmt: %w", cerr)
+		}
+	}
+	if q.markCalendarImportFailedStmt != nil {
+		if cerr := q.markCalendarImportFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markCalendarImportFailedStmt: %w", cerr)
+		}
+	}
+	if q.markCalendarImportSyncedStmt != nil {
+		if cerr := q.markCalendarImportSyncedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markCalendarImportSyncedStmt: %w", cerr)
+		}
+	}
+	if q.markEvacuationRollEntryStmt != nil {
+		if cerr := q.markEvacuationRollEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markEvacuationRollEntryStmt: %w", cerr)
+		}
+	}
+	if q.markNotificationFailedStmt != nil {
+		if cerr := q.markNotificationFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationFailedStmt: %w", cerr)
+		}
+	}
+	if q.markNotificationSentStmt != nil {
+		if cerr := q.markNotificationSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationSentStmt: %w", cerr)
+		}
+	}
+	if q.markReminderSentStmt != nil {
+		if cerr := q.markReminderSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markReminderSentStmt: %w", cerr)
+		}
+	}
+	if q.notifyLiveEventStmt != nil {
+		if cerr := q.notifyLiveEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing notifyLiveEventStmt: %w", cerr)
+		}
+	}
+	if q.reconcileAppointmentStatsStmt != nil {
+		if cerr := q.reconcileAppointmentStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing reconcileAppointmentStatsStmt: %w", cerr)
+		}
+	}
+	if q.reconcileUserAppointmentCountsStmt != nil {
+		if cerr := q.reconcileUserAppointmentCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing reconcileUserAppointmentCountsStmt: %w", cerr)
+		}
+	}
+	if q.redeemStreamTicketStmt != nil {
+		if cerr := q.redeemStreamTicketStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing redeemStreamTicketStmt: %w", cerr)
+		}
+	}
+	if q.redeliverWebhookStmt != nil {
+		if cerr := q.redeliverWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing redeliverWebhookStmt: %w", cerr)
+		}
+	}
+	if q.refreshAppointmentLogStmt != nil {
+		if cerr := q.refreshAppointmentLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing refreshAppointmentLogStmt: %w", cerr)
+		}
+	}
+	if q.requestCalendarImportSyncStmt != nil {
+		if cerr := q.requestCalendarImportSyncStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requestCalendarImportSyncStmt: %w", cerr)
+		}
+	}
+	if q.requeueNotificationStmt != nil {
+		if cerr := q.requeueNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueNotificationStmt: %w", cerr)
+		}
+	}
+	if q.requeueWaitlistEntryStmt != nil {
+		if cerr := q.requeueWaitlistEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueWaitlistEntryStmt: %w", cerr)
+		}
+	}
+	if q.rescheduleAppointmentStmt != nil {
+		if cerr := q.rescheduleAppointmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rescheduleAppointmentStmt: %w", cerr)
+		}
+	}
+	if q.resolveOverstaysStmt != nil {
+		if cerr := q.resolveOverstaysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resolveOverstaysStmt: %w", cerr)
+		}
+	}
+	if q.rotateCalendarFeedStmt != nil {
+		if cerr := q.rotateCalendarFeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateCalendarFeedStmt: %w", cerr)
+		}
+	}
+	if q.snapshotEvacuationRollStmt != nil {
+		if cerr := q.snapshotEvacuationRollStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing snapshotEvacuationRollStmt: %w", cerr)
+		}
+	}
+	if q.touchKioskSyncStmt != nil {
+		if cerr := q.touchKioskSyncStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchKioskSyncStmt: %w", cerr)
 		}
 	}
 	if q.updateAppointmentStatusStmt != nil {
@@ -337,14 +1204,29 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateAvailabilityStatusStmt: %w", cerr)
 		}
 	}
-	if q.updateCheckInTimeStmt != nil {
-		if cerr := q.updateCheckInTimeStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateCheckInTimeStmt: %w", cerr)
+	if q.updateDeclarationFormStmt != nil {
+		if cerr := q.updateDeclarationFormStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDeclarationFormStmt: %w", cerr)
+		}
+	}
+	if q.updateKioskActiveStmt != nil {
+		if cerr := q.updateKioskActiveStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateKioskActiveStmt: %w", cerr)
 		}
 	}
-	if q.updateCheckOutTimeStmt != nil {
-		if cerr := q.updateCheckOutTimeStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateCheckOutTimeStmt: %w", cerr)
+	if q.updateUserActiveStmt != nil {
+		if cerr := q.updateUserActiveStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserActiveStmt: %w", cerr)
+		}
+	}
+	if q.updateUserDepartmentStmt != nil {
+		if cerr := q.updateUserDepartmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserDepartmentStmt: %w", cerr)
+		}
+	}
+	if q.updateUserEmailStmt != nil {
+		if cerr := q.updateUserEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserEmailStmt: %w", cerr)
 		}
 	}
 	if q.updateUserNameStmt != nil {
@@ -352,11 +1234,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserNameStmt: %w", cerr)
 		}
 	}
+	if q.updateUserNotificationChannelStmt != nil {
+		if cerr := q.updateUserNotificationChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserNotificationChannelStmt: %w", cerr)
+		}
+	}
+	if q.updateUserPreferredLocaleStmt != nil {
+		if cerr := q.updateUserPreferredLocaleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserPreferredLocaleStmt: %w", cerr)
+		}
+	}
 	if q.updateUserRoleStmt != nil {
 		if cerr := q.updateUserRoleStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateUserRoleStmt: %w", cerr)
 		}
 	}
+	if q.updateWaitlistEntryStatusStmt != nil {
+		if cerr := q.updateWaitlistEntryStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWaitlistEntryStatusStmt: %w", cerr)
+		}
+	}
+	if q.updateWebhookSubscriptionStmt != nil {
+		if cerr := q.updateWebhookSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWebhookSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.upsertDeclarationResponseStmt != nil {
+		if cerr := q.upsertDeclarationResponseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertDeclarationResponseStmt: %w", cerr)
+		}
+	}
+	if q.upsertMessageTemplateStmt != nil {
+		if cerr := q.upsertMessageTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertMessageTemplateStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -397,27 +1309,76 @@ type Queries struct {
 	db                                   DBTX
 	tx                                   *sql.Tx
 	cancelAppointmentStmt                *sql.Stmt
+	cancelWaitlistEntryStmt              *sql.Stmt
+	claimDueNotificationsStmt            *sql.Stmt
+	claimWaitlistOfferStmt               *sql.Stmt
+	closeEvacuationStmt                  *sql.Stmt
+	countOverlappingAppointmentsStmt     *sql.Stmt
+	countOverlappingBusyBlocksStmt       *sql.Stmt
+	countPendingDeclarationsStmt         *sql.Stmt
+	createAccessEventStmt                *sql.Stmt
 	createAppointmentStmt                *sql.Stmt
-	createAppointmentLogStmt             *sql.Stmt
-	createAppointmentStatsStmt           *sql.Stmt
 	createAvailabilitySlotStmt           *sql.Stmt
+	createBusyBlockStmt                  *sql.Stmt
+	createCalendarFeedStmt               *sql.Stmt
+	createCalendarImportStmt             *sql.Stmt
+	createCheckInReplyStmt               *sql.Stmt
+	createDeclarationFormStmt            *sql.Stmt
+	createDeclarationFormVersionStmt     *sql.Stmt
+	createEvacuationStmt                 *sql.Stmt
+	createKioskStmt                      *sql.Stmt
+	createNotificationStmt               *sql.Stmt
 	createOTPStmt                        *sql.Stmt
+	createStreamTicketStmt               *sql.Stmt
 	createUserStmt                       *sql.Stmt
-	decrementAppointmentCountStmt        *sql.Stmt
+	createVisitorPhotoStmt               *sql.Stmt
+	createWaitlistEntryStmt              *sql.Stmt
+	createWaitlistOfferStmt              *sql.Stmt
+	createWebhookSubscriptionStmt        *sql.Stmt
+	deadLetterExpiredLeasesStmt          *sql.Stmt
+	deleteAccessEventsByAppointmentStmt  *sql.Stmt
 	deleteAppointmentStmt                *sql.Stmt
 	deleteAppointmentLogStmt             *sql.Stmt
 	deleteAppointmentStatsStmt           *sql.Stmt
 	deleteAvailabilityByUserStmt         *sql.Stmt
 	deleteAvailabilitySlotStmt           *sql.Stmt
+	deleteBusyBlocksByImportStmt         *sql.Stmt
+	deleteCalendarImportStmt             *sql.Stmt
 	deleteExpiredOTPsStmt                *sql.Stmt
+	deleteExpiredStreamTicketsStmt       *sql.Stmt
+	deleteMessageTemplateStmt            *sql.Stmt
 	deleteOTPByPhoneStmt                 *sql.Stmt
 	deleteUserStmt                       *sql.Stmt
+	deleteVisitorPhotoStmt               *sql.Stmt
+	deleteWebhookSubscriptionStmt        *sql.Stmt
+	enqueueWebhookDeliveriesStmt         *sql.Stmt
+	expireWaitlistOfferStmt              *sql.Stmt
+	flagOverstaysStmt                    *sql.Stmt
+	getAccessEventByClientIDStmt         *sql.Stmt
+	getAppointmentBadgeStmt              *sql.Stmt
 	getAppointmentByIDStmt               *sql.Stmt
 	getAppointmentByQRCodeStmt           *sql.Stmt
+	getAppointmentForUpdateStmt          *sql.Stmt
 	getAppointmentLogByAppointmentIDStmt *sql.Stmt
 	getAppointmentStatsByUserIDStmt      *sql.Stmt
 	getAvailabilityByUserStmt            *sql.Stmt
+	getCalendarFeedStmt                  *sql.Stmt
+	getCalendarFeedByTokenHashStmt       *sql.Stmt
+	getCalendarImportStmt                *sql.Stmt
+	getCalendarSummaryStmt               *sql.Stmt
+	getDailyVisitVolumesStmt             *sql.Stmt
+	getDeclarationFormStmt               *sql.Stmt
+	getDeclarationFormVersionStmt        *sql.Stmt
+	getEvacuationStmt                    *sql.Stmt
+	getKioskByKeyHashStmt                *sql.Stmt
+	getLatestAccessEventStmt             *sql.Stmt
+	getLatestVisitorPhotoStmt            *sql.Stmt
+	getLeaderboardStmt                   *sql.Stmt
+	getNextWaitlistEntryStmt             *sql.Stmt
 	getOTPByPhoneStmt                    *sql.Stmt
+	getOverstayNoticeStmt                *sql.Stmt
+	getOverstaySummaryStmt               *sql.Stmt
+	getPrecedingAccessEventStmt          *sql.Stmt
 	getTopPopularUsersStmt               *sql.Stmt
 	getTotalAppointmentsHostedStmt       *sql.Stmt
 	getTotalAppointmentsVisitedStmt      *sql.Stmt
@@ -425,18 +1386,83 @@ type Queries struct {
 	getUserByIDStmt                      *sql.Stmt
 	getUserByPhoneStmt                   *sql.Stmt
 	getUsersByNameStmt                   *sql.Stmt
-	incrementAppointmentCountStmt        *sql.Stmt
+	getVisitDurationStatsStmt            *sql.Stmt
+	getVisitHeatmapStmt                  *sql.Stmt
+	getVisitOutcomeCountsStmt            *sql.Stmt
+	getWaitlistEntryStmt                 *sql.Stmt
+	getWaitlistOfferByTokenStmt          *sql.Stmt
+	getWaitlistOfferForUpdateStmt        *sql.Stmt
+	getWebhookSubscriptionStmt           *sql.Stmt
+	listAccessEventsByAppointmentStmt    *sql.Stmt
+	listAppointmentCountDriftStmt        *sql.Stmt
+	listAppointmentDeclarationsStmt      *sql.Stmt
 	listAppointmentsByDateStmt           *sql.Stmt
 	listAppointmentsByHostStmt           *sql.Stmt
 	listAppointmentsByVisitorStmt        *sql.Stmt
+	listAppointmentsPageAscStmt          *sql.Stmt
+	listAppointmentsPageDescStmt         *sql.Stmt
+	listBusyBlocksStmt                   *sql.Stmt
+	listCalendarImportsByUserStmt        *sql.Stmt
+	listCheckInRepliesStmt               *sql.Stmt
+	listDeclarationFormVersionsStmt      *sql.Stmt
+	listDeclarationFormsStmt             *sql.Stmt
+	listDueCalendarImportsStmt           *sql.Stmt
+	listDueRemindersStmt                 *sql.Stmt
+	listEvacuationRollStmt               *sql.Stmt
+	listEvacuationsStmt                  *sql.Stmt
+	listExpiredVisitorPhotosStmt         *sql.Stmt
+	listExpiredWaitlistOffersStmt        *sql.Stmt
+	listHostAppointmentTimesStmt         *sql.Stmt
+	listInboxNotificationsStmt           *sql.Stmt
+	listKioskManifestStmt                *sql.Stmt
+	listKiosksStmt                       *sql.Stmt
+	listLogbookPageStmt                  *sql.Stmt
+	listMessageTemplatesStmt             *sql.Stmt
+	listNotificationsByAppointmentStmt   *sql.Stmt
+	listOnsiteVisitorsStmt               *sql.Stmt
+	listOverstaysStmt                    *sql.Stmt
 	listUsersStmt                        *sql.Stmt
-	resetAppointmentCountStmt            *sql.Stmt
+	listVisitorPhotosByAppointmentStmt   *sql.Stmt
+	listVisitorPhotosByUserStmt          *sql.Stmt
+	listWaitlistByHostStmt               *sql.Stmt
+	listWaitlistByVisitorStmt            *sql.Stmt
+	listWebhookDeliveriesStmt            *sql.Stmt
+	listWebhookSubscriptionsStmt         *sql.Stmt
+	markCalendarImportFailedStmt         *sql.Stmt
+	markCalendarImportSyncedStmt         *sql.Stmt
+	markEvacuationRollEntryStmt          *sql.Stmt
+	markNotificationFailedStmt           *sql.Stmt
+	markNotificationSentStmt             *sql.Stmt
+	markReminderSentStmt                 *sql.Stmt
+	notifyLiveEventStmt                  *sql.Stmt
+	reconcileAppointmentStatsStmt        *sql.Stmt
+	reconcileUserAppointmentCountsStmt   *sql.Stmt
+	redeemStreamTicketStmt               *sql.Stmt
+	redeliverWebhookStmt                 *sql.Stmt
+	refreshAppointmentLogStmt            *sql.Stmt
+	requestCalendarImportSyncStmt        *sql.Stmt
+	requeueNotificationStmt              *sql.Stmt
+	requeueWaitlistEntryStmt             *sql.Stmt
+	rescheduleAppointmentStmt            *sql.Stmt
+	resolveOverstaysStmt                 *sql.Stmt
+	rotateCalendarFeedStmt               *sql.Stmt
+	snapshotEvacuationRollStmt           *sql.Stmt
+	touchKioskSyncStmt                   *sql.Stmt
 	updateAppointmentStatusStmt          *sql.Stmt
 	updateAvailabilityStatusStmt         *sql.Stmt
-	updateCheckInTimeStmt                *sql.Stmt
-	updateCheckOutTimeStmt               *sql.Stmt
+	updateDeclarationFormStmt            *sql.Stmt
+	updateKioskActiveStmt                *sql.Stmt
+	updateUserActiveStmt                 *sql.Stmt
+	updateUserDepartmentStmt             *sql.Stmt
+	updateUserEmailStmt                  *sql.Stmt
 	updateUserNameStmt                   *sql.Stmt
+	updateUserNotificationChannelStmt    *sql.Stmt
+	updateUserPreferredLocaleStmt        *sql.Stmt
 	updateUserRoleStmt                   *sql.Stmt
+	updateWaitlistEntryStatusStmt        *sql.Stmt
+	updateWebhookSubscriptionStmt        *sql.Stmt
+	upsertDeclarationResponseStmt        *sql.Stmt
+	upsertMessageTemplateStmt            *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
@@ -444,27 +1470,76 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		db:                                   tx,
 		tx:                                   tx,
 		cancelAppointmentStmt:                q.cancelAppointmentStmt,
+		cancelWaitlistEntryStmt:              q.cancelWaitlistEntryStmt,
+		claimDueNotificationsStmt:            q.claimDueNotificationsStmt,
+		claimWaitlistOfferStmt:               q.claimWaitlistOfferStmt,
+		closeEvacuationStmt:                  q.closeEvacuationStmt,
+		countOverlappingAppointmentsStmt:     q.countOverlappingAppointmentsStmt,
+		countOverlappingBusyBlocksStmt:       q.countOverlappingBusyBlocksStmt,
+		countPendingDeclarationsStmt:         q.countPendingDeclarationsStmt,
+		createAccessEventStmt:                q.createAccessEventStmt,
 		createAppointmentStmt:                q.createAppointmentStmt,
-		createAppointmentLogStmt:             q.createAppointmentLogStmt,
-		createAppointmentStatsStmt:           q.createAppointmentStatsStmt,
 		createAvailabilitySlotStmt:           q.createAvailabilitySlotStmt,
+		createBusyBlockStmt:                  q.createBusyBlockStmt,
+		createCalendarFeedStmt:               q.createCalendarFeedStmt,
+		createCalendarImportStmt:             q.createCalendarImportStmt,
+		createCheckInReplyStmt:               q.createCheckInReplyStmt,
+		createDeclarationFormStmt:            q.createDeclarationFormStmt,
+		createDeclarationFormVersionStmt:     q.createDeclarationFormVersionStmt,
+		createEvacuationStmt:                 q.createEvacuationStmt,
+		createKioskStmt:                      q.createKioskStmt,
+		createNotificationStmt:               q.createNotificationStmt,
 		createOTPStmt:                        q.createOTPStmt,
+		createStreamTicketStmt:               q.createStreamTicketStmt,
 		createUserStmt:                       q.createUserStmt,
-		decrementAppointmentCountStmt:        q.decrementAppointmentCountStmt,
+		createVisitorPhotoStmt:               q.createVisitorPhotoStmt,
+		createWaitlistEntryStmt:              q.createWaitlistEntryStmt,
+		createWaitlistOfferStmt:              q.createWaitlistOfferStmt,
+		createWebhookSubscriptionStmt:        q.createWebhookSubscriptionStmt,
+		deadLetterExpiredLeasesStmt:          q.deadLetterExpiredLeasesStmt,
+		deleteAccessEventsByAppointmentStmt:  q.deleteAccessEventsByAppointmentStmt,
 		deleteAppointmentStmt:                q.deleteAppointmentStmt,
 		deleteAppointmentLogStmt:             q.deleteAppointmentLogStmt,
 		deleteAppointmentStatsStmt:           q.deleteAppointmentStatsStmt,
 		deleteAvailabilityByUserStmt:         q.deleteAvailabilityByUserStmt,
 		deleteAvailabilitySlotStmt:           q.deleteAvailabilitySlotStmt,
+		deleteBusyBlocksByImportStmt:         q.deleteBusyBlocksByImportStmt,
+		deleteCalendarImportStmt:             q.deleteCalendarImportStmt,
 		deleteExpiredOTPsStmt:                q.deleteExpiredOTPsStmt,
+		deleteExpiredStreamTicketsStmt:       q.deleteExpiredStreamTicketsStmt,
+		deleteMessageTemplateStmt:            q.deleteMessageTemplateStmt,
 		deleteOTPByPhoneStmt:                 q.deleteOTPByPhoneStmt,
 		deleteUserStmt:                       q.deleteUserStmt,
+		deleteVisitorPhotoStmt:               q.deleteVisitorPhotoStmt,
+		deleteWebhookSubscriptionStmt:        q.deleteWebhookSubscriptionStmt,
+		enqueueWebhookDeliveriesStmt:         q.enqueueWebhookDeliveriesStmt,
+		expireWaitlistOfferStmt:              q.expireWaitlistOfferStmt,
+		flagOverstaysStmt:                    q.flagOverstaysStmt,
+		getAccessEventByClientIDStmt:         q.getAccessEventByClientIDStmt,
+		getAppointmentBadgeStmt:              q.getAppointmentBadgeStmt,
 		getAppointmentByIDStmt:               q.getAppointmentByIDStmt,
 		getAppointmentByQRCodeStmt:           q.getAppointmentByQRCodeStmt,
+		getAppointmentForUpdateStmt:          q.getAppointmentForUpdateStmt,
 		getAppointmentLogByAppointmentIDStmt: q.getAppointmentLogByAppointmentIDStmt,
 		getAppointmentStatsByUserIDStmt:      q.getAppointmentStatsByUserIDStmt,
 		getAvailabilityByUserStmt:            q.getAvailabilityByUserStmt,
+		getCalendarFeedStmt:                  q.getCalendarFeedStmt,
+		getCalendarFeedByTokenHashStmt:       q.getCalendarFeedByTokenHashStmt,
+		getCalendarImportStmt:                q.getCalendarImportStmt,
+		getCalendarSummaryStmt:               q.getCalendarSummaryStmt,
+		getDailyVisitVolumesStmt:             q.getDailyVisitVolumesStmt,
+		getDeclarationFormStmt:               q.getDeclarationFormStmt,
+		getDeclarationFormVersionStmt:        q.getDeclarationFormVersionStmt,
+		getEvacuationStmt:                    q.getEvacuationStmt,
+		getKioskByKeyHashStmt:                q.getKioskByKeyHashStmt,
+		getLatestAccessEventStmt:             q.getLatestAccessEventStmt,
+		getLatestVisitorPhotoStmt:            q.getLatestVisitorPhotoStmt,
+		getLeaderboardStmt:                   q.getLeaderboardStmt,
+		getNextWaitlistEntryStmt:             q.getNextWaitlistEntryStmt,
 		getOTPByPhoneStmt:                    q.getOTPByPhoneStmt,
+		getOverstayNoticeStmt:                q.getOverstayNoticeStmt,
+		getOverstaySummaryStmt:               q.getOverstaySummaryStmt,
+		getPrecedingAccessEventStmt:          q.getPrecedingAccessEventStmt,
 		getTopPopularUsersStmt:               q.getTopPopularUsersStmt,
 		getTotalAppointmentsHostedStmt:       q.getTotalAppointmentsHostedStmt,
 		getTotalAppointmentsVisitedStmt:      q.getTotalAppointmentsVisitedStmt,
@@ -472,17 +1547,82 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getUserByIDStmt:                      q.getUserByIDStmt,
 		getUserByPhoneStmt:                   q.getUserByPhoneStmt,
 		getUsersByNameStmt:                   q.getUsersByNameStmt,
-		incrementAppointmentCountStmt:        q.incrementAppointmentCountStmt,
+		getVisitDurationStatsStmt:            q.getVisitDurationStatsStmt,
+		getVisitHeatmapStmt:                  q.getVisitHeatmapStmt,
+		getVisitOutcomeCountsStmt:            q.getVisitOutcomeCountsStmt,
+		getWaitlistEntryStmt:                 q.getWaitlistEntryStmt,
+		getWaitlistOfferByTokenStmt:          q.getWaitlistOfferByTokenStmt,
+		getWaitlistOfferForUpdateStmt:        q.getWaitlistOfferForUpdateStmt,
+		getWebhookSubscriptionStmt:           q.getWebhookSubscriptionStmt,
+		listAccessEventsByAppointmentStmt:    q.listAccessEventsByAppointmentStmt,
+		listAppointmentCountDriftStmt:        q.listAppointmentCountDriftStmt,
+		listAppointmentDeclarationsStmt:      q.listAppointmentDeclarationsStmt,
 		listAppointmentsByDateStmt:           q.listAppointmentsByDateStmt,
 		listAppointmentsByHostStmt:           q.listAppointmentsByHostStmt,
 		listAppointmentsByVisitorStmt:        q.listAppointmentsByVisitorStmt,
+		listAppointmentsPageAscStmt:          q.listAppointmentsPageAscStmt,
+		listAppointmentsPageDescStmt:         q.listAppointmentsPageDescStmt,
+		listBusyBlocksStmt:                   q.listBusyBlocksStmt,
+		listCalendarImportsByUserStmt:        q.listCalendarImportsByUserStmt,
+		listCheckInRepliesStmt:               q.listCheckInRepliesStmt,
+		listDeclarationFormVersionsStmt:      q.listDeclarationFormVersionsStmt,
+		listDeclarationFormsStmt:             q.listDeclarationFormsStmt,
+		listDueCalendarImportsStmt:           q.listDueCalendarImportsStmt,
+		listDueRemindersStmt:                 q.listDueRemindersStmt,
+		listEvacuationRollStmt:               q.listEvacuationRollStmt,
+		listEvacuationsStmt:                  q.listEvacuationsStmt,
+		listExpiredVisitorPhotosStmt:         q.listExpiredVisitorPhotosStmt,
+		listExpiredWaitlistOffersStmt:        q.listExpiredWaitlistOffersStmt,
+		listHostAppointmentTimesStmt:         q.listHostAppointmentTimesStmt,
+		listInboxNotificationsStmt:           q.listInboxNotificationsStmt,
+		listKioskManifestStmt:                q.listKioskManifestStmt,
+		listKiosksStmt:                       q.listKiosksStmt,
+		listLogbookPageStmt:                  q.listLogbookPageStmt,
+		listMessageTemplatesStmt:             q.listMessageTemplatesStmt,
+		listNotificationsByAppointmentStmt:   q.listNotificationsByAppointmentStmt,
+		listOnsiteVisitorsStmt:               q.listOnsiteVisitorsStmt,
+		listOverstaysStmt:                    q.listOverstaysStmt,
 		listUsersStmt:                        q.listUsersStmt,
-		resetAppointmentCountStmt:            q.resetAppointmentCountStmt,
+		listVisitorPhotosByAppointmentStmt:   q.listVisitorPhotosByAppointmentStmt,
+		listVisitorPhotosByUserStmt:          q.listVisitorPhotosByUserStmt,
+		listWaitlistByHostStmt:               q.listWaitlistByHostStmt,
+		listWaitlistByVisitorStmt:            q.listWaitlistByVisitorStmt,
+		listWebhookDeliveriesStmt:            q.listWebhookDeliveriesStmt,
+		listWebhookSubscriptionsStmt:         q.listWebhookSubscriptionsStmt,
+		markCalendarImportFailedStmt:         q.markCalendarImportFailedStmt,
+		markCalendarImportSyncedStmt:         q.markCalendarImportSyncedStmt,
+		markEvacuationRollEntryStmt:          q.markEvacuationRollEntryStmt,
+		markNotificationFailedStmt:           q.markNotificationFailedStmt,
+		markNotificationSentStmt:             q.markNotificationSentStmt,
+		markReminderSentStmt:                 q.markReminderSentStmt,
+		notifyLiveEventStmt:                  q.notifyLiveEventStmt,
+		reconcileAppointmentStatsStmt:        q.reconcileAppointmentStatsStmt,
+		reconcileUserAppointmentCountsStmt:   q.reconcileUserAppointmentCountsStmt,
+		redeemStreamTicketStmt:               q.redeemStreamTicketStmt,
+		redeliverWebhookStmt:                 q.redeliverWebhookStmt,
+		refreshAppointmentLogStmt:            q.refreshAppointmentLogStmt,
+		requestCalendarImportSyncStmt:        q.requestCalendarImportSyncStmt,
+		requeueNotificationStmt:              q.requeueNotificationStmt,
+		requeueWaitlistEntryStmt:             q.requeueWaitlistEntryStmt,
+		rescheduleAppointmentStmt:            q.rescheduleAppointmentStmt,
+		resolveOverstaysStmt:                 q.resolveOverstaysStmt,
+		rotateCalendarFeedStmt:               q.rotateCalendarFeedStmt,
+		snapshotEvacuationRollStmt:           q.snapshotEvacuationRollStmt,
+		touchKioskSyncStmt:                   q.touchKioskSyncStmt,
 		updateAppointmentStatusStmt:          q.updateAppointmentStatusStmt,
 		updateAvailabilityStatusStmt:         q.updateAvailabilityStatusStmt,
-		updateCheckInTimeStmt:                q.updateCheckInTimeStmt,
-		updateCheckOutTimeStmt:               q.updateCheckOutTimeStmt,
+		updateDeclarationFormStmt:            q.updateDeclarationFormStmt,
+		updateKioskActiveStmt:                q.updateKioskActiveStmt,
+		updateUserActiveStmt:                 q.updateUserActiveStmt,
+		updateUserDepartmentStmt:             q.updateUserDepartmentStmt,
+		updateUserEmailStmt:                  q.updateUserEmailStmt,
 		updateUserNameStmt:                   q.updateUserNameStmt,
+		updateUserNotificationChannelStmt:    q.updateUserNotificationChannelStmt,
+		updateUserPreferredLocaleStmt:        q.updateUserPreferredLocaleStmt,
 		updateUserRoleStmt:                   q.updateUserRoleStmt,
+		updateWaitlistEntryStatusStmt:        q.updateWaitlistEntryStatusStmt,
+		updateWebhookSubscriptionStmt:        q.updateWebhookSubscriptionStmt,
+		upsertDeclarationResponseStmt:        q.upsertDeclarationResponseStmt,
+		upsertMessageTemplateStmt:            q.upsertMessageTemplateStmt,
 	}
 }
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type CreateDeclarationFormTxParams struct {
+	CreateDeclarationFormParams
+	Body      string
+	Questions json.RawMessage
+}
+
+type DeclarationFormTxResult struct {
+	Form    DeclarationForm        `json:"form"`
+	Version DeclarationFormVersion `json:"version"`
+}
+
+// CreateDeclarationFormTx creates a form together with its first version.
+func (store *SQLStore) CreateDeclarationFormTx(ctx context.Context, arg CreateDeclarationFormTxParams) (DeclarationFormTxResult, error) {
+	var result DeclarationFormTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Form, err = q.CreateDeclarationForm(ctx, arg.CreateDeclarationFormParams)
+		if err != nil {
+			return err
+		}
+
+		result.Version, err = q.CreateDeclarationFormVersion(ctx, CreateDeclarationFormVersionParams{
+			FormID:    result.Form.ID,
+			Body:      arg.Body,
+			Questions: arg.Questions,
+		})
+		return err
+	})
+
+	return result, err
+}
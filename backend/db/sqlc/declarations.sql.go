@@ -0,0 +1,403 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: declarations.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const countPendingDeclarations = `-- name: CountPendingDeclarations :one
+SELECT COUNT(*)::int FROM declaration_forms f
+WHERE f.is_active AND f.is_required
+  AND EXISTS (
+    SELECT 1 FROM declaration_form_versions v
+    WHERE v.form_id = f.id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM declaration_responses r
+    JOIN declaration_form_versions v ON r.form_version_id = v.id
+    WHERE v.form_id = f.id AND r.appointment_id = $1
+  )
+`
+
+// Required, active forms this visit has not signed in any version. A visitor
+// who signed an earlier version may re-enter after a new one is published;
+// only visits that have signed nothing yet must sign the current version.
+func (q *Queries) CountPendingDeclarations(ctx context.Context, appointmentID int32) (int32, error) {
+	row := q.queryRow(ctx, q.countPendingDeclarationsStmt, countPendingDeclarations, appointmentID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createDeclarationForm = `-- name: CreateDeclarationForm :one
+INSERT INTO declaration_forms (
+  title, kind, is_required, created_by
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, title, kind, is_required, is_active, created_by, created_at
+`
+
+type CreateDeclarationFormParams struct {
+	Title      string        `json:"title"`
+	Kind       string        `json:"kind"`
+	IsRequired bool          `json:"is_required"`
+	CreatedBy  sql.NullInt32 `json:"created_by"`
+}
+
+func (q *Queries) CreateDeclarationForm(ctx context.Context, arg CreateDeclarationFormParams) (DeclarationForm, error) {
+	row := q.queryRow(ctx, q.createDeclarationFormStmt, createDeclarationForm,
+		arg.Title,
+		arg.Kind,
+		arg.IsRequired,
+		arg.CreatedBy,
+	)
+	var i DeclarationForm
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Kind,
+		&i.IsRequired,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDeclarationFormVersion = `-- name: CreateDeclarationFormVersion :one
+INSERT INTO declaration_form_versions (
+  form_id, version, body, questions
+)
+SELECT
+  $1::int,
+  COALESCE(MAX(v.version), 0) + 1,
+  $2::text,
+  $3::jsonb
+FROM declaration_form_versions v
+WHERE v.form_id = $1::int
+RETURNING id, form_id, version, body, questions, created_at
+`
+
+type CreateDeclarationFormVersionParams struct {
+	FormID    int32           `json:"form_id"`
+	Body      string          `json:"body"`
+	Questions json.RawMessage `json:"questions"`
+}
+
+// Versions are numbered per form; the unique (form_id, version) constraint
+// rejects a concurrent publish instead of creating two identical numbers.
+func (q *Queries) CreateDeclarationFormVersion(ctx context.Context, arg CreateDeclarationFormVersionParams) (DeclarationFormVersion, error) {
+	row := q.queryRow(ctx, q.createDeclarationFormVersionStmt, createDeclarationFormVersion, arg.FormID, arg.Body, arg.Questions)
+	var i DeclarationFormVersion
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Version,
+		&i.Body,
+		&i.Questions,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDeclarationForm = `-- name: GetDeclarationForm :one
+SELECT id, title, kind, is_required, is_active, created_by, created_at FROM declaration_forms
+WHERE id = $1
+`
+
+func (q *Queries) GetDeclarationForm(ctx context.Context, id int32) (DeclarationForm, error) {
+	row := q.queryRow(ctx, q.getDeclarationFormStmt, getDeclarationForm, id)
+	var i DeclarationForm
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Kind,
+		&i.IsRequired,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDeclarationFormVersion = `-- name: GetDeclarationFormVersion :one
+SELECT id, form_id, version, body, questions, created_at FROM declaration_form_versions
+WHERE id = $1
+`
+
+func (q *Queries) GetDeclarationFormVersion(ctx context.Context, id int32) (DeclarationFormVersion, error) {
+	row := q.queryRow(ctx, q.getDeclarationFormVersionStmt, getDeclarationFormVersion, id)
+	var i DeclarationFormVersion
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Version,
+		&i.Body,
+		&i.Questions,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAppointmentDeclarations = `-- name: ListAppointmentDeclarations :many
+SELECT
+  f.id AS form_id, f.title, f.kind, f.is_required,
+  v.id AS version_id, v.version, v.body, v.questions,
+  r.id AS response_id,
+  r.form_version_id AS response_version_id,
+  COALESCE(r.answers, 'null'::jsonb) AS answers,
+  r.signed_name, r.accepted_at
+FROM declaration_forms f
+JOIN LATERAL (
+  SELECT id, form_id, version, body, questions, created_at FROM declaration_form_versions
+  WHERE form_id = f.id
+  ORDER BY version DESC
+  LIMIT 1
+) v ON true
+LEFT JOIN declaration_responses r ON r.id = (
+  SELECT dr.id FROM declaration_responses dr
+  JOIN declaration_form_versions dv ON dr.form_version_id = dv.id
+  WHERE dv.form_id = f.id AND dr.appointment_id = $1
+  ORDER BY dv.version DESC
+  LIMIT 1
+)
+WHERE f.is_active
+ORDER BY f.is_required DESC, f.id
+`
+
+type ListAppointmentDeclarationsRow struct {
+	FormID            int32           `json:"form_id"`
+	Title             string          `json:"title"`
+	Kind              string          `json:"kind"`
+	IsRequired        bool            `json:"is_required"`
+	VersionID         int32           `json:"version_id"`
+	Version           int32           `json:"version"`
+	Body              string          `json:"body"`
+	Questions         json.RawMessage `json:"questions"`
+	ResponseID        sql.NullInt32   `json:"response_id"`
+	ResponseVersionID sql.NullInt32   `json:"response_version_id"`
+	Answers           json.RawMessage `json:"answers"`
+	SignedName        sql.NullString  `json:"signed_name"`
+	AcceptedAt        sql.NullTime    `json:"accepted_at"`
+}
+
+// The current version of every active form, with this visit's latest response
+// to any version of it. A form signed in an earlier version counts as signed,
+// the same rule CountPendingDeclarations applies; response_version_id tells
+// the two apart.
+func (q *Queries) ListAppointmentDeclarations(ctx context.Context, appointmentID int32) ([]ListAppointmentDeclarationsRow, error) {
+	rows, err := q.query(ctx, q.listAppointmentDeclarationsStmt, listAppointmentDeclarations, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAppointmentDeclarationsRow{}
+	for rows.Next() {
+		var i ListAppointmentDeclarationsRow
+		if err := rows.Scan(
+			&i.FormID,
+			&i.Title,
+			&i.Kind,
+			&i.IsRequired,
+			&i.VersionID,
+			&i.Version,
+			&i.Body,
+			&i.Questions,
+			&i.ResponseID,
+			&i.ResponseVersionID,
+			&i.Answers,
+			&i.SignedName,
+			&i.AcceptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeclarationFormVersions = `-- name: ListDeclarationFormVersions :many
+SELECT id, form_id, version, body, questions, created_at FROM declaration_form_versions
+WHERE form_id = $1
+ORDER BY version DESC
+`
+
+func (q *Queries) ListDeclarationFormVersions(ctx context.Context, formID int32) ([]DeclarationFormVersion, error) {
+	rows, err := q.query(ctx, q.listDeclarationFormVersionsStmt, listDeclarationFormVersions, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DeclarationFormVersion{}
+	for rows.Next() {
+		var i DeclarationFormVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.FormID,
+			&i.Version,
+			&i.Body,
+			&i.Questions,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeclarationForms = `-- name: ListDeclarationForms :many
+SELECT
+  f.id, f.title, f.kind, f.is_required, f.is_active, f.created_at,
+  v.id AS version_id, v.version, v.body, v.questions
+FROM declaration_forms f
+JOIN LATERAL (
+  SELECT id, form_id, version, body, questions, created_at FROM declaration_form_versions
+  WHERE form_id = f.id
+  ORDER BY version DESC
+  LIMIT 1
+) v ON true
+WHERE $1::boolean IS NULL OR f.is_active = $1::boolean
+ORDER BY f.id
+`
+
+type ListDeclarationFormsRow struct {
+	ID         int32           `json:"id"`
+	Title      string          `json:"title"`
+	Kind       string          `json:"kind"`
+	IsRequired bool            `json:"is_required"`
+	IsActive   bool            `json:"is_active"`
+	CreatedAt  time.Time       `json:"created_at"`
+	VersionID  int32           `json:"version_id"`
+	Version    int32           `json:"version"`
+	Body       string          `json:"body"`
+	Questions  json.RawMessage `json:"questions"`
+}
+
+// Each form with its current (latest) version.
+func (q *Queries) ListDeclarationForms(ctx context.Context, activeOnly sql.NullBool) ([]ListDeclarationFormsRow, error) {
+	rows, err := q.query(ctx, q.listDeclarationFormsStmt, listDeclarationForms, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDeclarationFormsRow{}
+	for rows.Next() {
+		var i ListDeclarationFormsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Kind,
+			&i.IsRequired,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.VersionID,
+			&i.Version,
+			&i.Body,
+			&i.Questions,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDeclarationForm = `-- name: UpdateDeclarationForm :one
+UPDATE declaration_forms
+SET title = $2, is_required = $3, is_active = $4
+WHERE id = $1
+RETURNING id, title, kind, is_required, is_active, created_by, created_at
+`
+
+type UpdateDeclarationFormParams struct {
+	ID         int32  `json:"id"`
+	Title      string `json:"title"`
+	IsRequired bool   `json:"is_required"`
+	IsActive   bool   `json:"is_active"`
+}
+
+func (q *Queries) UpdateDeclarationForm(ctx context.Context, arg UpdateDeclarationFormParams) (DeclarationForm, error) {
+	row := q.queryRow(ctx, q.updateDeclarationFormStmt, updateDeclarationForm,
+		arg.ID,
+		arg.Title,
+		arg.IsRequired,
+		arg.IsActive,
+	)
+	var i DeclarationForm
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Kind,
+		&i.IsRequired,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertDeclarationResponse = `-- name: UpsertDeclarationResponse :one
+INSERT INTO declaration_responses (
+  appointment_id, form_version_id, answers, signed_name
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (appointment_id, form_version_id) DO UPDATE
+SET answers = EXCLUDED.answers,
+    signed_name = EXCLUDED.signed_name,
+    accepted_at = now()
+RETURNING id, appointment_id, form_version_id, answers, signed_name, accepted_at
+`
+
+type UpsertDeclarationResponseParams struct {
+	AppointmentID int32           `json:"appointment_id"`
+	FormVersionID int32           `json:"form_version_id"`
+	Answers       json.RawMessage `json:"answers"`
+	SignedName    string          `json:"signed_name"`
+}
+
+func (q *Queries) UpsertDeclarationResponse(ctx context.Context, arg UpsertDeclarationResponseParams) (DeclarationResponse, error) {
+	row := q.queryRow(ctx, q.upsertDeclarationResponseStmt, upsertDeclarationResponse,
+		arg.AppointmentID,
+		arg.FormVersionID,
+		arg.Answers,
+		arg.SignedName,
+	)
+	var i DeclarationResponse
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.FormVersionID,
+		&i.Answers,
+		&i.SignedName,
+		&i.AcceptedAt,
+	)
+	return i, err
+}
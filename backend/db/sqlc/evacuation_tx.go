@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+type StartEvacuationTxResult struct {
+	Evacuation Evacuation       `json:"evacuation"`
+	Roll       []EvacuationRoll `json:"roll"`
+}
+
+// StartEvacuationTx opens a new evacuation and snapshots everyone currently
+// checked in onto its roll, so later check-outs don't change who marshals
+// have to account for.
+func (store *SQLStore) StartEvacuationTx(ctx context.Context, startedBy int32) (StartEvacuationTxResult, error) {
+	var result StartEvacuationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Evacuation, err = q.CreateEvacuation(ctx, sql.NullInt32{Int32: startedBy, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		result.Roll, err = q.SnapshotEvacuationRoll(ctx, result.Evacuation.ID)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,268 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: evacuations.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const closeEvacuation = `-- name: CloseEvacuation :one
+UPDATE evacuations
+SET status = 'closed',
+    closed_by = $2,
+    closed_at = NOW()
+WHERE id = $1
+  AND status = 'active'
+RETURNING id, status, started_by, started_at, closed_by, closed_at
+`
+
+type CloseEvacuationParams struct {
+	ID       int32         `json:"id"`
+	ClosedBy sql.NullInt32 `json:"closed_by"`
+}
+
+func (q *Queries) CloseEvacuation(ctx context.Context, arg CloseEvacuationParams) (Evacuation, error) {
+	row := q.queryRow(ctx, q.closeEvacuationStmt, closeEvacuation, arg.ID, arg.ClosedBy)
+	var i Evacuation
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.StartedBy,
+		&i.StartedAt,
+		&i.ClosedBy,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const createEvacuation = `-- name: CreateEvacuation :one
+INSERT INTO evacuations (
+  started_by
+) VALUES (
+  $1
+)
+RETURNING id, status, started_by, started_at, closed_by, closed_at
+`
+
+func (q *Queries) CreateEvacuation(ctx context.Context, startedBy sql.NullInt32) (Evacuation, error) {
+	row := q.queryRow(ctx, q.createEvacuationStmt, createEvacuation, startedBy)
+	var i Evacuation
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.StartedBy,
+		&i.StartedAt,
+		&i.ClosedBy,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const getEvacuation = `-- name: GetEvacuation :one
+SELECT id, status, started_by, started_at, closed_by, closed_at FROM evacuations
+WHERE id = $1
+`
+
+func (q *Queries) GetEvacuation(ctx context.Context, id int32) (Evacuation, error) {
+	row := q.queryRow(ctx, q.getEvacuationStmt, getEvacuation, id)
+	var i Evacuation
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.StartedBy,
+		&i.StartedAt,
+		&i.ClosedBy,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const listEvacuationRoll = `-- name: ListEvacuationRoll :many
+SELECT id, evacuation_id, appointment_id, visitor_id, visitor_name, visitor_phone, host_name, location, check_in_time, accounted_for, accounted_by, accounted_at FROM evacuation_roll
+WHERE evacuation_id = $1
+ORDER BY accounted_for, visitor_name
+`
+
+func (q *Queries) ListEvacuationRoll(ctx context.Context, evacuationID int32) ([]EvacuationRoll, error) {
+	rows, err := q.query(ctx, q.listEvacuationRollStmt, listEvacuationRoll, evacuationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EvacuationRoll{}
+	for rows.Next() {
+		var i EvacuationRoll
+		if err := rows.Scan(
+			&i.ID,
+			&i.EvacuationID,
+			&i.AppointmentID,
+			&i.VisitorID,
+			&i.VisitorName,
+			&i.VisitorPhone,
+			&i.HostName,
+			&i.Location,
+			&i.CheckInTime,
+			&i.AccountedFor,
+			&i.AccountedBy,
+			&i.AccountedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEvacuations = `-- name: ListEvacuations :many
+SELECT id, status, started_by, started_at, closed_by, closed_at FROM evacuations
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListEvacuationsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListEvacuations(ctx context.Context, arg ListEvacuationsParams) ([]Evacuation, error) {
+	rows, err := q.query(ctx, q.listEvacuationsStmt, listEvacuations, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Evacuation{}
+	for rows.Next() {
+		var i Evacuation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.StartedBy,
+			&i.StartedAt,
+			&i.ClosedBy,
+			&i.ClosedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markEvacuationRollEntry = `-- name: MarkEvacuationRollEntry :one
+UPDATE evacuation_roll r
+SET accounted_for = $1::bool,
+    accounted_by = CASE WHEN $1::bool THEN $2::int END,
+    accounted_at = CASE WHEN $1::bool THEN NOW() END
+FROM evacuations e
+WHERE r.id = $3
+  AND r.evacuation_id = $4
+  AND e.id = r.evacuation_id
+  AND e.status = 'active'
+RETURNING r.id, r.evacuation_id, r.appointment_id, r.visitor_id, r.visitor_name, r.visitor_phone, r.host_name, r.location, r.check_in_time, r.accounted_for, r.accounted_by, r.accounted_at
+`
+
+type MarkEvacuationRollEntryParams struct {
+	AccountedFor bool          `json:"accounted_for"`
+	AccountedBy  sql.NullInt32 `json:"accounted_by"`
+	ID           int32         `json:"id"`
+	EvacuationID int32         `json:"evacuation_id"`
+}
+
+func (q *Queries) MarkEvacuationRollEntry(ctx context.Context, arg MarkEvacuationRollEntryParams) (EvacuationRoll, error) {
+	row := q.queryRow(ctx, q.markEvacuationRollEntryStmt, markEvacuationRollEntry,
+		arg.AccountedFor,
+		arg.AccountedBy,
+		arg.ID,
+		arg.EvacuationID,
+	)
+	var i EvacuationRoll
+	err := row.Scan(
+		&i.ID,
+		&i.EvacuationID,
+		&i.AppointmentID,
+		&i.VisitorID,
+		&i.VisitorName,
+		&i.VisitorPhone,
+		&i.HostName,
+		&i.Location,
+		&i.CheckInTime,
+		&i.AccountedFor,
+		&i.AccountedBy,
+		&i.AccountedAt,
+	)
+	return i, err
+}
+
+const snapshotEvacuationRoll = `-- name: SnapshotEvacuationRoll :many
+INSERT INTO evacuation_roll (
+  evacuation_id, appointment_id, visitor_id, visitor_name, visitor_phone, host_name, location, check_in_time
+)
+SELECT
+  $1::int,
+  l.appointment_id,
+  a.visitor_id,
+  visitor.first_name || ' ' || visitor.last_name,
+  visitor.phone_number,
+  host.first_name || ' ' || host.last_name,
+  a.location,
+  l.check_in_time
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+RETURNING id, evacuation_id, appointment_id, visitor_id, visitor_name, visitor_phone, host_name, location, check_in_time, accounted_for, accounted_by, accounted_at
+`
+
+func (q *Queries) SnapshotEvacuationRoll(ctx context.Context, evacuationID int32) ([]EvacuationRoll, error) {
+	rows, err := q.query(ctx, q.snapshotEvacuationRollStmt, snapshotEvacuationRoll, evacuationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EvacuationRoll{}
+	for rows.Next() {
+		var i EvacuationRoll
+		if err := rows.Scan(
+			&i.ID,
+			&i.EvacuationID,
+			&i.AppointmentID,
+			&i.VisitorID,
+			&i.VisitorName,
+			&i.VisitorPhone,
+			&i.HostName,
+			&i.Location,
+			&i.CheckInTime,
+			&i.AccountedFor,
+			&i.AccountedBy,
+			&i.AccountedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,106 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: exports.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listLogbookPage = `-- name: ListLogbookPage :many
+SELECT
+  a.id,
+  a.appointment_date,
+  a.start_time,
+  a.end_time,
+  a.status,
+  a.purpose,
+  a.purpose_category,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  l.check_in_time,
+  l.check_out_time
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN appointment_logs l ON l.appointment_id = a.id
+WHERE a.appointment_date BETWEEN $1::date AND $2::date
+  AND (
+    $3::int IS NULL
+    OR (a.appointment_date, a.start_time, a.id) > ($4::date, $5::time, $3::int)
+  )
+ORDER BY a.appointment_date, a.start_time, a.id
+LIMIT $6
+`
+
+type ListLogbookPageParams struct {
+	FromDate   time.Time     `json:"from_date"`
+	ToDate     time.Time     `json:"to_date"`
+	CursorID   sql.NullInt32 `json:"cursor_id"`
+	CursorDate sql.NullTime  `json:"cursor_date"`
+	CursorTime sql.NullTime  `json:"cursor_time"`
+	PageSize   int32         `json:"page_size"`
+}
+
+type ListLogbookPageRow struct {
+	ID              int32          `json:"id"`
+	AppointmentDate time.Time      `json:"appointment_date"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	Status          sql.NullString `json:"status"`
+	Purpose         sql.NullString `json:"purpose"`
+	PurposeCategory sql.NullString `json:"purpose_category"`
+	VisitorName     string         `json:"visitor_name"`
+	VisitorPhone    string         `json:"visitor_phone"`
+	HostName        string         `json:"host_name"`
+	CheckInTime     sql.NullTime   `json:"check_in_time"`
+	CheckOutTime    sql.NullTime   `json:"check_out_time"`
+}
+
+func (q *Queries) ListLogbookPage(ctx context.Context, arg ListLogbookPageParams) ([]ListLogbookPageRow, error) {
+	rows, err := q.query(ctx, q.listLogbookPageStmt, listLogbookPage,
+		arg.FromDate,
+		arg.ToDate,
+		arg.CursorID,
+		arg.CursorDate,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLogbookPageRow{}
+	for rows.Next() {
+		var i ListLogbookPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Status,
+			&i.Purpose,
+			&i.PurposeCategory,
+			&i.VisitorName,
+			&i.VisitorPhone,
+			&i.HostName,
+			&i.CheckInTime,
+			&i.CheckOutTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
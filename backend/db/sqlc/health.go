@@ -0,0 +1,16 @@
+package db
+
+import "context"
+
+// Ping checks that the database can be reached.
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.db.PingContext(ctx)
+}
+
+// MigrationVersion returns the schema version golang-migrate last applied,
+// and whether that migration failed part way. schema_migrations is managed by
+// migrate rather than our migrations, so sqlc cannot generate this query.
+func (store *SQLStore) MigrationVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	err = store.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	return version, dirty, err
+}
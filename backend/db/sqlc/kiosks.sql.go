@@ -0,0 +1,227 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: kiosks.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createKiosk = `-- name: CreateKiosk :one
+INSERT INTO kiosks (
+  name, gate, api_key_hash, created_by
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, name, gate, api_key_hash, is_active, last_sync_at, created_by, created_at
+`
+
+type CreateKioskParams struct {
+	Name       string         `json:"name"`
+	Gate       sql.NullString `json:"gate"`
+	ApiKeyHash string         `json:"api_key_hash"`
+	CreatedBy  sql.NullInt32  `json:"created_by"`
+}
+
+func (q *Queries) CreateKiosk(ctx context.Context, arg CreateKioskParams) (Kiosk, error) {
+	row := q.queryRow(ctx, q.createKioskStmt, createKiosk,
+		arg.Name,
+		arg.Gate,
+		arg.ApiKeyHash,
+		arg.CreatedBy,
+	)
+	var i Kiosk
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Gate,
+		&i.ApiKeyHash,
+		&i.IsActive,
+		&i.LastSyncAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getKioskByKeyHash = `-- name: GetKioskByKeyHash :one
+SELECT id, name, gate, api_key_hash, is_active, last_sync_at, created_by, created_at FROM kiosks
+WHERE api_key_hash = $1
+`
+
+func (q *Queries) GetKioskByKeyHash(ctx context.Context, apiKeyHash string) (Kiosk, error) {
+	row := q.queryRow(ctx, q.getKioskByKeyHashStmt, getKioskByKeyHash, apiKeyHash)
+	var i Kiosk
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Gate,
+		&i.ApiKeyHash,
+		&i.IsActive,
+		&i.LastSyncAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listKioskManifest = `-- name: ListKioskManifest :many
+SELECT
+  a.id AS appointment_id, a.qr_code, a.status, a.appointment_date,
+  a.start_time, a.end_time, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  (host.first_name || ' ' || host.last_name)::text AS host_name,
+  (l.check_in_time IS NOT NULL AND l.check_out_time IS NULL)::boolean AS on_site,
+  (
+    -- Same rule as CountPendingDeclarations: any signed version counts
+    SELECT COUNT(*) FROM declaration_forms f
+    WHERE f.is_active AND f.is_required
+      AND EXISTS (
+        SELECT 1 FROM declaration_form_versions v
+        WHERE v.form_id = f.id
+      )
+      AND NOT EXISTS (
+        SELECT 1 FROM declaration_responses r
+        JOIN declaration_form_versions v ON r.form_version_id = v.id
+        WHERE v.form_id = f.id AND r.appointment_id = a.id
+      )
+  )::int AS declarations_pending
+FROM appointments a
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN appointment_logs l ON l.appointment_id = a.id
+WHERE a.appointment_date = $1
+  AND a.status IN ('pending', 'ongoing')
+  AND a.qr_code IS NOT NULL
+ORDER BY a.start_time, a.id
+`
+
+type ListKioskManifestRow struct {
+	AppointmentID       int32          `json:"appointment_id"`
+	QrCode              sql.NullString `json:"qr_code"`
+	Status              sql.NullString `json:"status"`
+	AppointmentDate     time.Time      `json:"appointment_date"`
+	StartTime           time.Time      `json:"start_time"`
+	EndTime             time.Time      `json:"end_time"`
+	Location            sql.NullString `json:"location"`
+	VisitorName         string         `json:"visitor_name"`
+	HostName            string         `json:"host_name"`
+	OnSite              bool           `json:"on_site"`
+	DeclarationsPending int32          `json:"declarations_pending"`
+}
+
+// Everything a kiosk needs to validate the day's scans while offline.
+func (q *Queries) ListKioskManifest(ctx context.Context, appointmentDate time.Time) ([]ListKioskManifestRow, error) {
+	rows, err := q.query(ctx, q.listKioskManifestStmt, listKioskManifest, appointmentDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListKioskManifestRow{}
+	for rows.Next() {
+		var i ListKioskManifestRow
+		if err := rows.Scan(
+			&i.AppointmentID,
+			&i.QrCode,
+			&i.Status,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Location,
+			&i.VisitorName,
+			&i.HostName,
+			&i.OnSite,
+			&i.DeclarationsPending,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listKiosks = `-- name: ListKiosks :many
+SELECT id, name, gate, api_key_hash, is_active, last_sync_at, created_by, created_at FROM kiosks
+ORDER BY id
+`
+
+func (q *Queries) ListKiosks(ctx context.Context) ([]Kiosk, error) {
+	rows, err := q.query(ctx, q.listKiosksStmt, listKiosks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Kiosk{}
+	for rows.Next() {
+		var i Kiosk
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Gate,
+			&i.ApiKeyHash,
+			&i.IsActive,
+			&i.LastSyncAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchKioskSync = `-- name: TouchKioskSync :exec
+UPDATE kiosks
+SET last_sync_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchKioskSync(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.touchKioskSyncStmt, touchKioskSync, id)
+	return err
+}
+
+const updateKioskActive = `-- name: UpdateKioskActive :one
+UPDATE kiosks
+SET is_active = $2
+WHERE id = $1
+RETURNING id, name, gate, api_key_hash, is_active, last_sync_at, created_by, created_at
+`
+
+type UpdateKioskActiveParams struct {
+	ID       int32 `json:"id"`
+	IsActive bool  `json:"is_active"`
+}
+
+func (q *Queries) UpdateKioskActive(ctx context.Context, arg UpdateKioskActiveParams) (Kiosk, error) {
+	row := q.queryRow(ctx, q.updateKioskActiveStmt, updateKioskActive, arg.ID, arg.IsActive)
+	var i Kiosk
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Gate,
+		&i.ApiKeyHash,
+		&i.IsActive,
+		&i.LastSyncAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
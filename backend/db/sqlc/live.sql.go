@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: live.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createStreamTicket = `-- name: CreateStreamTicket :exec
+INSERT INTO stream_tickets (ticket_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateStreamTicketParams struct {
+	TicketHash string    `json:"ticket_hash"`
+	UserID     int32     `json:"user_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateStreamTicket(ctx context.Context, arg CreateStreamTicketParams) error {
+	_, err := q.exec(ctx, q.createStreamTicketStmt, createStreamTicket, arg.TicketHash, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const deleteExpiredStreamTickets = `-- name: DeleteExpiredStreamTickets :exec
+DELETE FROM stream_tickets
+WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredStreamTickets(ctx context.Context) error {
+	_, err := q.exec(ctx, q.deleteExpiredStreamTicketsStmt, deleteExpiredStreamTickets)
+	return err
+}
+
+const notifyLiveEvent = `-- name: NotifyLiveEvent :exec
+SELECT pg_notify('live_events', json_build_object(
+  'event', $1::text,
+  'appointment_id', a.id,
+  'host_id', a.host_id,
+  'visitor_id', a.visitor_id,
+  'host_name', host.first_name || ' ' || host.last_name,
+  'visitor_name', visitor.first_name || ' ' || visitor.last_name,
+  'status', a.status,
+  'appointment_date', a.appointment_date,
+  'start_time', to_char(a.start_time, 'HH24:MI'),
+  'end_time', to_char(a.end_time, 'HH24:MI'),
+  'occurred_at', now()
+)::text)
+FROM appointments a
+JOIN users host ON a.host_id = host.id
+JOIN users visitor ON a.visitor_id = visitor.id
+WHERE a.id = $2
+`
+
+type NotifyLiveEventParams struct {
+	Event         string `json:"event"`
+	AppointmentID int32  `json:"appointment_id"`
+}
+
+// Tells live dashboards on every replica about a change to an appointment.
+// Postgres only delivers it once the surrounding transaction commits.
+func (q *Queries) NotifyLiveEvent(ctx context.Context, arg NotifyLiveEventParams) error {
+	_, err := q.exec(ctx, q.notifyLiveEventStmt, notifyLiveEvent, arg.Event, arg.AppointmentID)
+	return err
+}
+
+const redeemStreamTicket = `-- name: RedeemStreamTicket :one
+WITH redeemed AS (
+  DELETE FROM stream_tickets
+  WHERE ticket_hash = $1
+    AND expires_at > now()
+  RETURNING user_id
+)
+SELECT u.id, u.role
+FROM redeemed
+JOIN users u ON u.id = redeemed.user_id
+`
+
+type RedeemStreamTicketRow struct {
+	ID   int32          `json:"id"`
+	Role sql.NullString `json:"role"`
+}
+
+// Deletes the ticket as it is used, so it works once. The role is read now
+// rather than when the ticket was issued.
+func (q *Queries) RedeemStreamTicket(ctx context.Context, ticketHash string) (RedeemStreamTicketRow, error) {
+	row := q.queryRow(ctx, q.redeemStreamTicketStmt, redeemStreamTicket, ticketHash)
+	var i RedeemStreamTicketRow
+	err := row.Scan(&i.ID, &i.Role)
+	return i, err
+}
@@ -0,0 +1,103 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: message_templates.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const deleteMessageTemplate = `-- name: DeleteMessageTemplate :execrows
+DELETE FROM message_templates
+WHERE locale = $1 AND key = $2
+`
+
+type DeleteMessageTemplateParams struct {
+	Locale string `json:"locale"`
+	Key    string `json:"key"`
+}
+
+func (q *Queries) DeleteMessageTemplate(ctx context.Context, arg DeleteMessageTemplateParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteMessageTemplateStmt, deleteMessageTemplate, arg.Locale, arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listMessageTemplates = `-- name: ListMessageTemplates :many
+SELECT locale, key, subject, body, updated_by, updated_at FROM message_templates
+ORDER BY locale, key
+`
+
+func (q *Queries) ListMessageTemplates(ctx context.Context) ([]MessageTemplate, error) {
+	rows, err := q.query(ctx, q.listMessageTemplatesStmt, listMessageTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MessageTemplate{}
+	for rows.Next() {
+		var i MessageTemplate
+		if err := rows.Scan(
+			&i.Locale,
+			&i.Key,
+			&i.Subject,
+			&i.Body,
+			&i.UpdatedBy,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertMessageTemplate = `-- name: UpsertMessageTemplate :one
+INSERT INTO message_templates (locale, key, subject, body, updated_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (locale, key) DO UPDATE
+SET subject = EXCLUDED.subject,
+    body = EXCLUDED.body,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING locale, key, subject, body, updated_by, updated_at
+`
+
+type UpsertMessageTemplateParams struct {
+	Locale    string        `json:"locale"`
+	Key       string        `json:"key"`
+	Subject   string        `json:"subject"`
+	Body      string        `json:"body"`
+	UpdatedBy sql.NullInt32 `json:"updated_by"`
+}
+
+func (q *Queries) UpsertMessageTemplate(ctx context.Context, arg UpsertMessageTemplateParams) (MessageTemplate, error) {
+	row := q.queryRow(ctx, q.upsertMessageTemplateStmt, upsertMessageTemplate,
+		arg.Locale,
+		arg.Key,
+		arg.Subject,
+		arg.Body,
+		arg.UpdatedBy,
+	)
+	var i MessageTemplate
+	err := row.Scan(
+		&i.Locale,
+		&i.Key,
+		&i.Subject,
+		&i.Body,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
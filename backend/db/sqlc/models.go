@@ -6,19 +6,42 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AccessEvent struct {
+	ID            int32          `json:"id"`
+	AppointmentID int32          `json:"appointment_id"`
+	Direction     string         `json:"direction"`
+	ScannedAt     time.Time      `json:"scanned_at"`
+	Gate          sql.NullString `json:"gate"`
+	ScannedBy     sql.NullInt32  `json:"scanned_by"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	KioskID       sql.NullInt32  `json:"kiosk_id"`
+	ClientEventID sql.NullString `json:"client_event_id"`
+}
+
 type Appointment struct {
-	ID              int32          `json:"id"`
-	VisitorID       int32          `json:"visitor_id"`
-	HostID          int32          `json:"host_id"`
-	AppointmentDate time.Time      `json:"appointment_date"`
-	StartTime       time.Time      `json:"start_time"`
-	EndTime         time.Time      `json:"end_time"`
-	Status          sql.NullString `json:"status"`
-	QrCode          sql.NullString `json:"qr_code"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	QrCode             sql.NullString `json:"qr_code"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	HostNotes          sql.NullString `json:"host_notes"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+	ReminderSentAt     sql.NullTime   `json:"reminder_sent_at"`
+	IcsSequence        int32          `json:"ics_sequence"`
 }
 
 type AppointmentLog struct {
@@ -42,6 +65,135 @@ type Availability struct {
 	Status    sql.NullString `json:"status"`
 }
 
+type BusyBlock struct {
+	ID       int64     `json:"id"`
+	ImportID int32     `json:"import_id"`
+	UserID   int32     `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Summary  string    `json:"summary"`
+}
+
+type CalendarFeed struct {
+	UserID    int32     `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CalendarImport struct {
+	ID              int32          `json:"id"`
+	UserID          int32          `json:"user_id"`
+	Name            string         `json:"name"`
+	Url             sql.NullString `json:"url"`
+	IcsData         sql.NullString `json:"ics_data"`
+	BusyCount       int32          `json:"busy_count"`
+	LastSyncedAt    sql.NullTime   `json:"last_synced_at"`
+	LastAttemptedAt sql.NullTime   `json:"last_attempted_at"`
+	LastError       sql.NullString `json:"last_error"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type CheckinReply struct {
+	ID            int32          `json:"id"`
+	AppointmentID int32          `json:"appointment_id"`
+	HostID        int32          `json:"host_id"`
+	Reply         string         `json:"reply"`
+	Message       sql.NullString `json:"message"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type DeclarationForm struct {
+	ID         int32         `json:"id"`
+	Title      string        `json:"title"`
+	Kind       string        `json:"kind"`
+	IsRequired bool          `json:"is_required"`
+	IsActive   bool          `json:"is_active"`
+	CreatedBy  sql.NullInt32 `json:"created_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type DeclarationFormVersion struct {
+	ID        int32           `json:"id"`
+	FormID    int32           `json:"form_id"`
+	Version   int32           `json:"version"`
+	Body      string          `json:"body"`
+	Questions json.RawMessage `json:"questions"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type DeclarationResponse struct {
+	ID            int32           `json:"id"`
+	AppointmentID int32           `json:"appointment_id"`
+	FormVersionID int32           `json:"form_version_id"`
+	Answers       json.RawMessage `json:"answers"`
+	SignedName    string          `json:"signed_name"`
+	AcceptedAt    time.Time       `json:"accepted_at"`
+}
+
+type Evacuation struct {
+	ID        int32          `json:"id"`
+	Status    sql.NullString `json:"status"`
+	StartedBy sql.NullInt32  `json:"started_by"`
+	StartedAt time.Time      `json:"started_at"`
+	ClosedBy  sql.NullInt32  `json:"closed_by"`
+	ClosedAt  sql.NullTime   `json:"closed_at"`
+}
+
+type EvacuationRoll struct {
+	ID            int32          `json:"id"`
+	EvacuationID  int32          `json:"evacuation_id"`
+	AppointmentID sql.NullInt32  `json:"appointment_id"`
+	VisitorID     sql.NullInt32  `json:"visitor_id"`
+	VisitorName   string         `json:"visitor_name"`
+	VisitorPhone  string         `json:"visitor_phone"`
+	HostName      string         `json:"host_name"`
+	Location      sql.NullString `json:"location"`
+	CheckInTime   time.Time      `json:"check_in_time"`
+	AccountedFor  bool           `json:"accounted_for"`
+	AccountedBy   sql.NullInt32  `json:"accounted_by"`
+	AccountedAt   sql.NullTime   `json:"accounted_at"`
+}
+
+type Kiosk struct {
+	ID         int32          `json:"id"`
+	Name       string         `json:"name"`
+	Gate       sql.NullString `json:"gate"`
+	ApiKeyHash string         `json:"api_key_hash"`
+	IsActive   bool           `json:"is_active"`
+	LastSyncAt sql.NullTime   `json:"last_sync_at"`
+	CreatedBy  sql.NullInt32  `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type MessageTemplate struct {
+	Locale    string        `json:"locale"`
+	Key       string        `json:"key"`
+	Subject   string        `json:"subject"`
+	Body      string        `json:"body"`
+	UpdatedBy sql.NullInt32 `json:"updated_by"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type Notification struct {
+	ID             int32           `json:"id"`
+	Event          string          `json:"event"`
+	AppointmentID  sql.NullInt32   `json:"appointment_id"`
+	UserID         sql.NullInt32   `json:"user_id"`
+	Channel        string          `json:"channel"`
+	Recipient      string          `json:"recipient"`
+	Subject        string          `json:"subject"`
+	Body           string          `json:"body"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	MaxAttempts    int32           `json:"max_attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      sql.NullString  `json:"last_error"`
+	SentAt         sql.NullTime    `json:"sent_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	SubscriptionID sql.NullInt32   `json:"subscription_id"`
+}
+
 type Otp struct {
 	PhoneNumber sql.NullString `json:"phone_number"`
 	OtpCode     sql.NullString `json:"otp_code"`
@@ -49,6 +201,21 @@ type Otp struct {
 	ExpiresAt   sql.NullTime   `json:"expires_at"`
 }
 
+type Overstay struct {
+	ID              int32         `json:"id"`
+	AppointmentID   int32         `json:"appointment_id"`
+	ScheduledEnd    time.Time     `json:"scheduled_end"`
+	DetectedAt      time.Time     `json:"detected_at"`
+	ResolvedAt      sql.NullTime  `json:"resolved_at"`
+	OverstayMinutes sql.NullInt32 `json:"overstay_minutes"`
+}
+
+type StreamTicket struct {
+	TicketHash string    `json:"ticket_hash"`
+	UserID     int32     `json:"user_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
 type User struct {
 	ID                  int32          `json:"id"`
 	PhoneNumber         string         `json:"phone_number"`
@@ -58,4 +225,55 @@ type User struct {
 	CreatedAt           sql.NullTime   `json:"created_at"`
 	AppointmentsHosted  sql.NullInt32  `json:"appointments_hosted"`
 	AppointmentsVisited sql.NullInt32  `json:"appointments_visited"`
+	Department          sql.NullString `json:"department"`
+	IsActive            bool           `json:"is_active"`
+	NotificationChannel string         `json:"notification_channel"`
+	Email               sql.NullString `json:"email"`
+	PreferredLocale     sql.NullString `json:"preferred_locale"`
+}
+
+type VisitorPhoto struct {
+	ID            int32         `json:"id"`
+	UserID        int32         `json:"user_id"`
+	AppointmentID int32         `json:"appointment_id"`
+	StorageKey    string        `json:"storage_key"`
+	ContentType   string        `json:"content_type"`
+	SizeBytes     int32         `json:"size_bytes"`
+	CapturedBy    sql.NullInt32 `json:"captured_by"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+type WaitlistEntry struct {
+	ID        int32          `json:"id"`
+	VisitorID int32          `json:"visitor_id"`
+	HostID    int32          `json:"host_id"`
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	Status    sql.NullString `json:"status"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+type WaitlistOffer struct {
+	ID              int32          `json:"id"`
+	WaitlistEntryID int32          `json:"waitlist_entry_id"`
+	AppointmentDate time.Time      `json:"appointment_date"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	ClaimToken      string         `json:"claim_token"`
+	Status          sql.NullString `json:"status"`
+	AppointmentID   sql.NullInt32  `json:"appointment_id"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID          int32         `json:"id"`
+	Url         string        `json:"url"`
+	Secret      string        `json:"secret"`
+	Events      []string      `json:"events"`
+	IsActive    bool          `json:"is_active"`
+	MaxAttempts int32         `json:"max_attempts"`
+	CreatedBy   sql.NullInt32 `json:"created_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
 }
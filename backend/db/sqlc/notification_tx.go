@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// enqueueNotifications writes notifications to the outbox inside the caller's
+// transaction. Entries without an appointment are linked to appointmentID,
+// which is how notifications for a new booking get the booking's ID.
+func enqueueNotifications(ctx context.Context, q *Queries, appointmentID int32, notifications []CreateNotificationParams) error {
+	for _, notification := range notifications {
+		if !notification.AppointmentID.Valid && appointmentID != 0 {
+			notification.AppointmentID = sql.NullInt32{Int32: appointmentID, Valid: true}
+		}
+		if _, err := q.CreateNotification(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
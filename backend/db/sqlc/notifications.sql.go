@@ -0,0 +1,377 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: notifications.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+const claimDueNotifications = `-- name: ClaimDueNotifications :many
+UPDATE notifications
+SET status = 'sending',
+    attempts = attempts + 1,
+    next_attempt_at = LOCALTIMESTAMP + make_interval(secs => $1::int)
+WHERE id IN (
+  SELECT id FROM notifications
+  WHERE status IN ('pending', 'retrying', 'sending')
+    AND next_attempt_at <= LOCALTIMESTAMP
+    AND (status <> 'sending' OR attempts < max_attempts)
+  ORDER BY next_attempt_at, id
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+type ClaimDueNotificationsParams struct {
+	LeaseSeconds int32 `json:"lease_seconds"`
+	BatchSize    int32 `json:"batch_size"`
+}
+
+// Leases a batch of due notifications to one worker. SKIP LOCKED lets several
+// workers run side by side, and a lease that runs out (e.g. the worker died
+// mid-send) makes the row due again while it has attempts left.
+func (q *Queries) ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error) {
+	rows, err := q.query(ctx, q.claimDueNotificationsStmt, claimDueNotifications, arg.LeaseSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.AppointmentID,
+			&i.UserID,
+			&i.Channel,
+			&i.Recipient,
+			&i.Subject,
+			&i.Body,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.SubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createNotification = `-- name: CreateNotification :one
+INSERT INTO notifications (
+  event, appointment_id, user_id, channel, recipient, subject, body, payload, max_attempts
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+type CreateNotificationParams struct {
+	Event         string          `json:"event"`
+	AppointmentID sql.NullInt32   `json:"appointment_id"`
+	UserID        sql.NullInt32   `json:"user_id"`
+	Channel       string          `json:"channel"`
+	Recipient     string          `json:"recipient"`
+	Subject       string          `json:"subject"`
+	Body          string          `json:"body"`
+	Payload       json.RawMessage `json:"payload"`
+	MaxAttempts   int32           `json:"max_attempts"`
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
+	row := q.queryRow(ctx, q.createNotificationStmt, createNotification,
+		arg.Event,
+		arg.AppointmentID,
+		arg.UserID,
+		arg.Channel,
+		arg.Recipient,
+		arg.Subject,
+		arg.Body,
+		arg.Payload,
+		arg.MaxAttempts,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Event,
+		&i.AppointmentID,
+		&i.UserID,
+		&i.Channel,
+		&i.Recipient,
+		&i.Subject,
+		&i.Body,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.SubscriptionID,
+	)
+	return i, err
+}
+
+const deadLetterExpiredLeases = `-- name: DeadLetterExpiredLeases :many
+UPDATE notifications
+SET status = 'dead',
+    last_error = 'lease expired before the last attempt finished'
+WHERE status = 'sending'
+  AND next_attempt_at <= LOCALTIMESTAMP
+  AND attempts >= max_attempts
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+// A lease that ran out on the last attempt (e.g. the worker died mid-send)
+// has no attempts left to retry with, so the notification is dead-lettered
+// instead of being claimed again.
+func (q *Queries) DeadLetterExpiredLeases(ctx context.Context) ([]Notification, error) {
+	rows, err := q.query(ctx, q.deadLetterExpiredLeasesStmt, deadLetterExpiredLeases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.AppointmentID,
+			&i.UserID,
+			&i.Channel,
+			&i.Recipient,
+			&i.Subject,
+			&i.Body,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.SubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInboxNotifications = `-- name: ListInboxNotifications :many
+SELECT id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id FROM notifications
+WHERE user_id = $1
+  AND channel = 'in_app'
+  AND status = 'sent'
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+
+type ListInboxNotificationsParams struct {
+	UserID sql.NullInt32 `json:"user_id"`
+	Limit  int32         `json:"limit"`
+}
+
+// The user's in-app notifications, newest first.
+func (q *Queries) ListInboxNotifications(ctx context.Context, arg ListInboxNotificationsParams) ([]Notification, error) {
+	rows, err := q.query(ctx, q.listInboxNotificationsStmt, listInboxNotifications, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.AppointmentID,
+			&i.UserID,
+			&i.Channel,
+			&i.Recipient,
+			&i.Subject,
+			&i.Body,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.SubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationsByAppointment = `-- name: ListNotificationsByAppointment :many
+SELECT id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id FROM notifications
+WHERE appointment_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListNotificationsByAppointment(ctx context.Context, appointmentID sql.NullInt32) ([]Notification, error) {
+	rows, err := q.query(ctx, q.listNotificationsByAppointmentStmt, listNotificationsByAppointment, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.AppointmentID,
+			&i.UserID,
+			&i.Channel,
+			&i.Recipient,
+			&i.Subject,
+			&i.Body,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.SubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationFailed = `-- name: MarkNotificationFailed :one
+UPDATE notifications
+SET status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'retrying' END,
+    next_attempt_at = LOCALTIMESTAMP + make_interval(secs => $1::int),
+    last_error = $2
+WHERE id = $3
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+type MarkNotificationFailedParams struct {
+	BackoffSeconds int32          `json:"backoff_seconds"`
+	LastError      sql.NullString `json:"last_error"`
+	ID             int32          `json:"id"`
+}
+
+// Schedules another attempt after the given backoff, or moves the
+// notification to the dead-letter state once it has used up its attempts.
+func (q *Queries) MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) (Notification, error) {
+	row := q.queryRow(ctx, q.markNotificationFailedStmt, markNotificationFailed, arg.BackoffSeconds, arg.LastError, arg.ID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Event,
+		&i.AppointmentID,
+		&i.UserID,
+		&i.Channel,
+		&i.Recipient,
+		&i.Subject,
+		&i.Body,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.SubscriptionID,
+	)
+	return i, err
+}
+
+const markNotificationSent = `-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status = 'sent', sent_at = now(), last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkNotificationSent(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.markNotificationSentStmt, markNotificationSent, id)
+	return err
+}
+
+const requeueNotification = `-- name: RequeueNotification :one
+UPDATE notifications
+SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE id = $1 AND status = 'dead'
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+// Gives a dead-lettered notification a fresh set of attempts.
+func (q *Queries) RequeueNotification(ctx context.Context, id int32) (Notification, error) {
+	row := q.queryRow(ctx, q.requeueNotificationStmt, requeueNotification, id)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Event,
+		&i.AppointmentID,
+		&i.UserID,
+		&i.Channel,
+		&i.Recipient,
+		&i.Subject,
+		&i.Body,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.SubscriptionID,
+	)
+	return i, err
+}
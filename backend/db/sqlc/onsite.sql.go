@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: onsite.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listOnsiteVisitors = `-- name: ListOnsiteVisitors :many
+SELECT
+  l.appointment_id,
+  l.check_in_time,
+  a.visitor_id,
+  a.host_id,
+  a.location,
+  a.end_time,
+  visitor.first_name || ' ' || visitor.last_name AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  host.first_name || ' ' || host.last_name AS host_name,
+  o.detected_at AS overstay_detected_at,
+  r.reply AS host_reply,
+  r.message AS host_reply_message,
+  r.created_at AS host_replied_at
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+LEFT JOIN overstays o ON o.appointment_id = l.appointment_id AND o.resolved_at IS NULL
+LEFT JOIN checkin_replies r ON r.appointment_id = l.appointment_id
+  AND NOT EXISTS (
+    SELECT 1 FROM checkin_replies newer
+    WHERE newer.appointment_id = r.appointment_id
+      AND (newer.created_at, newer.id) > (r.created_at, r.id)
+  )
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+ORDER BY l.check_in_time
+`
+
+type ListOnsiteVisitorsRow struct {
+	AppointmentID      int32          `json:"appointment_id"`
+	CheckInTime        sql.NullTime   `json:"check_in_time"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	Location           sql.NullString `json:"location"`
+	EndTime            time.Time      `json:"end_time"`
+	VisitorName        interface{}    `json:"visitor_name"`
+	VisitorPhone       string         `json:"visitor_phone"`
+	HostName           interface{}    `json:"host_name"`
+	OverstayDetectedAt sql.NullTime   `json:"overstay_detected_at"`
+	HostReply          sql.NullString `json:"host_reply"`
+	HostReplyMessage   sql.NullString `json:"host_reply_message"`
+	HostRepliedAt      sql.NullTime   `json:"host_replied_at"`
+}
+
+func (q *Queries) ListOnsiteVisitors(ctx context.Context) ([]ListOnsiteVisitorsRow, error) {
+	rows, err := q.query(ctx, q.listOnsiteVisitorsStmt, listOnsiteVisitors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOnsiteVisitorsRow{}
+	for rows.Next() {
+		var i ListOnsiteVisitorsRow
+		if err := rows.Scan(
+			&i.AppointmentID,
+			&i.CheckInTime,
+			&i.VisitorID,
+			&i.HostID,
+			&i.Location,
+			&i.EndTime,
+			&i.VisitorName,
+			&i.VisitorPhone,
+			&i.HostName,
+			&i.OverstayDetectedAt,
+			&i.HostReply,
+			&i.HostReplyMessage,
+			&i.HostRepliedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
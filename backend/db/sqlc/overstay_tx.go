@@ -0,0 +1,39 @@
+package db
+
+import "context"
+
+type FlagOverstaysTxParams struct {
+	GraceMinutes int32
+	// Notifications builds the alerts for one newly flagged overstay; they
+	// are queued with the flag
+	Notifications func(notice GetOverstayNoticeRow) []CreateNotificationParams
+}
+
+// FlagOverstaysTx flags every visitor still on site past their grace period
+// and queues the alerts for each new overstay, so a flag is never committed
+// without its alerts.
+func (store *SQLStore) FlagOverstaysTx(ctx context.Context, arg FlagOverstaysTxParams) ([]Overstay, error) {
+	var overstays []Overstay
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		overstays, err = q.FlagOverstays(ctx, arg.GraceMinutes)
+		if err != nil {
+			return err
+		}
+
+		for _, overstay := range overstays {
+			notice, err := q.GetOverstayNotice(ctx, overstay.ID)
+			if err != nil {
+				return err
+			}
+			if err := enqueueNotifications(ctx, q, overstay.AppointmentID, arg.Notifications(notice)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return overstays, err
+}
@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: overstays.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const flagOverstays = `-- name: FlagOverstays :many
+INSERT INTO overstays (appointment_id, scheduled_end)
+SELECT a.id, a.appointment_date + a.end_time
+FROM appointment_logs l
+JOIN appointments a ON l.appointment_id = a.id
+WHERE l.check_in_time IS NOT NULL
+  AND l.check_out_time IS NULL
+  AND a.appointment_date + a.end_time + make_interval(mins => $1::int) < LOCALTIMESTAMP
+ON CONFLICT (appointment_id) DO NOTHING
+RETURNING id, appointment_id, scheduled_end, detected_at, resolved_at, overstay_minutes
+`
+
+// Flags every visitor still checked in past their scheduled end plus the
+// grace period. Visits that are already flagged are skipped, so only new
+// overstays come back.
+func (q *Queries) FlagOverstays(ctx context.Context, graceMinutes int32) ([]Overstay, error) {
+	rows, err := q.query(ctx, q.flagOverstaysStmt, flagOverstays, graceMinutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Overstay{}
+	for rows.Next() {
+		var i Overstay
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.ScheduledEnd,
+			&i.DetectedAt,
+			&i.ResolvedAt,
+			&i.OverstayMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOverstayNotice = `-- name: GetOverstayNotice :one
+SELECT
+  o.id, o.appointment_id, o.scheduled_end, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  host.id AS host_id,
+  host.phone_number AS host_phone,
+  host.preferred_locale AS host_preferred_locale
+FROM overstays o
+JOIN appointments a ON o.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE o.id = $1
+`
+
+type GetOverstayNoticeRow struct {
+	ID                  int32          `json:"id"`
+	AppointmentID       int32          `json:"appointment_id"`
+	ScheduledEnd        time.Time      `json:"scheduled_end"`
+	Location            sql.NullString `json:"location"`
+	VisitorName         string         `json:"visitor_name"`
+	HostID              int32          `json:"host_id"`
+	HostPhone           string         `json:"host_phone"`
+	HostPreferredLocale sql.NullString `json:"host_preferred_locale"`
+}
+
+func (q *Queries) GetOverstayNotice(ctx context.Context, id int32) (GetOverstayNoticeRow, error) {
+	row := q.queryRow(ctx, q.getOverstayNoticeStmt, getOverstayNotice, id)
+	var i GetOverstayNoticeRow
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.ScheduledEnd,
+		&i.Location,
+		&i.VisitorName,
+		&i.HostID,
+		&i.HostPhone,
+		&i.HostPreferredLocale,
+	)
+	return i, err
+}
+
+const getOverstaySummary = `-- name: GetOverstaySummary :one
+SELECT
+  COUNT(*)::int AS total,
+  COUNT(*) FILTER (WHERE resolved_at IS NULL)::int AS active,
+  COALESCE(AVG(overstay_minutes), 0)::float8 AS avg_minutes,
+  COALESCE(MAX(overstay_minutes), 0)::int AS max_minutes
+FROM overstays
+WHERE scheduled_end >= $1::date
+  AND scheduled_end < $2::date + 1
+`
+
+type GetOverstaySummaryParams struct {
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+type GetOverstaySummaryRow struct {
+	Total      int32   `json:"total"`
+	Active     int32   `json:"active"`
+	AvgMinutes float64 `json:"avg_minutes"`
+	MaxMinutes int32   `json:"max_minutes"`
+}
+
+func (q *Queries) GetOverstaySummary(ctx context.Context, arg GetOverstaySummaryParams) (GetOverstaySummaryRow, error) {
+	row := q.queryRow(ctx, q.getOverstaySummaryStmt, getOverstaySummary, arg.FromDate, arg.ToDate)
+	var i GetOverstaySummaryRow
+	err := row.Scan(
+		&i.Total,
+		&i.Active,
+		&i.AvgMinutes,
+		&i.MaxMinutes,
+	)
+	return i, err
+}
+
+const listOverstays = `-- name: ListOverstays :many
+SELECT
+  o.id, o.appointment_id, o.scheduled_end, o.detected_at, o.resolved_at,
+  COALESCE(
+    o.overstay_minutes,
+    GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (LOCALTIMESTAMP - o.scheduled_end)) / 60))::int
+  )::int AS overstay_minutes,
+  a.visitor_id, a.host_id, a.location,
+  (visitor.first_name || ' ' || visitor.last_name)::text AS visitor_name,
+  visitor.phone_number AS visitor_phone,
+  (host.first_name || ' ' || host.last_name)::text AS host_name
+FROM overstays o
+JOIN appointments a ON o.appointment_id = a.id
+JOIN users visitor ON a.visitor_id = visitor.id
+JOIN users host ON a.host_id = host.id
+WHERE ($1::boolean IS NULL OR (o.resolved_at IS NULL) = $1::boolean)
+  AND o.scheduled_end >= $2::date
+  AND o.scheduled_end < $3::date + 1
+ORDER BY o.scheduled_end DESC, o.id DESC
+`
+
+type ListOverstaysParams struct {
+	Active   sql.NullBool `json:"active"`
+	FromDate time.Time    `json:"from_date"`
+	ToDate   time.Time    `json:"to_date"`
+}
+
+type ListOverstaysRow struct {
+	ID              int32          `json:"id"`
+	AppointmentID   int32          `json:"appointment_id"`
+	ScheduledEnd    time.Time      `json:"scheduled_end"`
+	DetectedAt      time.Time      `json:"detected_at"`
+	ResolvedAt      sql.NullTime   `json:"resolved_at"`
+	OverstayMinutes int32          `json:"overstay_minutes"`
+	VisitorID       int32          `json:"visitor_id"`
+	HostID          int32          `json:"host_id"`
+	Location        sql.NullString `json:"location"`
+	VisitorName     string         `json:"visitor_name"`
+	VisitorPhone    string         `json:"visitor_phone"`
+	HostName        string         `json:"host_name"`
+}
+
+func (q *Queries) ListOverstays(ctx context.Context, arg ListOverstaysParams) ([]ListOverstaysRow, error) {
+	rows, err := q.query(ctx, q.listOverstaysStmt, listOverstays, arg.Active, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOverstaysRow{}
+	for rows.Next() {
+		var i ListOverstaysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.ScheduledEnd,
+			&i.DetectedAt,
+			&i.ResolvedAt,
+			&i.OverstayMinutes,
+			&i.VisitorID,
+			&i.HostID,
+			&i.Location,
+			&i.VisitorName,
+			&i.VisitorPhone,
+			&i.HostName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveOverstays = `-- name: ResolveOverstays :many
+UPDATE overstays o
+SET resolved_at = l.check_out_time,
+    overstay_minutes = GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (l.check_out_time - o.scheduled_end)) / 60))::int
+FROM appointment_logs l
+WHERE l.appointment_id = o.appointment_id
+  AND o.resolved_at IS NULL
+  AND l.check_out_time IS NOT NULL
+RETURNING o.id, o.appointment_id, o.scheduled_end, o.detected_at, o.resolved_at, o.overstay_minutes
+`
+
+// Closes overstays whose visitor has checked out and records how long they
+// stayed past the scheduled end.
+func (q *Queries) ResolveOverstays(ctx context.Context) ([]Overstay, error) {
+	rows, err := q.query(ctx, q.resolveOverstaysStmt, resolveOverstays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Overstay{}
+	for rows.Next() {
+		var i Overstay
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.ScheduledEnd,
+			&i.DetectedAt,
+			&i.ResolvedAt,
+			&i.OverstayMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,28 +11,102 @@ import (
 )
 
 type Querier interface {
-	CancelAppointment(ctx context.Context, id int32) (Appointment, error)
+	CancelAppointment(ctx context.Context, arg CancelAppointmentParams) (Appointment, error)
+	CancelWaitlistEntry(ctx context.Context, id int32) (WaitlistEntry, error)
+	// Leases a batch of due notifications to one worker. SKIP LOCKED lets several
+	// workers run side by side, and a lease that runs out (e.g. the worker died
+	// mid-send) makes the row due again while it has attempts left.
+	ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error)
+	ClaimWaitlistOffer(ctx context.Context, arg ClaimWaitlistOfferParams) (WaitlistOffer, error)
+	CloseEvacuation(ctx context.Context, arg CloseEvacuationParams) (Evacuation, error)
+	CountOverlappingAppointments(ctx context.Context, arg CountOverlappingAppointmentsParams) (int64, error)
+	CountOverlappingBusyBlocks(ctx context.Context, arg CountOverlappingBusyBlocksParams) (int64, error)
+	// Required, active forms this visit has not signed in any version. A visitor
+	// who signed an earlier version may re-enter after a new one is published;
+	// only visits that have signed nothing yet must sign the current version.
+	CountPendingDeclarations(ctx context.Context, appointmentID int32) (int32, error)
+	CreateAccessEvent(ctx context.Context, arg CreateAccessEventParams) (AccessEvent, error)
+	// User counters and appointment_stats are kept in sync by the
+	// appointments_sync_counters trigger.
 	CreateAppointment(ctx context.Context, arg CreateAppointmentParams) (Appointment, error)
-	CreateAppointmentLog(ctx context.Context, arg CreateAppointmentLogParams) (AppointmentLog, error)
-	CreateAppointmentStats(ctx context.Context, arg CreateAppointmentStatsParams) (AppointmentStat, error)
 	CreateAvailabilitySlot(ctx context.Context, arg CreateAvailabilitySlotParams) (Availability, error)
+	CreateBusyBlock(ctx context.Context, arg CreateBusyBlockParams) error
+	// Issues the user's feed the first time; returns no row if they already have
+	// one, since its token cannot be shown again.
+	CreateCalendarFeed(ctx context.Context, arg CreateCalendarFeedParams) (CalendarFeed, error)
+	CreateCalendarImport(ctx context.Context, arg CreateCalendarImportParams) (CalendarImport, error)
+	CreateCheckInReply(ctx context.Context, arg CreateCheckInReplyParams) (CheckinReply, error)
+	CreateDeclarationForm(ctx context.Context, arg CreateDeclarationFormParams) (DeclarationForm, error)
+	// Versions are numbered per form; the unique (form_id, version) constraint
+	// rejects a concurrent publish instead of creating two identical numbers.
+	CreateDeclarationFormVersion(ctx context.Context, arg CreateDeclarationFormVersionParams) (DeclarationFormVersion, error)
+	CreateEvacuation(ctx context.Context, startedBy sql.NullInt32) (Evacuation, error)
+	CreateKiosk(ctx context.Context, arg CreateKioskParams) (Kiosk, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
 	CreateOTP(ctx context.Context, arg CreateOTPParams) (Otp, error)
+	CreateStreamTicket(ctx context.Context, arg CreateStreamTicketParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DecrementAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error)
+	CreateVisitorPhoto(ctx context.Context, arg CreateVisitorPhotoParams) (VisitorPhoto, error)
+	CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntry, error)
+	CreateWaitlistOffer(ctx context.Context, arg CreateWaitlistOfferParams) (WaitlistOffer, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	// A lease that ran out on the last attempt (e.g. the worker died mid-send)
+	// has no attempts left to retry with, so the notification is dead-lettered
+	// instead of being claimed again.
+	DeadLetterExpiredLeases(ctx context.Context) ([]Notification, error)
+	DeleteAccessEventsByAppointment(ctx context.Context, appointmentID int32) error
 	DeleteAppointment(ctx context.Context, id int32) error
 	DeleteAppointmentLog(ctx context.Context, appointmentID int32) error
 	DeleteAppointmentStats(ctx context.Context, userID int32) error
 	DeleteAvailabilityByUser(ctx context.Context, userID int32) error
 	DeleteAvailabilitySlot(ctx context.Context, arg DeleteAvailabilitySlotParams) error
+	DeleteBusyBlocksByImport(ctx context.Context, importID int32) error
+	DeleteCalendarImport(ctx context.Context, id int32) error
 	DeleteExpiredOTPs(ctx context.Context) error
+	DeleteExpiredStreamTickets(ctx context.Context) error
+	DeleteMessageTemplate(ctx context.Context, arg DeleteMessageTemplateParams) (int64, error)
 	DeleteOTPByPhone(ctx context.Context, phoneNumber sql.NullString) error
 	DeleteUser(ctx context.Context, id int32) error
+	DeleteVisitorPhoto(ctx context.Context, id int32) error
+	DeleteWebhookSubscription(ctx context.Context, id int32) error
+	// Queues one delivery of the event for every active subscriber to it.
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
+	ExpireWaitlistOffer(ctx context.Context, id int32) (WaitlistOffer, error)
+	// Flags every visitor still checked in past their scheduled end plus the
+	// grace period. Visits that are already flagged are skipped, so only new
+	// overstays come back.
+	FlagOverstays(ctx context.Context, graceMinutes int32) ([]Overstay, error)
+	GetAccessEventByClientID(ctx context.Context, arg GetAccessEventByClientIDParams) (AccessEvent, error)
+	GetAppointmentBadge(ctx context.Context, id int32) (GetAppointmentBadgeRow, error)
 	GetAppointmentByID(ctx context.Context, id int32) (Appointment, error)
 	GetAppointmentByQRCode(ctx context.Context, qrCode sql.NullString) (GetAppointmentByQRCodeRow, error)
+	GetAppointmentForUpdate(ctx context.Context, id int32) (Appointment, error)
 	GetAppointmentLogByAppointmentID(ctx context.Context, appointmentID int32) (AppointmentLog, error)
 	GetAppointmentStatsByUserID(ctx context.Context, userID int32) (AppointmentStat, error)
 	GetAvailabilityByUser(ctx context.Context, userID int32) ([]Availability, error)
+	GetCalendarFeed(ctx context.Context, userID int32) (CalendarFeed, error)
+	GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	GetCalendarImport(ctx context.Context, id int32) (CalendarImport, error)
+	GetCalendarSummary(ctx context.Context, arg GetCalendarSummaryParams) ([]GetCalendarSummaryRow, error)
+	GetDailyVisitVolumes(ctx context.Context, arg GetDailyVisitVolumesParams) ([]GetDailyVisitVolumesRow, error)
+	GetDeclarationForm(ctx context.Context, id int32) (DeclarationForm, error)
+	GetDeclarationFormVersion(ctx context.Context, id int32) (DeclarationFormVersion, error)
+	GetEvacuation(ctx context.Context, id int32) (Evacuation, error)
+	GetKioskByKeyHash(ctx context.Context, apiKeyHash string) (Kiosk, error)
+	GetLatestAccessEvent(ctx context.Context, appointmentID int32) (AccessEvent, error)
+	// Prefers the photo taken for this visit and falls back to the visitor's most
+	// recent one from an earlier visit.
+	GetLatestVisitorPhoto(ctx context.Context, arg GetLatestVisitorPhotoParams) (VisitorPhoto, error)
+	GetLeaderboard(ctx context.Context, arg GetLeaderboardParams) ([]GetLeaderboardRow, error)
+	// Visitors who already let an offer of this very slot expire are skipped, so
+	// requeued entries do not get the same slot back.
+	GetNextWaitlistEntry(ctx context.Context, arg GetNextWaitlistEntryParams) (WaitlistEntry, error)
 	GetOTPByPhone(ctx context.Context, phoneNumber sql.NullString) (Otp, error)
+	GetOverstayNotice(ctx context.Context, id int32) (GetOverstayNoticeRow, error)
+	GetOverstaySummary(ctx context.Context, arg GetOverstaySummaryParams) (GetOverstaySummaryRow, error)
+	// The last scan for the appointment at or before the given time, used to
+	// spot a repeated scan when offline kiosks upload late.
+	GetPrecedingAccessEvent(ctx context.Context, arg GetPrecedingAccessEventParams) (AccessEvent, error)
 	GetTopPopularUsers(ctx context.Context) ([]GetTopPopularUsersRow, error)
 	GetTotalAppointmentsHosted(ctx context.Context, id int32) (sql.NullInt32, error)
 	GetTotalAppointmentsVisited(ctx context.Context, id int32) (sql.NullInt32, error)
@@ -40,18 +114,110 @@ type Querier interface {
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByPhone(ctx context.Context, phoneNumber string) (User, error)
 	GetUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]User, error)
-	IncrementAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error)
+	GetVisitDurationStats(ctx context.Context, arg GetVisitDurationStatsParams) (GetVisitDurationStatsRow, error)
+	GetVisitHeatmap(ctx context.Context, arg GetVisitHeatmapParams) ([]GetVisitHeatmapRow, error)
+	GetVisitOutcomeCounts(ctx context.Context, arg GetVisitOutcomeCountsParams) (GetVisitOutcomeCountsRow, error)
+	GetWaitlistEntry(ctx context.Context, id int32) (WaitlistEntry, error)
+	GetWaitlistOfferByToken(ctx context.Context, claimToken string) (GetWaitlistOfferByTokenRow, error)
+	GetWaitlistOfferForUpdate(ctx context.Context, claimToken string) (WaitlistOffer, error)
+	GetWebhookSubscription(ctx context.Context, id int32) (WebhookSubscription, error)
+	ListAccessEventsByAppointment(ctx context.Context, appointmentID int32) ([]AccessEvent, error)
+	ListAppointmentCountDrift(ctx context.Context, userID sql.NullInt32) ([]ListAppointmentCountDriftRow, error)
+	// The current version of every active form, with this visit's latest response
+	// to any version of it. A form signed in an earlier version counts as signed,
+	// the same rule CountPendingDeclarations applies; response_version_id tells
+	// the two apart.
+	ListAppointmentDeclarations(ctx context.Context, appointmentID int32) ([]ListAppointmentDeclarationsRow, error)
 	ListAppointmentsByDate(ctx context.Context, appointmentDate time.Time) ([]ListAppointmentsByDateRow, error)
 	ListAppointmentsByHost(ctx context.Context, hostID int32) ([]ListAppointmentsByHostRow, error)
 	ListAppointmentsByVisitor(ctx context.Context, visitorID int32) ([]ListAppointmentsByVisitorRow, error)
+	ListAppointmentsPageAsc(ctx context.Context, arg ListAppointmentsPageAscParams) ([]ListAppointmentsPageAscRow, error)
+	ListAppointmentsPageDesc(ctx context.Context, arg ListAppointmentsPageDescParams) ([]ListAppointmentsPageDescRow, error)
+	ListBusyBlocks(ctx context.Context, arg ListBusyBlocksParams) ([]BusyBlock, error)
+	ListCalendarImportsByUser(ctx context.Context, userID int32) ([]ListCalendarImportsByUserRow, error)
+	ListCheckInReplies(ctx context.Context, appointmentID int32) ([]CheckinReply, error)
+	ListDeclarationFormVersions(ctx context.Context, formID int32) ([]DeclarationFormVersion, error)
+	// Each form with its current (latest) version.
+	ListDeclarationForms(ctx context.Context, activeOnly sql.NullBool) ([]ListDeclarationFormsRow, error)
+	// Imports not tried within the sync interval, oldest first, so a failing URL
+	// waits its turn instead of being retried on every tick.
+	ListDueCalendarImports(ctx context.Context, arg ListDueCalendarImportsParams) ([]CalendarImport, error)
+	// Pending appointments starting within the lead time whose visitor has not
+	// been reminded yet.
+	ListDueReminders(ctx context.Context, arg ListDueRemindersParams) ([]Appointment, error)
+	ListEvacuationRoll(ctx context.Context, evacuationID int32) ([]EvacuationRoll, error)
+	ListEvacuations(ctx context.Context, arg ListEvacuationsParams) ([]Evacuation, error)
+	ListExpiredVisitorPhotos(ctx context.Context, arg ListExpiredVisitorPhotosParams) ([]VisitorPhoto, error)
+	ListExpiredWaitlistOffers(ctx context.Context) ([]WaitlistOffer, error)
+	ListHostAppointmentTimes(ctx context.Context, arg ListHostAppointmentTimesParams) ([]ListHostAppointmentTimesRow, error)
+	// The user's in-app notifications, newest first.
+	ListInboxNotifications(ctx context.Context, arg ListInboxNotificationsParams) ([]Notification, error)
+	// Everything a kiosk needs to validate the day's scans while offline.
+	ListKioskManifest(ctx context.Context, appointmentDate time.Time) ([]ListKioskManifestRow, error)
+	ListKiosks(ctx context.Context) ([]Kiosk, error)
+	ListLogbookPage(ctx context.Context, arg ListLogbookPageParams) ([]ListLogbookPageRow, error)
+	ListMessageTemplates(ctx context.Context) ([]MessageTemplate, error)
+	ListNotificationsByAppointment(ctx context.Context, appointmentID sql.NullInt32) ([]Notification, error)
+	ListOnsiteVisitors(ctx context.Context) ([]ListOnsiteVisitorsRow, error)
+	ListOverstays(ctx context.Context, arg ListOverstaysParams) ([]ListOverstaysRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
-	ResetAppointmentCount(ctx context.Context, userID int32) (AppointmentStat, error)
+	ListVisitorPhotosByAppointment(ctx context.Context, appointmentID int32) ([]VisitorPhoto, error)
+	// Every photo deleting the user would take with it: their own, and those of
+	// the appointments they host or visit, which are deleted with them.
+	ListVisitorPhotosByUser(ctx context.Context, userID int32) ([]VisitorPhoto, error)
+	ListWaitlistByHost(ctx context.Context, hostID int32) ([]ListWaitlistByHostRow, error)
+	ListWaitlistByVisitor(ctx context.Context, visitorID int32) ([]ListWaitlistByVisitorRow, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]Notification, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	MarkCalendarImportFailed(ctx context.Context, arg MarkCalendarImportFailedParams) error
+	MarkCalendarImportSynced(ctx context.Context, arg MarkCalendarImportSyncedParams) error
+	MarkEvacuationRollEntry(ctx context.Context, arg MarkEvacuationRollEntryParams) (EvacuationRoll, error)
+	// Schedules another attempt after the given backoff, or moves the
+	// notification to the dead-letter state once it has used up its attempts.
+	MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) (Notification, error)
+	MarkNotificationSent(ctx context.Context, id int32) error
+	// Only one replica gets the row back, so a visitor is reminded once.
+	MarkReminderSent(ctx context.Context, id int32) (Appointment, error)
+	// Tells live dashboards on every replica about a change to an appointment.
+	// Postgres only delivers it once the surrounding transaction commits.
+	NotifyLiveEvent(ctx context.Context, arg NotifyLiveEventParams) error
+	ReconcileAppointmentStats(ctx context.Context, id int32) (AppointmentStat, error)
+	ReconcileUserAppointmentCounts(ctx context.Context, id int32) error
+	// Deletes the ticket as it is used, so it works once. The role is read now
+	// rather than when the ticket was issued.
+	RedeemStreamTicket(ctx context.Context, ticketHash string) (RedeemStreamTicketRow, error)
+	// Queues a fresh copy of a past delivery, sent to the subscription's current URL.
+	RedeliverWebhook(ctx context.Context, arg RedeliverWebhookParams) (Notification, error)
+	// Rebuilds the visit summary from access_events: the first entry is the
+	// check-in, and the check-out is only set while the latest scan is an exit.
+	RefreshAppointmentLog(ctx context.Context, appointmentID int32) (AppointmentLog, error)
+	// Makes the import due, so the worker syncs it on its next tick.
+	RequestCalendarImportSync(ctx context.Context, id int32) (CalendarImport, error)
+	// Gives a dead-lettered notification a fresh set of attempts.
+	RequeueNotification(ctx context.Context, id int32) (Notification, error)
+	RequeueWaitlistEntry(ctx context.Context, id int32) error
+	RescheduleAppointment(ctx context.Context, arg RescheduleAppointmentParams) (Appointment, error)
+	// Closes overstays whose visitor has checked out and records how long they
+	// stayed past the scheduled end.
+	ResolveOverstays(ctx context.Context) ([]Overstay, error)
+	RotateCalendarFeed(ctx context.Context, arg RotateCalendarFeedParams) (CalendarFeed, error)
+	SnapshotEvacuationRoll(ctx context.Context, evacuationID int32) ([]EvacuationRoll, error)
+	TouchKioskSync(ctx context.Context, id int32) error
 	UpdateAppointmentStatus(ctx context.Context, arg UpdateAppointmentStatusParams) (Appointment, error)
 	UpdateAvailabilityStatus(ctx context.Context, arg UpdateAvailabilityStatusParams) error
-	UpdateCheckInTime(ctx context.Context, arg UpdateCheckInTimeParams) (AppointmentLog, error)
-	UpdateCheckOutTime(ctx context.Context, arg UpdateCheckOutTimeParams) (AppointmentLog, error)
+	UpdateDeclarationForm(ctx context.Context, arg UpdateDeclarationFormParams) (DeclarationForm, error)
+	UpdateKioskActive(ctx context.Context, arg UpdateKioskActiveParams) (Kiosk, error)
+	UpdateUserActive(ctx context.Context, arg UpdateUserActiveParams) (User, error)
+	UpdateUserDepartment(ctx context.Context, arg UpdateUserDepartmentParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
 	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error)
+	UpdateUserNotificationChannel(ctx context.Context, arg UpdateUserNotificationChannelParams) (User, error)
+	UpdateUserPreferredLocale(ctx context.Context, arg UpdateUserPreferredLocaleParams) (User, error)
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateWaitlistEntryStatus(ctx context.Context, arg UpdateWaitlistEntryStatusParams) (WaitlistEntry, error)
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
+	UpsertDeclarationResponse(ctx context.Context, arg UpsertDeclarationResponseParams) (DeclarationResponse, error)
+	UpsertMessageTemplate(ctx context.Context, arg UpsertMessageTemplateParams) (MessageTemplate, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -10,6 +10,26 @@ type Store interface {
 	Querier // Embeds all generated SQLC methods
 	// Add transactional methods here, like:
 	// BookAppointmentTx(ctx context.Context, arg BookAppointmentTxParams) (BookAppointmentTxResult, error)
+	OfferFreedSlotTx(ctx context.Context, arg OfferFreedSlotTxParams) (OfferFreedSlotTxResult, error)
+	ExpireWaitlistOfferTx(ctx context.Context, offerID int32) (FreedSlot, error)
+	ClaimWaitlistOfferTx(ctx context.Context, arg ClaimWaitlistOfferTxParams) (ClaimWaitlistOfferTxResult, error)
+	CreateAppointmentTx(ctx context.Context, arg CreateAppointmentTxParams) (Appointment, error)
+	CancelAppointmentTx(ctx context.Context, arg CancelAppointmentTxParams) (Appointment, error)
+	RescheduleAppointmentTx(ctx context.Context, arg RescheduleAppointmentTxParams) (Appointment, error)
+	SendReminderTx(ctx context.Context, arg SendReminderTxParams) error
+	DeleteAppointmentTx(ctx context.Context, appointmentID int32) (Appointment, error)
+	ReconcileAppointmentCountsTx(ctx context.Context, arg ReconcileAppointmentCountsTxParams) (ReconcileAppointmentCountsTxResult, error)
+	StartEvacuationTx(ctx context.Context, startedBy int32) (StartEvacuationTxResult, error)
+	RecordAccessEventTx(ctx context.Context, arg RecordAccessEventTxParams) (RecordAccessEventTxResult, error)
+	ApplyKioskScanTx(ctx context.Context, arg ApplyKioskScanTxParams) (ApplyKioskScanTxResult, error)
+	DeleteVisitLogTx(ctx context.Context, appointmentID int32) error
+	CreateDeclarationFormTx(ctx context.Context, arg CreateDeclarationFormTxParams) (DeclarationFormTxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error)
+	UpdateAppointmentStatusTx(ctx context.Context, arg UpdateAppointmentStatusTxParams) (Appointment, error)
+	SyncCalendarImportTx(ctx context.Context, arg SyncCalendarImportTxParams) error
+	FlagOverstaysTx(ctx context.Context, arg FlagOverstaysTxParams) ([]Overstay, error)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
 }
 
 type SQLStore struct {
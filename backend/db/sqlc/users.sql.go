@@ -8,15 +8,16 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-  phone_number, first_name, last_name, role
+  phone_number, first_name, last_name, role, department, email
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
 `
 
 type CreateUserParams struct {
@@ -24,6 +25,8 @@ type CreateUserParams struct {
 	FirstName   string         `json:"first_name"`
 	LastName    string         `json:"last_name"`
 	Role        sql.NullString `json:"role"`
+	Department  sql.NullString `json:"department"`
+	Email       sql.NullString `json:"email"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -32,6 +35,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.FirstName,
 		arg.LastName,
 		arg.Role,
+		arg.Department,
+		arg.Email,
 	)
 	var i User
 	err := row.Scan(
@@ -43,6 +48,11 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.AppointmentsHosted,
 		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
 	)
 	return i, err
 }
@@ -57,6 +67,81 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 	return err
 }
 
+const getLeaderboard = `-- name: GetLeaderboard :many
+SELECT
+  u.id,
+  u.first_name,
+  u.last_name,
+  u.role,
+  u.department,
+  COUNT(a.id)::int AS completed_appointments
+FROM appointments a
+JOIN users u ON u.id = CASE
+  WHEN $1::text = 'visited' THEN a.visitor_id
+  ELSE a.host_id
+END
+WHERE a.status = 'completed'
+  AND a.appointment_date >= $2::date
+  AND (NOT $3::bool OR u.role IS DISTINCT FROM 'admin')
+  AND (NOT $4::bool OR u.is_active)
+GROUP BY u.id
+ORDER BY completed_appointments DESC, u.id
+LIMIT $5
+`
+
+type GetLeaderboardParams struct {
+	RankBy          string    `json:"rank_by"`
+	Since           time.Time `json:"since"`
+	ExcludeAdmins   bool      `json:"exclude_admins"`
+	ExcludeInactive bool      `json:"exclude_inactive"`
+	MaxResults      int32     `json:"max_results"`
+}
+
+type GetLeaderboardRow struct {
+	ID                    int32          `json:"id"`
+	FirstName             string         `json:"first_name"`
+	LastName              string         `json:"last_name"`
+	Role                  sql.NullString `json:"role"`
+	Department            sql.NullString `json:"department"`
+	CompletedAppointments int32          `json:"completed_appointments"`
+}
+
+func (q *Queries) GetLeaderboard(ctx context.Context, arg GetLeaderboardParams) ([]GetLeaderboardRow, error) {
+	rows, err := q.query(ctx, q.getLeaderboardStmt, getLeaderboard,
+		arg.RankBy,
+		arg.Since,
+		arg.ExcludeAdmins,
+		arg.ExcludeInactive,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetLeaderboardRow{}
+	for rows.Next() {
+		var i GetLeaderboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Role,
+			&i.Department,
+			&i.CompletedAppointments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTopPopularUsers = `-- name: GetTopPopularUsers :many
 SELECT 
   id, 
@@ -142,7 +227,7 @@ func (q *Queries) GetTotalAppointmentsVisited(ctx context.Context, id int32) (sq
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited FROM users
+SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale FROM users
 WHERE id = $1
 `
 
@@ -158,12 +243,17 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.CreatedAt,
 		&i.AppointmentsHosted,
 		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
 	)
 	return i, err
 }
 
 const getUserByPhone = `-- name: GetUserByPhone :one
-SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited FROM users
+SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale FROM users
 WHERE phone_number = $1
 `
 
@@ -179,12 +269,17 @@ func (q *Queries) GetUserByPhone(ctx context.Context, phoneNumber string) (User,
 		&i.CreatedAt,
 		&i.AppointmentsHosted,
 		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
 	)
 	return i, err
 }
 
 const getUsersByName = `-- name: GetUsersByName :many
-SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited FROM users
+SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale FROM users
 WHERE LOWER(first_name || ' ' || last_name) LIKE LOWER($1 || '%')
 ORDER BY created_at DESC
 `
@@ -207,6 +302,11 @@ func (q *Queries) GetUsersByName(ctx context.Context, dollar_1 sql.NullString) (
 			&i.CreatedAt,
 			&i.AppointmentsHosted,
 			&i.AppointmentsVisited,
+			&i.Department,
+			&i.IsActive,
+			&i.NotificationChannel,
+			&i.Email,
+			&i.PreferredLocale,
 		); err != nil {
 			return nil, err
 		}
@@ -222,7 +322,7 @@ func (q *Queries) GetUsersByName(ctx context.Context, dollar_1 sql.NullString) (
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited FROM users
+SELECT id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale FROM users
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -250,6 +350,11 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.CreatedAt,
 			&i.AppointmentsHosted,
 			&i.AppointmentsVisited,
+			&i.Department,
+			&i.IsActive,
+			&i.NotificationChannel,
+			&i.Email,
+			&i.PreferredLocale,
 		); err != nil {
 			return nil, err
 		}
@@ -264,12 +369,111 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const updateUserActive = `-- name: UpdateUserActive :one
+UPDATE users
+SET is_active = $2
+WHERE id = $1
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
+`
+
+type UpdateUserActiveParams struct {
+	ID       int32 `json:"id"`
+	IsActive bool  `json:"is_active"`
+}
+
+func (q *Queries) UpdateUserActive(ctx context.Context, arg UpdateUserActiveParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserActiveStmt, updateUserActive, arg.ID, arg.IsActive)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.PhoneNumber,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.CreatedAt,
+		&i.AppointmentsHosted,
+		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
+	)
+	return i, err
+}
+
+const updateUserDepartment = `-- name: UpdateUserDepartment :one
+UPDATE users
+SET department = $2
+WHERE id = $1
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
+`
+
+type UpdateUserDepartmentParams struct {
+	ID         int32          `json:"id"`
+	Department sql.NullString `json:"department"`
+}
+
+func (q *Queries) UpdateUserDepartment(ctx context.Context, arg UpdateUserDepartmentParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserDepartmentStmt, updateUserDepartment, arg.ID, arg.Department)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.PhoneNumber,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.CreatedAt,
+		&i.AppointmentsHosted,
+		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2
+WHERE id = $1
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
+`
+
+type UpdateUserEmailParams struct {
+	ID    int32          `json:"id"`
+	Email sql.NullString `json:"email"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserEmailStmt, updateUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.PhoneNumber,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.CreatedAt,
+		&i.AppointmentsHosted,
+		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
+	)
+	return i, err
+}
+
 const updateUserName = `-- name: UpdateUserName :one
 UPDATE users
 SET first_name = $2,
     last_name = $3
 WHERE id = $1
-RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
 `
 
 type UpdateUserNameParams struct {
@@ -290,6 +494,77 @@ func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams)
 		&i.CreatedAt,
 		&i.AppointmentsHosted,
 		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
+	)
+	return i, err
+}
+
+const updateUserNotificationChannel = `-- name: UpdateUserNotificationChannel :one
+UPDATE users
+SET notification_channel = $2
+WHERE id = $1
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
+`
+
+type UpdateUserNotificationChannelParams struct {
+	ID                  int32  `json:"id"`
+	NotificationChannel string `json:"notification_channel"`
+}
+
+func (q *Queries) UpdateUserNotificationChannel(ctx context.Context, arg UpdateUserNotificationChannelParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserNotificationChannelStmt, updateUserNotificationChannel, arg.ID, arg.NotificationChannel)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.PhoneNumber,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.CreatedAt,
+		&i.AppointmentsHosted,
+		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
+	)
+	return i, err
+}
+
+const updateUserPreferredLocale = `-- name: UpdateUserPreferredLocale :one
+UPDATE users
+SET preferred_locale = $2
+WHERE id = $1
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
+`
+
+type UpdateUserPreferredLocaleParams struct {
+	ID              int32          `json:"id"`
+	PreferredLocale sql.NullString `json:"preferred_locale"`
+}
+
+func (q *Queries) UpdateUserPreferredLocale(ctx context.Context, arg UpdateUserPreferredLocaleParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserPreferredLocaleStmt, updateUserPreferredLocale, arg.ID, arg.PreferredLocale)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.PhoneNumber,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.CreatedAt,
+		&i.AppointmentsHosted,
+		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
 	)
 	return i, err
 }
@@ -298,7 +573,7 @@ const updateUserRole = `-- name: UpdateUserRole :one
 UPDATE users
 SET role = $2
 WHERE id = $1
-RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited
+RETURNING id, phone_number, first_name, last_name, role, created_at, appointments_hosted, appointments_visited, department, is_active, notification_channel, email, preferred_locale
 `
 
 type UpdateUserRoleParams struct {
@@ -318,6 +593,11 @@ func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams)
 		&i.CreatedAt,
 		&i.AppointmentsHosted,
 		&i.AppointmentsVisited,
+		&i.Department,
+		&i.IsActive,
+		&i.NotificationChannel,
+		&i.Email,
+		&i.PreferredLocale,
 	)
 	return i, err
 }
@@ -0,0 +1,219 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: visitor_photos.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createVisitorPhoto = `-- name: CreateVisitorPhoto :one
+INSERT INTO visitor_photos (
+  user_id, appointment_id, storage_key, content_type, size_bytes, captured_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, user_id, appointment_id, storage_key, content_type, size_bytes, captured_by, created_at
+`
+
+type CreateVisitorPhotoParams struct {
+	UserID        int32         `json:"user_id"`
+	AppointmentID int32         `json:"appointment_id"`
+	StorageKey    string        `json:"storage_key"`
+	ContentType   string        `json:"content_type"`
+	SizeBytes     int32         `json:"size_bytes"`
+	CapturedBy    sql.NullInt32 `json:"captured_by"`
+}
+
+func (q *Queries) CreateVisitorPhoto(ctx context.Context, arg CreateVisitorPhotoParams) (VisitorPhoto, error) {
+	row := q.queryRow(ctx, q.createVisitorPhotoStmt, createVisitorPhoto,
+		arg.UserID,
+		arg.AppointmentID,
+		arg.StorageKey,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.CapturedBy,
+	)
+	var i VisitorPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AppointmentID,
+		&i.StorageKey,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.CapturedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteVisitorPhoto = `-- name: DeleteVisitorPhoto :exec
+DELETE FROM visitor_photos
+WHERE id = $1
+`
+
+func (q *Queries) DeleteVisitorPhoto(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.deleteVisitorPhotoStmt, deleteVisitorPhoto, id)
+	return err
+}
+
+const getLatestVisitorPhoto = `-- name: GetLatestVisitorPhoto :one
+SELECT id, user_id, appointment_id, storage_key, content_type, size_bytes, captured_by, created_at FROM visitor_photos
+WHERE user_id = $1
+ORDER BY (appointment_id = $2) DESC, created_at DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestVisitorPhotoParams struct {
+	UserID        int32 `json:"user_id"`
+	AppointmentID int32 `json:"appointment_id"`
+}
+
+// Prefers the photo taken for this visit and falls back to the visitor's most
+// recent one from an earlier visit.
+func (q *Queries) GetLatestVisitorPhoto(ctx context.Context, arg GetLatestVisitorPhotoParams) (VisitorPhoto, error) {
+	row := q.queryRow(ctx, q.getLatestVisitorPhotoStmt, getLatestVisitorPhoto, arg.UserID, arg.AppointmentID)
+	var i VisitorPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AppointmentID,
+		&i.StorageKey,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.CapturedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listExpiredVisitorPhotos = `-- name: ListExpiredVisitorPhotos :many
+SELECT id, user_id, appointment_id, storage_key, content_type, size_bytes, captured_by, created_at FROM visitor_photos
+WHERE created_at < $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListExpiredVisitorPhotosParams struct {
+	CapturedBefore time.Time `json:"captured_before"`
+	BatchSize      int32     `json:"batch_size"`
+}
+
+func (q *Queries) ListExpiredVisitorPhotos(ctx context.Context, arg ListExpiredVisitorPhotosParams) ([]VisitorPhoto, error) {
+	rows, err := q.query(ctx, q.listExpiredVisitorPhotosStmt, listExpiredVisitorPhotos, arg.CapturedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VisitorPhoto{}
+	for rows.Next() {
+		var i VisitorPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AppointmentID,
+			&i.StorageKey,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.CapturedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisitorPhotosByAppointment = `-- name: ListVisitorPhotosByAppointment :many
+SELECT id, user_id, appointment_id, storage_key, content_type, size_bytes, captured_by, created_at FROM visitor_photos
+WHERE appointment_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListVisitorPhotosByAppointment(ctx context.Context, appointmentID int32) ([]VisitorPhoto, error) {
+	rows, err := q.query(ctx, q.listVisitorPhotosByAppointmentStmt, listVisitorPhotosByAppointment, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VisitorPhoto{}
+	for rows.Next() {
+		var i VisitorPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AppointmentID,
+			&i.StorageKey,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.CapturedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisitorPhotosByUser = `-- name: ListVisitorPhotosByUser :many
+SELECT id, user_id, appointment_id, storage_key, content_type, size_bytes, captured_by, created_at FROM visitor_photos
+WHERE user_id = $1
+   OR appointment_id IN (
+     SELECT id FROM appointments
+     WHERE visitor_id = $1 OR host_id = $1
+   )
+ORDER BY id
+`
+
+// Every photo deleting the user would take with it: their own, and those of
+// the appointments they host or visit, which are deleted with them.
+func (q *Queries) ListVisitorPhotosByUser(ctx context.Context, userID int32) ([]VisitorPhoto, error) {
+	rows, err := q.query(ctx, q.listVisitorPhotosByUserStmt, listVisitorPhotosByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VisitorPhoto{}
+	for rows.Next() {
+		var i VisitorPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AppointmentID,
+			&i.StorageKey,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.CapturedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,490 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: waitlist.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const cancelWaitlistEntry = `-- name: CancelWaitlistEntry :one
+UPDATE waitlist_entries
+SET status = 'cancelled'
+WHERE id = $1
+  AND status IN ('waiting', 'offered')
+RETURNING id, visitor_id, host_id, start_date, end_date, status, created_at
+`
+
+func (q *Queries) CancelWaitlistEntry(ctx context.Context, id int32) (WaitlistEntry, error) {
+	row := q.queryRow(ctx, q.cancelWaitlistEntryStmt, cancelWaitlistEntry, id)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const claimWaitlistOffer = `-- name: ClaimWaitlistOffer :one
+UPDATE waitlist_offers
+SET status = 'claimed',
+    appointment_id = $2
+WHERE id = $1
+RETURNING id, waitlist_entry_id, appointment_date, start_time, end_time, claim_token, status, appointment_id, expires_at, created_at
+`
+
+type ClaimWaitlistOfferParams struct {
+	ID            int32         `json:"id"`
+	AppointmentID sql.NullInt32 `json:"appointment_id"`
+}
+
+func (q *Queries) ClaimWaitlistOffer(ctx context.Context, arg ClaimWaitlistOfferParams) (WaitlistOffer, error) {
+	row := q.queryRow(ctx, q.claimWaitlistOfferStmt, claimWaitlistOffer, arg.ID, arg.AppointmentID)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.WaitlistEntryID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.ClaimToken,
+		&i.Status,
+		&i.AppointmentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWaitlistEntry = `-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (
+  visitor_id, host_id, start_date, end_date
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, visitor_id, host_id, start_date, end_date, status, created_at
+`
+
+type CreateWaitlistEntryParams struct {
+	VisitorID int32     `json:"visitor_id"`
+	HostID    int32     `json:"host_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+func (q *Queries) CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntry, error) {
+	row := q.queryRow(ctx, q.createWaitlistEntryStmt, createWaitlistEntry,
+		arg.VisitorID,
+		arg.HostID,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWaitlistOffer = `-- name: CreateWaitlistOffer :one
+INSERT INTO waitlist_offers (
+  waitlist_entry_id, appointment_date, start_time, end_time, claim_token, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, waitlist_entry_id, appointment_date, start_time, end_time, claim_token, status, appointment_id, expires_at, created_at
+`
+
+type CreateWaitlistOfferParams struct {
+	WaitlistEntryID int32     `json:"waitlist_entry_id"`
+	AppointmentDate time.Time `json:"appointment_date"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	ClaimToken      string    `json:"claim_token"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateWaitlistOffer(ctx context.Context, arg CreateWaitlistOfferParams) (WaitlistOffer, error) {
+	row := q.queryRow(ctx, q.createWaitlistOfferStmt, createWaitlistOffer,
+		arg.WaitlistEntryID,
+		arg.AppointmentDate,
+		arg.StartTime,
+		arg.EndTime,
+		arg.ClaimToken,
+		arg.ExpiresAt,
+	)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.WaitlistEntryID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.ClaimToken,
+		&i.Status,
+		&i.AppointmentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const expireWaitlistOffer = `-- name: ExpireWaitlistOffer :one
+UPDATE waitlist_offers
+SET status = 'expired'
+WHERE id = $1
+  AND status = 'pending'
+RETURNING id, waitlist_entry_id, appointment_date, start_time, end_time, claim_token, status, appointment_id, expires_at, created_at
+`
+
+func (q *Queries) ExpireWaitlistOffer(ctx context.Context, id int32) (WaitlistOffer, error) {
+	row := q.queryRow(ctx, q.expireWaitlistOfferStmt, expireWaitlistOffer, id)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.WaitlistEntryID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.ClaimToken,
+		&i.Status,
+		&i.AppointmentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getNextWaitlistEntry = `-- name: GetNextWaitlistEntry :one
+SELECT id, visitor_id, host_id, start_date, end_date, status, created_at FROM waitlist_entries
+WHERE host_id = $1
+  AND status = 'waiting'
+  AND $2::date BETWEEN start_date AND end_date
+  AND NOT EXISTS (
+    SELECT 1 FROM waitlist_offers o
+    WHERE o.waitlist_entry_id = waitlist_entries.id
+      AND o.appointment_date = $2::date
+      AND o.start_time = $3::time
+  )
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+type GetNextWaitlistEntryParams struct {
+	HostID    int32     `json:"host_id"`
+	SlotDate  time.Time `json:"slot_date"`
+	SlotStart time.Time `json:"slot_start"`
+}
+
+// Visitors who already let an offer of this very slot expire are skipped, so
+// requeued entries do not get the same slot back.
+func (q *Queries) GetNextWaitlistEntry(ctx context.Context, arg GetNextWaitlistEntryParams) (WaitlistEntry, error) {
+	row := q.queryRow(ctx, q.getNextWaitlistEntryStmt, getNextWaitlistEntry, arg.HostID, arg.SlotDate, arg.SlotStart)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWaitlistEntry = `-- name: GetWaitlistEntry :one
+SELECT id, visitor_id, host_id, start_date, end_date, status, created_at FROM waitlist_entries
+WHERE id = $1
+`
+
+func (q *Queries) GetWaitlistEntry(ctx context.Context, id int32) (WaitlistEntry, error) {
+	row := q.queryRow(ctx, q.getWaitlistEntryStmt, getWaitlistEntry, id)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWaitlistOfferByToken = `-- name: GetWaitlistOfferByToken :one
+SELECT 
+  o.id, o.waitlist_entry_id, o.appointment_date, o.start_time, o.end_time, o.claim_token, o.status, o.appointment_id, o.expires_at, o.created_at,
+  w.visitor_id,
+  w.host_id,
+  host.first_name || ' ' || host.last_name AS host_name
+FROM waitlist_offers o
+JOIN waitlist_entries w ON o.waitlist_entry_id = w.id
+JOIN users host ON w.host_id = host.id
+WHERE o.claim_token = $1
+`
+
+type GetWaitlistOfferByTokenRow struct {
+	ID              int32          `json:"id"`
+	WaitlistEntryID int32          `json:"waitlist_entry_id"`
+	AppointmentDate time.Time      `json:"appointment_date"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	ClaimToken      string         `json:"claim_token"`
+	Status          sql.NullString `json:"status"`
+	AppointmentID   sql.NullInt32  `json:"appointment_id"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	VisitorID       int32          `json:"visitor_id"`
+	HostID          int32          `json:"host_id"`
+	HostName        interface{}    `json:"host_name"`
+}
+
+func (q *Queries) GetWaitlistOfferByToken(ctx context.Context, claimToken string) (GetWaitlistOfferByTokenRow, error) {
+	row := q.queryRow(ctx, q.getWaitlistOfferByTokenStmt, getWaitlistOfferByToken, claimToken)
+	var i GetWaitlistOfferByTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.WaitlistEntryID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.ClaimToken,
+		&i.Status,
+		&i.AppointmentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.VisitorID,
+		&i.HostID,
+		&i.HostName,
+	)
+	return i, err
+}
+
+const getWaitlistOfferForUpdate = `-- name: GetWaitlistOfferForUpdate :one
+SELECT id, waitlist_entry_id, appointment_date, start_time, end_time, claim_token, status, appointment_id, expires_at, created_at FROM waitlist_offers
+WHERE claim_token = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetWaitlistOfferForUpdate(ctx context.Context, claimToken string) (WaitlistOffer, error) {
+	row := q.queryRow(ctx, q.getWaitlistOfferForUpdateStmt, getWaitlistOfferForUpdate, claimToken)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.WaitlistEntryID,
+		&i.AppointmentDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.ClaimToken,
+		&i.Status,
+		&i.AppointmentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listExpiredWaitlistOffers = `-- name: ListExpiredWaitlistOffers :many
+SELECT id, waitlist_entry_id, appointment_date, start_time, end_time, claim_token, status, appointment_id, expires_at, created_at FROM waitlist_offers
+WHERE status = 'pending'
+  AND expires_at < NOW()
+ORDER BY expires_at
+`
+
+func (q *Queries) ListExpiredWaitlistOffers(ctx context.Context) ([]WaitlistOffer, error) {
+	rows, err := q.query(ctx, q.listExpiredWaitlistOffersStmt, listExpiredWaitlistOffers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WaitlistOffer{}
+	for rows.Next() {
+		var i WaitlistOffer
+		if err := rows.Scan(
+			&i.ID,
+			&i.WaitlistEntryID,
+			&i.AppointmentDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.ClaimToken,
+			&i.Status,
+			&i.AppointmentID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitlistByHost = `-- name: ListWaitlistByHost :many
+SELECT 
+  w.id, w.visitor_id, w.host_id, w.start_date, w.end_date, w.status, w.created_at,
+  u.first_name || ' ' || u.last_name AS visitor_name
+FROM waitlist_entries w
+JOIN users u ON w.visitor_id = u.id
+WHERE w.host_id = $1
+  AND w.status IN ('waiting', 'offered')
+ORDER BY w.created_at, w.id
+`
+
+type ListWaitlistByHostRow struct {
+	ID          int32          `json:"id"`
+	VisitorID   int32          `json:"visitor_id"`
+	HostID      int32          `json:"host_id"`
+	StartDate   time.Time      `json:"start_date"`
+	EndDate     time.Time      `json:"end_date"`
+	Status      sql.NullString `json:"status"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	VisitorName interface{}    `json:"visitor_name"`
+}
+
+func (q *Queries) ListWaitlistByHost(ctx context.Context, hostID int32) ([]ListWaitlistByHostRow, error) {
+	rows, err := q.query(ctx, q.listWaitlistByHostStmt, listWaitlistByHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWaitlistByHostRow{}
+	for rows.Next() {
+		var i ListWaitlistByHostRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitorID,
+			&i.HostID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.VisitorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitlistByVisitor = `-- name: ListWaitlistByVisitor :many
+SELECT 
+  w.id, w.visitor_id, w.host_id, w.start_date, w.end_date, w.status, w.created_at,
+  u.first_name || ' ' || u.last_name AS host_name
+FROM waitlist_entries w
+JOIN users u ON w.host_id = u.id
+WHERE w.visitor_id = $1
+ORDER BY w.created_at DESC
+`
+
+type ListWaitlistByVisitorRow struct {
+	ID        int32          `json:"id"`
+	VisitorID int32          `json:"visitor_id"`
+	HostID    int32          `json:"host_id"`
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	Status    sql.NullString `json:"status"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	HostName  interface{}    `json:"host_name"`
+}
+
+func (q *Queries) ListWaitlistByVisitor(ctx context.Context, visitorID int32) ([]ListWaitlistByVisitorRow, error) {
+	rows, err := q.query(ctx, q.listWaitlistByVisitorStmt, listWaitlistByVisitor, visitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWaitlistByVisitorRow{}
+	for rows.Next() {
+		var i ListWaitlistByVisitorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitorID,
+			&i.HostID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.HostName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const requeueWaitlistEntry = `-- name: RequeueWaitlistEntry :exec
+UPDATE waitlist_entries
+SET status = 'waiting'
+WHERE id = $1
+  AND status = 'offered'
+`
+
+func (q *Queries) RequeueWaitlistEntry(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.requeueWaitlistEntryStmt, requeueWaitlistEntry, id)
+	return err
+}
+
+const updateWaitlistEntryStatus = `-- name: UpdateWaitlistEntryStatus :one
+UPDATE waitlist_entries
+SET status = $2
+WHERE id = $1
+RETURNING id, visitor_id, host_id, start_date, end_date, status, created_at
+`
+
+type UpdateWaitlistEntryStatusParams struct {
+	ID     int32          `json:"id"`
+	Status sql.NullString `json:"status"`
+}
+
+func (q *Queries) UpdateWaitlistEntryStatus(ctx context.Context, arg UpdateWaitlistEntryStatusParams) (WaitlistEntry, error) {
+	row := q.queryRow(ctx, q.updateWaitlistEntryStatusStmt, updateWaitlistEntryStatus, arg.ID, arg.Status)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.HostID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return i, err
+}
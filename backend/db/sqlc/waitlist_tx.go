@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOfferUnavailable is returned when a waitlist offer has already been
+// claimed, has expired, or its slot has been taken in the meantime.
+var ErrOfferUnavailable = errors.New("waitlist offer is no longer available")
+
+// FreedSlot describes a host's time slot that has become free again.
+type FreedSlot struct {
+	HostID          int32     `json:"host_id"`
+	AppointmentDate time.Time `json:"appointment_date"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+}
+
+type OfferFreedSlotTxParams struct {
+	Slot       FreedSlot
+	ClaimToken string
+	ExpiresAt  time.Time
+	// Notifications builds the claim link messages once the visitor it goes
+	// to is known; they are queued with the offer
+	Notifications func(visitor User) []CreateNotificationParams
+}
+
+type OfferFreedSlotTxResult struct {
+	Entry WaitlistEntry `json:"entry"`
+	Offer WaitlistOffer `json:"offer"`
+}
+
+// OfferFreedSlotTx offers a freed slot to the next waiting visitor for the host
+// and queues their claim link. It returns sql.ErrNoRows when nobody is
+// waiting for that day.
+func (store *SQLStore) OfferFreedSlotTx(ctx context.Context, arg OfferFreedSlotTxParams) (OfferFreedSlotTxResult, error) {
+	var result OfferFreedSlotTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Entry, err = q.GetNextWaitlistEntry(ctx, GetNextWaitlistEntryParams{
+			HostID:    arg.Slot.HostID,
+			SlotDate:  arg.Slot.AppointmentDate,
+			SlotStart: arg.Slot.StartTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Offer, err = q.CreateWaitlistOffer(ctx, CreateWaitlistOfferParams{
+			WaitlistEntryID: result.Entry.ID,
+			AppointmentDate: arg.Slot.AppointmentDate,
+			StartTime:       arg.Slot.StartTime,
+			EndTime:         arg.Slot.EndTime,
+			ClaimToken:      arg.ClaimToken,
+			ExpiresAt:       arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.UpdateWaitlistEntryStatus(ctx, UpdateWaitlistEntryStatusParams{
+			ID:     result.Entry.ID,
+			Status: sql.NullString{String: "offered", Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Notifications == nil {
+			return nil
+		}
+		visitor, err := q.GetUserByID(ctx, result.Entry.VisitorID)
+		if err != nil {
+			return err
+		}
+		return enqueueNotifications(ctx, q, 0, arg.Notifications(visitor))
+	})
+
+	return result, err
+}
+
+// ExpireWaitlistOfferTx expires an unclaimed offer so the slot can be offered
+// to the next visitor. The entry goes back in line, keeping its place, for
+// later slots; an entry cancelled in the meantime stays cancelled.
+func (store *SQLStore) ExpireWaitlistOfferTx(ctx context.Context, offerID int32) (FreedSlot, error) {
+	var slot FreedSlot
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		offer, err := q.ExpireWaitlistOffer(ctx, offerID)
+		if err != nil {
+			return err
+		}
+
+		if err := q.RequeueWaitlistEntry(ctx, offer.WaitlistEntryID); err != nil {
+			return err
+		}
+
+		entry, err := q.GetWaitlistEntry(ctx, offer.WaitlistEntryID)
+		if err != nil {
+			return err
+		}
+
+		slot = FreedSlot{
+			HostID:          entry.HostID,
+			AppointmentDate: offer.AppointmentDate,
+			StartTime:       offer.StartTime,
+			EndTime:         offer.EndTime,
+		}
+		return nil
+	})
+
+	return slot, err
+}
+
+type ClaimWaitlistOfferTxParams struct {
+	ClaimToken string
+	QrCode     string
+}
+
+type ClaimWaitlistOfferTxResult struct {
+	Offer       WaitlistOffer `json:"offer"`
+	Appointment Appointment   `json:"appointment"`
+}
+
+// ClaimWaitlistOfferTx books the offered slot for the waitlisted visitor.
+func (store *SQLStore) ClaimWaitlistOfferTx(ctx context.Context, arg ClaimWaitlistOfferTxParams) (ClaimWaitlistOfferTxResult, error) {
+	var result ClaimWaitlistOfferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		offer, err := q.GetWaitlistOfferForUpdate(ctx, arg.ClaimToken)
+		if err != nil {
+			return err
+		}
+		if offer.Status.String != "pending" || time.Now().After(offer.ExpiresAt) {
+			return ErrOfferUnavailable
+		}
+
+		entry, err := q.GetWaitlistEntry(ctx, offer.WaitlistEntryID)
+		if err != nil {
+			return err
+		}
+		if entry.Status.String != "offered" {
+			return ErrOfferUnavailable
+		}
+
+		taken, err := q.CountOverlappingAppointments(ctx, CountOverlappingAppointmentsParams{
+			HostID:          entry.HostID,
+			AppointmentDate: offer.AppointmentDate,
+			StartTime:       offer.StartTime,
+			EndTime:         offer.EndTime,
+		})
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrOfferUnavailable
+		}
+
+		result.Appointment, err = q.CreateAppointment(ctx, CreateAppointmentParams{
+			VisitorID:       entry.VisitorID,
+			HostID:          entry.HostID,
+			AppointmentDate: offer.AppointmentDate,
+			StartTime:       offer.StartTime,
+			EndTime:         offer.EndTime,
+			Status:          sql.NullString{String: "pending", Valid: true},
+			QrCode:          sql.NullString{String: arg.QrCode, Valid: true},
+			PurposeCategory: sql.NullString{String: "meeting", Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create appointment: %w", err)
+		}
+		if err := publishEvent(ctx, q, WebhookAppointmentCreated, result.Appointment.ID, newAppointmentEvent(result.Appointment)); err != nil {
+			return err
+		}
+
+		result.Offer, err = q.ClaimWaitlistOffer(ctx, ClaimWaitlistOfferParams{
+			ID:            offer.ID,
+			AppointmentID: sql.NullInt32{Int32: result.Appointment.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateWaitlistEntryStatus(ctx, UpdateWaitlistEntryStatusParams{
+			ID:     entry.ID,
+			Status: sql.NullString{String: "claimed", Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
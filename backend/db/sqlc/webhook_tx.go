@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Events published to webhook subscribers
+const (
+	WebhookAppointmentCreated       = "appointment.created"
+	WebhookAppointmentCancelled     = "appointment.cancelled"
+	WebhookAppointmentRescheduled   = "appointment.rescheduled"
+	WebhookAppointmentStatusChanged = "appointment.status_changed"
+	WebhookVisitorCheckedIn         = "visitor.checked_in"
+	WebhookVisitorCheckedOut        = "visitor.checked_out"
+	WebhookUserCreated              = "user.created"
+)
+
+// WebhookEvents lists every event a subscription can ask for.
+var WebhookEvents = []string{
+	WebhookAppointmentCreated,
+	WebhookAppointmentCancelled,
+	WebhookAppointmentRescheduled,
+	WebhookAppointmentStatusChanged,
+	WebhookVisitorCheckedIn,
+	WebhookVisitorCheckedOut,
+	WebhookUserCreated,
+}
+
+// webhookPayload is the JSON body every subscriber receives.
+type webhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// publishEvent queues a delivery of the event to each active webhook
+// subscriber and, for appointment events, notifies live dashboards, all in
+// the same transaction as the change it describes.
+func publishEvent(ctx context.Context, q *Queries, event string, appointmentID int32, data any) error {
+	payload, err := json.Marshal(webhookPayload{
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.EnqueueWebhookDeliveries(ctx, EnqueueWebhookDeliveriesParams{
+		Event:         event,
+		AppointmentID: sql.NullInt32{Int32: appointmentID, Valid: appointmentID != 0},
+		Payload:       payload,
+	})
+	if err != nil || appointmentID == 0 {
+		return err
+	}
+
+	return q.NotifyLiveEvent(ctx, NotifyLiveEventParams{
+		Event:         event,
+		AppointmentID: appointmentID,
+	})
+}
+
+// userCreated is the user.created payload. Contact details stay out of it,
+// since subscribers are third-party systems.
+type userCreated struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// CreateUserTx creates a user and publishes user.created.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return publishEvent(ctx, q, WebhookUserCreated, 0, userCreated{
+			ID:   user.ID,
+			Name: user.FirstName + " " + user.LastName,
+			Role: user.Role.String,
+		})
+	})
+
+	return user, err
+}
+
+// appointmentEvent is the appointment in appointment.* payloads. It keeps the
+// shape of Appointment but leaves out the host's private notes and the QR
+// code, which opens the gate, since subscribers are third-party systems.
+type appointmentEvent struct {
+	ID                 int32          `json:"id"`
+	VisitorID          int32          `json:"visitor_id"`
+	HostID             int32          `json:"host_id"`
+	AppointmentDate    time.Time      `json:"appointment_date"`
+	StartTime          time.Time      `json:"start_time"`
+	EndTime            time.Time      `json:"end_time"`
+	Status             sql.NullString `json:"status"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	CancellationReason sql.NullString `json:"cancellation_reason"`
+	CancelledBy        sql.NullInt32  `json:"cancelled_by"`
+	CancelledAt        sql.NullTime   `json:"cancelled_at"`
+	Purpose            sql.NullString `json:"purpose"`
+	PurposeCategory    sql.NullString `json:"purpose_category"`
+	Location           sql.NullString `json:"location"`
+	VisitorNotes       sql.NullString `json:"visitor_notes"`
+}
+
+func newAppointmentEvent(appointment Appointment) appointmentEvent {
+	return appointmentEvent{
+		ID:                 appointment.ID,
+		VisitorID:          appointment.VisitorID,
+		HostID:             appointment.HostID,
+		AppointmentDate:    appointment.AppointmentDate,
+		StartTime:          appointment.StartTime,
+		EndTime:            appointment.EndTime,
+		Status:             appointment.Status,
+		CreatedAt:          appointment.CreatedAt,
+		CancellationReason: appointment.CancellationReason,
+		CancelledBy:        appointment.CancelledBy,
+		CancelledAt:        appointment.CancelledAt,
+		Purpose:            appointment.Purpose,
+		PurposeCategory:    appointment.PurposeCategory,
+		Location:           appointment.Location,
+		VisitorNotes:       appointment.VisitorNotes,
+	}
+}
+
+// appointmentStatusChange is the appointment.status_changed payload.
+type appointmentStatusChange struct {
+	Appointment    appointmentEvent `json:"appointment"`
+	PreviousStatus string           `json:"previous_status"`
+}
+
+type UpdateAppointmentStatusTxParams struct {
+	UpdateAppointmentStatusParams
+	// CheckInNotifications are queued when this moves the visit to ongoing
+	// and no gate scan has checked the visitor in yet
+	CheckInNotifications []CreateNotificationParams
+}
+
+// UpdateAppointmentStatusTx sets an appointment's status and publishes
+// appointment.status_changed when it actually changed. Like a gate scan,
+// moving a visit to ongoing is refused with ErrDeclarationsPending until the
+// required forms are done, and tells the host their visitor has arrived.
+func (store *SQLStore) UpdateAppointmentStatusTx(ctx context.Context, arg UpdateAppointmentStatusTxParams) (Appointment, error) {
+	var appointment Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetAppointmentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		checkingIn := arg.Status.String == "ongoing" && current.Status.String != "ongoing"
+		if checkingIn {
+			pending, err := q.CountPendingDeclarations(ctx, arg.ID)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return ErrDeclarationsPending
+			}
+		}
+
+		appointment, err = q.UpdateAppointmentStatus(ctx, arg.UpdateAppointmentStatusParams)
+		if err != nil {
+			return err
+		}
+		if current.Status == appointment.Status {
+			return nil
+		}
+
+		err = publishEvent(ctx, q, WebhookAppointmentStatusChanged, appointment.ID, appointmentStatusChange{
+			Appointment:    newAppointmentEvent(appointment),
+			PreviousStatus: current.Status.String,
+		})
+		if err != nil || !checkingIn {
+			return err
+		}
+
+		// A gate scan already told the host
+		visit, err := q.GetAppointmentLogByAppointmentID(ctx, arg.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if visit.CheckInTime.Valid {
+			return nil
+		}
+		return enqueueNotifications(ctx, q, appointment.ID, arg.CheckInNotifications)
+	})
+
+	return appointment, err
+}
@@ -0,0 +1,287 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: webhooks.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  url, secret, events, max_attempts, created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, url, secret, events, is_active, max_attempts, created_by, created_at, updated_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	Url         string        `json:"url"`
+	Secret      string        `json:"secret"`
+	Events      []string      `json:"events"`
+	MaxAttempts int32         `json:"max_attempts"`
+	CreatedBy   sql.NullInt32 `json:"created_by"`
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.queryRow(ctx, q.createWebhookSubscriptionStmt, createWebhookSubscription,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.MaxAttempts,
+		arg.CreatedBy,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.MaxAttempts,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, id int32) error {
+	_, err := q.exec(ctx, q.deleteWebhookSubscriptionStmt, deleteWebhookSubscription, id)
+	return err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO notifications (
+  event, appointment_id, subscription_id, channel, recipient, body, payload, max_attempts
+)
+SELECT $1, $2, s.id, 'webhook', s.url, '', $3, s.max_attempts
+FROM webhook_subscriptions s
+WHERE s.is_active
+  AND $1::text = ANY(s.events)
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	Event         string          `json:"event"`
+	AppointmentID sql.NullInt32   `json:"appointment_id"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Queues one delivery of the event for every active subscriber to it.
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.exec(ctx, q.enqueueWebhookDeliveriesStmt, enqueueWebhookDeliveries, arg.Event, arg.AppointmentID, arg.Payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getWebhookSubscription = `-- name: GetWebhookSubscription :one
+SELECT id, url, secret, events, is_active, max_attempts, created_by, created_at, updated_at FROM webhook_subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int32) (WebhookSubscription, error) {
+	row := q.queryRow(ctx, q.getWebhookSubscriptionStmt, getWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.MaxAttempts,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id FROM notifications
+WHERE subscription_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID sql.NullInt32 `json:"subscription_id"`
+	Limit          int32         `json:"limit"`
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]Notification, error) {
+	rows, err := q.query(ctx, q.listWebhookDeliveriesStmt, listWebhookDeliveries, arg.SubscriptionID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.AppointmentID,
+			&i.UserID,
+			&i.Channel,
+			&i.Recipient,
+			&i.Subject,
+			&i.Body,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.SubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT id, url, secret, events, is_active, max_attempts, created_by, created_at, updated_at FROM webhook_subscriptions
+ORDER BY id
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	rows, err := q.query(ctx, q.listWebhookSubscriptionsStmt, listWebhookSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.IsActive,
+			&i.MaxAttempts,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const redeliverWebhook = `-- name: RedeliverWebhook :one
+INSERT INTO notifications (
+  event, appointment_id, subscription_id, channel, recipient, body, payload, max_attempts
+)
+SELECT n.event, n.appointment_id, s.id, 'webhook', s.url, '', n.payload, s.max_attempts
+FROM notifications n
+JOIN webhook_subscriptions s ON s.id = n.subscription_id
+WHERE n.id = $1
+  AND n.subscription_id = $2
+RETURNING id, event, appointment_id, user_id, channel, recipient, subject, body, payload, status, attempts, max_attempts, next_attempt_at, last_error, sent_at, created_at, subscription_id
+`
+
+type RedeliverWebhookParams struct {
+	DeliveryID     int32         `json:"delivery_id"`
+	SubscriptionID sql.NullInt32 `json:"subscription_id"`
+}
+
+// Queues a fresh copy of a past delivery, sent to the subscription's current URL.
+func (q *Queries) RedeliverWebhook(ctx context.Context, arg RedeliverWebhookParams) (Notification, error) {
+	row := q.queryRow(ctx, q.redeliverWebhookStmt, redeliverWebhook, arg.DeliveryID, arg.SubscriptionID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Event,
+		&i.AppointmentID,
+		&i.UserID,
+		&i.Channel,
+		&i.Recipient,
+		&i.Subject,
+		&i.Body,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.NextAttemptAt,
+		&i.LastError,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.SubscriptionID,
+	)
+	return i, err
+}
+
+const updateWebhookSubscription = `-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = $2,
+    events = $3,
+    is_active = $4,
+    max_attempts = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, url, secret, events, is_active, max_attempts, created_by, created_at, updated_at
+`
+
+type UpdateWebhookSubscriptionParams struct {
+	ID          int32    `json:"id"`
+	Url         string   `json:"url"`
+	Events      []string `json:"events"`
+	IsActive    bool     `json:"is_active"`
+	MaxAttempts int32    `json:"max_attempts"`
+}
+
+func (q *Queries) UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.queryRow(ctx, q.updateWebhookSubscriptionStmt, updateWebhookSubscription,
+		arg.ID,
+		arg.Url,
+		pq.Array(arg.Events),
+		arg.IsActive,
+		arg.MaxAttempts,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.IsActive,
+		&i.MaxAttempts,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
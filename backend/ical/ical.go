@@ -0,0 +1,169 @@
+// Package ical writes iCalendar (RFC 5545) data for appointment invites and
+// calendar subscription feeds.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID = "-//VisiTrack//Visitor Management//EN"
+	// maxLineOctets is the longest content line allowed before folding
+	maxLineOctets = 75
+	timeFormat    = "20060102T150405Z"
+)
+
+// Methods for iTIP messages sent by email. A subscription feed has none.
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Person is an organizer or attendee. Email may be empty for attendees.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a single VEVENT.
+type Event struct {
+	UID         string
+	Sequence    int32
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Organizer   *Person
+	Attendees   []Person
+}
+
+// Calendar is a VCALENDAR holding any number of events.
+type Calendar struct {
+	Name   string // Shown by clients that subscribe to the feed
+	Method string // Empty for a feed
+	Events []Event
+}
+
+// UID returns the stable identifier of an appointment's event, so every
+// update and the feed refer to the same calendar entry.
+func UID(appointmentID int32, domain string) string {
+	return fmt.Sprintf("appointment-%d@%s", appointmentID, domain)
+}
+
+// Domain returns the host name of the server's public URL, which qualifies
+// every UID it hands out.
+func Domain(publicURL string) string {
+	parsed, err := url.Parse(publicURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "localhost"
+	}
+	return parsed.Hostname()
+}
+
+// At combines an appointment's date and its time of day in the site's time
+// zone into an instant.
+func At(date, clock time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+}
+
+// Encode renders the calendar. Times are written in UTC so no VTIMEZONE
+// component is needed.
+func (cal Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	stamp := time.Now().UTC().Format(timeFormat)
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	if cal.Method != "" {
+		writeLine(&buf, "METHOD:"+cal.Method)
+	}
+	if cal.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+event.UID)
+		writeLine(&buf, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		writeLine(&buf, "DTSTAMP:"+stamp)
+		writeLine(&buf, "DTSTART:"+event.Start.UTC().Format(timeFormat))
+		writeLine(&buf, "DTEND:"+event.End.UTC().Format(timeFormat))
+		writeLine(&buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(&buf, "LOCATION:"+escapeText(event.Location))
+		}
+		if event.Status != "" {
+			writeLine(&buf, "STATUS:"+event.Status)
+		}
+		if event.Organizer != nil && event.Organizer.Email != "" {
+			writeLine(&buf, "ORGANIZER"+personParams(*event.Organizer)+":mailto:"+event.Organizer.Email)
+		}
+		for _, attendee := range event.Attendees {
+			if attendee.Email == "" {
+				continue
+			}
+			writeLine(&buf, "ATTENDEE"+personParams(attendee)+";ROLE=REQ-PARTICIPANT:mailto:"+attendee.Email)
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func personParams(person Person) string {
+	if person.Name == "" {
+		return ""
+	}
+	// Parameter values are quoted and cannot contain quotes themselves
+	return `;CN="` + strings.ReplaceAll(person.Name, `"`, "'") + `"`
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeLine writes a content line, folding it into 75 octet pieces without
+// splitting a UTF-8 sequence.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,320 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxICSLine bounds a single unfolded content line
+const maxICSLine = 1 << 20
+
+// ErrInvalidCalendar is returned for a calendar that cannot be parsed. The
+// error only ever names a line number: calendars are private, and the error
+// is stored and shown back to the user.
+var ErrInvalidCalendar = errors.New("not a valid iCalendar file")
+
+// Busy is a time range in which a calendar's owner is busy.
+type Busy struct {
+	Start   time.Time
+	End     time.Time
+	Summary string
+}
+
+// property is one content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// vevent is what busy time calculation needs from a VEVENT.
+type vevent struct {
+	uid          string
+	summary      string
+	start        time.Time
+	end          time.Time
+	allDay       bool
+	free         bool // TRANSP:TRANSPARENT or STATUS:CANCELLED
+	rule         *rrule
+	exdates      map[int64]bool
+	recurrenceID time.Time     // Set on an override of one occurrence of a recurring event
+	duration     time.Duration // DURATION, used when there is no DTEND
+}
+
+// BusyTimes parses an iCalendar stream and returns the busy ranges that
+// overlap [from, to), sorted by start, with recurring events expanded.
+// Events marked free (TRANSP:TRANSPARENT) or cancelled are left out.
+//
+// Times without a zone, and times whose TZID is not an IANA zone name, are
+// read in loc. All-day events block the whole day in loc.
+func BusyTimes(r io.Reader, from, to time.Time, loc *time.Location) ([]Busy, error) {
+	events, err := parseEvents(r, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// An override replaces the occurrence it names, so that occurrence is
+	// skipped when the master event is expanded
+	overridden := map[string]map[int64]bool{}
+	for _, event := range events {
+		if !event.recurrenceID.IsZero() {
+			if overridden[event.uid] == nil {
+				overridden[event.uid] = map[int64]bool{}
+			}
+			overridden[event.uid][event.recurrenceID.Unix()] = true
+		}
+	}
+
+	var busy []Busy
+	for _, event := range events {
+		if event.free {
+			continue
+		}
+		if event.recurrenceID.IsZero() {
+			for unix := range overridden[event.uid] {
+				event.exdates[unix] = true
+			}
+		}
+
+		length := event.end.Sub(event.start)
+		for _, start := range event.occurrences(from, to) {
+			busy = append(busy, Busy{Start: start, End: start.Add(length), Summary: event.summary})
+		}
+	}
+
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy, nil
+}
+
+// parseEvents reads the VEVENTs of a calendar, ignoring other components and
+// anything nested in an event such as VALARM.
+func parseEvents(r io.Reader, loc *time.Location) ([]vevent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, fmt.Errorf("%w: a line is longer than %d MB", ErrInvalidCalendar, maxICSLine>>20)
+		}
+		return nil, err
+	}
+
+	var events []vevent
+	var current *vevent
+	depth := 0 // Components nested inside the current event
+	sawCalendar := false
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: content line %d is malformed", ErrInvalidCalendar, i+1)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			sawCalendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && current == nil:
+			current = &vevent{exdates: map[int64]bool{}}
+		case prop.name == "BEGIN" && current != nil:
+			depth++
+		case prop.name == "END" && current != nil && depth > 0:
+			depth--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && current != nil:
+			if err := current.finish(); err != nil {
+				return nil, fmt.Errorf("%w: the event ending on content line %d is incomplete", ErrInvalidCalendar, i+1)
+			}
+			events = append(events, *current)
+			current = nil
+		case current != nil && depth == 0:
+			if err := current.set(prop, loc); err != nil {
+				return nil, fmt.Errorf("%w: content line %d has an invalid value", ErrInvalidCalendar, i+1)
+			}
+		}
+	}
+
+	if !sawCalendar {
+		return nil, ErrInvalidCalendar
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab, onto the
+// line before them.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxICSLine)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Colons and semicolons inside quoted parameter values do not count.
+func parseProperty(line string) (property, error) {
+	colon := -1
+	quoted := false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("missing ':'")
+	}
+
+	prop := property{params: map[string]string{}, value: line[colon+1:]}
+	var parts []string
+	start := 0
+	quoted = false
+	head := line[:colon]
+	for i, c := range head {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ';' && !quoted {
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, head[start:])
+
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+func (event *vevent) set(prop property, loc *time.Location) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		event.uid = prop.value
+	case "SUMMARY":
+		event.summary = unescapeText(prop.value)
+	case "DTSTART":
+		event.start, event.allDay, err = parseTime(prop, loc)
+	case "DTEND":
+		event.end, _, err = parseTime(prop, loc)
+	case "DURATION":
+		event.duration, err = parseDuration(prop.value)
+	case "TRANSP":
+		event.free = event.free || strings.EqualFold(prop.value, "TRANSPARENT")
+	case "STATUS":
+		event.free = event.free || strings.EqualFold(prop.value, "CANCELLED")
+	case "RRULE":
+		event.rule, err = parseRule(prop.value, loc)
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			exdate, _, err := parseTime(property{params: prop.params, value: value}, loc)
+			if err != nil {
+				return err
+			}
+			event.exdates[exdate.Unix()] = true
+		}
+	case "RECURRENCE-ID":
+		event.recurrenceID, _, err = parseTime(prop, loc)
+	}
+	return err
+}
+
+// finish works out the end of the event once all its properties are read.
+func (event *vevent) finish() error {
+	if event.start.IsZero() {
+		return fmt.Errorf("missing DTSTART")
+	}
+	switch {
+	case !event.end.IsZero():
+	case event.duration > 0:
+		event.end = event.start.Add(event.duration)
+	case event.allDay:
+		event.end = event.start.AddDate(0, 0, 1)
+	default:
+		event.end = event.start
+	}
+	if !event.end.After(event.start) {
+		// Zero-length events block nothing
+		event.free = true
+	}
+	return nil
+}
+
+// parseTime reads a DATE or DATE-TIME value, in UTC, in its TZID or else in
+// loc. The flag is set for a DATE.
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if prop.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// Exporters such as Outlook use Windows zone names, which Go cannot
+		// load; those fall back to the site's zone
+		if zone, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration reads a DURATION value such as PT1H30M, P1D or P2W.
+func parseDuration(value string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(value, "+"), "P")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+		end := strings.IndexAny(rest, "WDHMS")
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		unit := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}[rest[end]]
+		if rest[end] == 'M' && !inTime {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit
+		rest = rest[end+1:]
+	}
+	return total, nil
+}
+
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(value)
+}
@@ -0,0 +1,254 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar wraps events in a VCALENDAR with CRLF line endings, as exporters
+// write them.
+func calendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Test//EN"}
+	for _, event := range events {
+		lines = append(lines, strings.Split(strings.TrimSpace(event), "\n")...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func mustBusyTimes(t *testing.T, ics string, from, to time.Time, loc *time.Location) []Busy {
+	t.Helper()
+	busy, err := BusyTimes(strings.NewReader(ics), from, to, loc)
+	if err != nil {
+		t.Fatalf("BusyTimes: %v", err)
+	}
+	return busy
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestBusyTimesSingleEvents(t *testing.T) {
+	ics := calendar(`
+BEGIN:VEVENT
+UID:late@test
+DTSTART:20260105T150000Z
+DTEND:20260105T160000Z
+SUMMARY:Review\, design
+END:VEVENT
+BEGIN:VEVENT
+UID:early@test
+DTSTART:20260105T090000Z
+DURATION:PT1H30M
+SUMMARY:Stand
+ up
+BEGIN:VALARM
+TRIGGER:-PT15M
+DTSTART:20990101T000000Z
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:free@test
+DTSTART:20260105T120000Z
+DTEND:20260105T130000Z
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled@test
+DTSTART:20260105T130000Z
+DTEND:20260105T140000Z
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:empty@test
+DTSTART:20260105T140000Z
+END:VEVENT
+BEGIN:VTODO
+DTSTART:20260105T100000Z
+END:VTODO`)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	busy := mustBusyTimes(t, ics, from, from.AddDate(0, 1, 0), time.UTC)
+
+	want := []Busy{
+		{
+			Start:   time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC),
+			Summary: "Standup",
+		},
+		{
+			Start:   time.Date(2026, 1, 5, 15, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 1, 5, 16, 0, 0, 0, time.UTC),
+			Summary: "Review, design",
+		},
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("got %d busy ranges %v, want %d", len(busy), busy, len(want))
+	}
+	for i := range want {
+		if !busy[i].Start.Equal(want[i].Start) || !busy[i].End.Equal(want[i].End) || busy[i].Summary != want[i].Summary {
+			t.Errorf("busy[%d] = %v, want %v", i, busy[i], want[i])
+		}
+	}
+}
+
+func TestBusyTimesZones(t *testing.T) {
+	site := mustLoad(t, "Asia/Kolkata")
+	ics := calendar(`
+BEGIN:VEVENT
+UID:floating@test
+DTSTART:20260105T090000
+DTEND:20260105T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:iana@test
+DTSTART;TZID=America/New_York:20260105T090000
+DTEND;TZID=America/New_York:20260105T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:windows@test
+DTSTART;TZID="Eastern Standard Time":20260106T090000
+DTEND;TZID="Eastern Standard Time":20260106T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:allday@test
+DTSTART;VALUE=DATE:20260107
+END:VEVENT`)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	busy := mustBusyTimes(t, ics, from, from.AddDate(0, 1, 0), site)
+
+	newYork := mustLoad(t, "America/New_York")
+	want := []time.Time{
+		// Floating times and unknown zones are read in the site's zone
+		time.Date(2026, 1, 5, 9, 0, 0, 0, site),
+		time.Date(2026, 1, 5, 9, 0, 0, 0, newYork),
+		time.Date(2026, 1, 6, 9, 0, 0, 0, site),
+		time.Date(2026, 1, 7, 0, 0, 0, 0, site),
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("got %d busy ranges %v, want %d", len(busy), busy, len(want))
+	}
+	for i, start := range want {
+		if !busy[i].Start.Equal(start) {
+			t.Errorf("busy[%d] starts at %v, want %v", i, busy[i].Start, start)
+		}
+	}
+
+	allDay := busy[3]
+	if wantEnd := time.Date(2026, 1, 8, 0, 0, 0, 0, site); !allDay.End.Equal(wantEnd) {
+		t.Errorf("all-day event ends at %v, want %v", allDay.End, wantEnd)
+	}
+}
+
+func TestBusyTimesWindow(t *testing.T) {
+	ics := calendar(`
+BEGIN:VEVENT
+UID:before@test
+DTSTART:20260105T080000Z
+DTEND:20260105T090000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:straddles@test
+DTSTART:20260105T093000Z
+DTEND:20260105T103000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:after@test
+DTSTART:20260105T120000Z
+DTEND:20260105T130000Z
+END:VEVENT`)
+
+	from := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	busy := mustBusyTimes(t, ics, from, to, time.UTC)
+
+	if len(busy) != 1 || !busy[0].Start.Equal(time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("got %v, want only the event overlapping the window", busy)
+	}
+}
+
+func TestBusyTimesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		line string
+	}{
+		{
+			name: "not a calendar",
+			ics:  "<html>Sign in</html>\r\n",
+		},
+		{
+			name: "malformed line",
+			ics:  "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nsecret meeting with no colon\r\n",
+			line: "content line 3 ",
+		},
+		{
+			name: "invalid value",
+			ics:  calendar("BEGIN:VEVENT", "DTSTART:tomorrow", "END:VEVENT"),
+			line: "content line 5 ",
+		},
+		{
+			name: "missing start",
+			ics:  calendar("BEGIN:VEVENT", "SUMMARY:secret", "END:VEVENT"),
+			line: "content line 6 ",
+		},
+		{
+			name: "unsupported rule",
+			ics:  calendar("BEGIN:VEVENT", "DTSTART:20260105T090000Z", "RRULE:FREQ=HOURLY", "END:VEVENT"),
+			line: "content line 6 ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := BusyTimes(strings.NewReader(tc.ics), time.Time{}, time.Now(), time.UTC)
+			if !errors.Is(err, ErrInvalidCalendar) {
+				t.Fatalf("got error %v, want ErrInvalidCalendar", err)
+			}
+			if !strings.Contains(err.Error(), tc.line) {
+				t.Errorf("error %q does not name %q", err, tc.line)
+			}
+			// The calendar's own text is never echoed back
+			if strings.Contains(err.Error(), "secret") || strings.Contains(err.Error(), "tomorrow") {
+				t.Errorf("error %q repeats calendar content", err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1D", 24 * time.Hour, true},
+		{"P2W", 14 * 24 * time.Hour, true},
+		{"+P1DT2H", 26 * time.Hour, true},
+		{"PT45S", 45 * time.Second, true},
+		{"P1M", 0, false},
+		{"1H", 0, false},
+		{"PTH", 0, false},
+	}
+
+	for _, tc := range tests {
+		got, err := parseDuration(tc.value)
+		if (err == nil) != tc.ok {
+			t.Errorf("parseDuration(%q) error = %v, want ok %v", tc.value, err, tc.ok)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRulePeriods stops expanding a rule that never produces an occurrence,
+// e.g. the 31st of February
+const maxRulePeriods = 10000
+
+// weekdayNum is a BYDAY entry such as MO, 2TU or -1FR. N is zero when every
+// such weekday in the period matches.
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+// rrule is the subset of RFC 5545 recurrence rules calendar exporters use for
+// meetings: DAILY, WEEKLY, MONTHLY and YEARLY with INTERVAL, COUNT, UNTIL,
+// BYDAY, BYMONTHDAY and BYMONTH.
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []time.Month
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRule(value string, loc *time.Location) (*rrule, error) {
+	rule := &rrule{interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(val)
+			if err == nil && rule.interval < 1 {
+				err = fmt.Errorf("INTERVAL must be positive")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			var isDate bool
+			rule.until, isDate, err = parseTime(property{value: val}, loc)
+			if isDate {
+				// A date covers the whole of that day
+				rule.until = rule.until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				day = strings.ToUpper(day)
+				weekday, ok := weekdays[day[max(0, len(day)-2):]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", day)
+				}
+				n := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					if n, err = strconv.Atoi(prefix); err != nil {
+						return nil, fmt.Errorf("invalid BYDAY %q", day)
+					}
+				}
+				rule.byDay = append(rule.byDay, weekdayNum{n: n, day: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				rule.byMonthDay = append(rule.byMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(month)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", month)
+				}
+				rule.byMonth = append(rule.byMonth, time.Month(n))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+		return rule, nil
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.freq)
+	}
+}
+
+// occurrences returns the start of every occurrence that overlaps [from, to).
+// COUNT is applied from the first occurrence, even ones before from.
+func (event *vevent) occurrences(from, to time.Time) []time.Time {
+	length := event.end.Sub(event.start)
+	overlaps := func(start time.Time) bool {
+		return start.Before(to) && start.Add(length).After(from)
+	}
+
+	if event.rule == nil {
+		if overlaps(event.start) {
+			return []time.Time{event.start}
+		}
+		return nil
+	}
+
+	var starts []time.Time
+	seen := 0
+	for period := 0; period < maxRulePeriods; period++ {
+		candidates, periodStart := event.rule.candidates(event.start, period)
+		if !periodStart.Before(to) {
+			break
+		}
+
+		for _, start := range candidates {
+			if start.Before(event.start) {
+				continue
+			}
+			if !event.rule.until.IsZero() && start.After(event.rule.until) {
+				return starts
+			}
+			if event.rule.count > 0 && seen >= event.rule.count {
+				return starts
+			}
+			seen++
+			if !start.Before(to) {
+				return starts
+			}
+			if !event.exdates[start.Unix()] && overlaps(start) {
+				starts = append(starts, start)
+			}
+		}
+	}
+	return starts
+}
+
+// candidates returns the sorted dates the rule produces in the given period
+// after dtstart, at dtstart's time of day, and when that period begins.
+func (rule *rrule) candidates(dtstart time.Time, period int) ([]time.Time, time.Time) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+	year, month, day := dtstart.Date()
+	step := period * rule.interval
+
+	var dates []time.Time
+	var periodStart time.Time
+
+	switch rule.freq {
+	case "DAILY":
+		date := at(year, month, day+step)
+		periodStart = date
+		if rule.matchesMonth(date) && rule.matchesMonthDay(date) && rule.matchesWeekday(date) {
+			dates = append(dates, date)
+		}
+
+	case "WEEKLY":
+		// Weeks start on Monday, the RFC 5545 default for WKST
+		monday := day - (int(dtstart.Weekday())+6)%7
+		periodStart = at(year, month, monday+7*step)
+		days := rule.byDay
+		if len(days) == 0 {
+			days = []weekdayNum{{day: dtstart.Weekday()}}
+		}
+		for _, weekday := range days {
+			date := periodStart.AddDate(0, 0, (int(weekday.day)+6)%7)
+			if rule.matchesMonth(date) {
+				dates = append(dates, date)
+			}
+		}
+
+	case "MONTHLY":
+		periodStart = at(year, month+time.Month(step), 1)
+		if rule.matchesMonth(periodStart) {
+			dates = rule.daysInMonth(periodStart, day, at)
+		}
+
+	case "YEARLY":
+		periodStart = at(year+step, time.January, 1)
+		months := rule.byMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+		for _, m := range months {
+			dates = append(dates, rule.daysInMonth(at(year+step, m, 1), day, at)...)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, periodStart
+}
+
+// daysInMonth expands BYMONTHDAY or BYDAY within the month of first, or
+// falls back to dtstart's day of the month. Days the month does not have,
+// such as the 31st of April, are skipped rather than rolled over.
+func (rule *rrule) daysInMonth(first time.Time, dtstartDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	var days []int
+	switch {
+	case len(rule.byMonthDay) > 0:
+		for _, n := range rule.byMonthDay {
+			if n < 0 {
+				n = last + n + 1
+			}
+			if n >= 1 && n <= last && rule.matchesWeekday(at(year, month, n)) {
+				days = append(days, n)
+			}
+		}
+
+	case len(rule.byDay) > 0:
+		for _, weekday := range rule.byDay {
+			var matches []int
+			for d := 1; d <= last; d++ {
+				if at(year, month, d).Weekday() == weekday.day {
+					matches = append(matches, d)
+				}
+			}
+			switch {
+			case weekday.n == 0:
+				days = append(days, matches...)
+			case weekday.n > 0 && weekday.n <= len(matches):
+				days = append(days, matches[weekday.n-1])
+			case weekday.n < 0 && -weekday.n <= len(matches):
+				days = append(days, matches[len(matches)+weekday.n])
+			}
+		}
+
+	case dtstartDay <= last:
+		days = append(days, dtstartDay)
+	}
+
+	dates := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		dates = append(dates, at(year, month, d))
+	}
+	return dates
+}
+
+func (rule *rrule) matchesMonth(date time.Time) bool {
+	if len(rule.byMonth) == 0 {
+		return true
+	}
+	for _, month := range rule.byMonth {
+		if date.Month() == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *rrule) matchesMonthDay(date time.Time) bool {
+	if len(rule.byMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, n := range rule.byMonthDay {
+		if n == date.Day() || last+n+1 == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday filters by BYDAY, ignoring any ordinal, as a DAILY rule or
+// BYMONTHDAY combined with BYDAY requires.
+func (rule *rrule) matchesWeekday(date time.Time) bool {
+	if len(rule.byDay) == 0 {
+		return true
+	}
+	for _, weekday := range rule.byDay {
+		if date.Weekday() == weekday.day {
+			return true
+		}
+	}
+	return false
+}
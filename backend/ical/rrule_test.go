@@ -0,0 +1,176 @@
+package ical
+
+import (
+	"testing"
+	"time"
+)
+
+// utc is a shorthand for the expected occurrence starts below.
+func utc(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestBusyTimesRecurrence(t *testing.T) {
+	// 2026-01-05 is a Monday
+	tests := []struct {
+		name  string
+		event []string
+		want  []time.Time
+	}{
+		{
+			name:  "daily until a date",
+			event: []string{"DTSTART:20260105T100000Z", "DTEND:20260105T110000Z", "RRULE:FREQ=DAILY;UNTIL=20260107"},
+			want:  []time.Time{utc(2026, 1, 5, 10), utc(2026, 1, 6, 10), utc(2026, 1, 7, 10)},
+		},
+		{
+			name:  "daily on weekdays",
+			event: []string{"DTSTART:20260108T100000Z", "DTEND:20260108T110000Z", "RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3"},
+			want:  []time.Time{utc(2026, 1, 8, 10), utc(2026, 1, 9, 10), utc(2026, 1, 12, 10)},
+		},
+		{
+			name:  "weekly by day with count",
+			event: []string{"DTSTART:20260105T100000Z", "DTEND:20260105T110000Z", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4"},
+			want:  []time.Time{utc(2026, 1, 5, 10), utc(2026, 1, 7, 10), utc(2026, 1, 12, 10), utc(2026, 1, 14, 10)},
+		},
+		{
+			name:  "fortnightly",
+			event: []string{"DTSTART:20260105T100000Z", "DTEND:20260105T110000Z", "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=3"},
+			want:  []time.Time{utc(2026, 1, 5, 10), utc(2026, 1, 19, 10), utc(2026, 2, 2, 10)},
+		},
+		{
+			name:  "monthly on the last friday",
+			event: []string{"DTSTART:20260130T100000Z", "DTEND:20260130T110000Z", "RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3"},
+			want:  []time.Time{utc(2026, 1, 30, 10), utc(2026, 2, 27, 10), utc(2026, 3, 27, 10)},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			event: []string{"DTSTART:20260131T100000Z", "DTEND:20260131T110000Z", "RRULE:FREQ=MONTHLY;COUNT=3"},
+			want:  []time.Time{utc(2026, 1, 31, 10), utc(2026, 3, 31, 10), utc(2026, 5, 31, 10)},
+		},
+		{
+			name:  "yearly by month",
+			event: []string{"DTSTART:20260115T100000Z", "DTEND:20260115T110000Z", "RRULE:FREQ=YEARLY;BYMONTH=1,7;COUNT=3"},
+			want:  []time.Time{utc(2026, 1, 15, 10), utc(2026, 7, 15, 10), utc(2027, 1, 15, 10)},
+		},
+		{
+			name: "exdate removes an occurrence but still counts",
+			event: []string{
+				"DTSTART:20260105T100000Z", "DTEND:20260105T110000Z", "RRULE:FREQ=DAILY;COUNT=4",
+				"EXDATE:20260106T100000Z,20260107T100000Z",
+			},
+			want: []time.Time{utc(2026, 1, 5, 10), utc(2026, 1, 8, 10)},
+		},
+		{
+			name: "exdate in the event's zone",
+			event: []string{
+				"DTSTART;TZID=Europe/Paris:20260105T100000", "DTEND;TZID=Europe/Paris:20260105T110000",
+				"RRULE:FREQ=DAILY;COUNT=2", "EXDATE;TZID=Europe/Paris:20260105T100000",
+			},
+			want: []time.Time{utc(2026, 1, 6, 9)},
+		},
+	}
+
+	from := utc(2026, 1, 1, 0)
+	to := utc(2028, 1, 1, 0)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			event := append([]string{"BEGIN:VEVENT", "UID:rule@test"}, tc.event...)
+			event = append(event, "END:VEVENT")
+			busy := mustBusyTimes(t, calendar(event...), from, to, time.UTC)
+
+			if len(busy) != len(tc.want) {
+				t.Fatalf("got %d occurrences %v, want %v", len(busy), busy, tc.want)
+			}
+			for i, start := range tc.want {
+				if !busy[i].Start.Equal(start) {
+					t.Errorf("occurrence %d starts at %v, want %v", i, busy[i].Start, start)
+				}
+				if got := busy[i].End.Sub(busy[i].Start); got != time.Hour {
+					t.Errorf("occurrence %d lasts %v, want 1h", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBusyTimesRecurrenceOverride(t *testing.T) {
+	ics := calendar(`
+BEGIN:VEVENT
+UID:weekly@test
+DTSTART:20260105T100000Z
+DTEND:20260105T110000Z
+RRULE:FREQ=WEEKLY;COUNT=3
+SUMMARY:Sync
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly@test
+RECURRENCE-ID:20260112T100000Z
+DTSTART:20260113T150000Z
+DTEND:20260113T160000Z
+SUMMARY:Sync (moved)
+END:VEVENT`)
+
+	busy := mustBusyTimes(t, ics, utc(2026, 1, 1, 0), utc(2026, 2, 1, 0), time.UTC)
+
+	want := []struct {
+		start   time.Time
+		summary string
+	}{
+		{utc(2026, 1, 5, 10), "Sync"},
+		{utc(2026, 1, 13, 15), "Sync (moved)"},
+		{utc(2026, 1, 19, 10), "Sync"},
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %d", len(busy), busy, len(want))
+	}
+	for i := range want {
+		if !busy[i].Start.Equal(want[i].start) || busy[i].Summary != want[i].summary {
+			t.Errorf("occurrence %d = %v, want %v %q", i, busy[i], want[i].start, want[i].summary)
+		}
+	}
+}
+
+func TestBusyTimesRecurrenceWindow(t *testing.T) {
+	// An open-ended daily rule only expands inside the window, and COUNT is
+	// applied from the first occurrence rather than from the window
+	ics := calendar(
+		"BEGIN:VEVENT", "UID:daily@test",
+		"DTSTART:20260101T100000Z", "DTEND:20260101T110000Z", "RRULE:FREQ=DAILY",
+		"END:VEVENT",
+		"BEGIN:VEVENT", "UID:counted@test",
+		"DTSTART:20260101T120000Z", "DTEND:20260101T130000Z", "RRULE:FREQ=DAILY;COUNT=12",
+		"END:VEVENT",
+	)
+
+	busy := mustBusyTimes(t, ics, utc(2026, 1, 10, 0), utc(2026, 1, 14, 0), time.UTC)
+
+	want := []time.Time{
+		utc(2026, 1, 10, 10), utc(2026, 1, 10, 12),
+		utc(2026, 1, 11, 10), utc(2026, 1, 11, 12),
+		utc(2026, 1, 12, 10), utc(2026, 1, 12, 12),
+		utc(2026, 1, 13, 10),
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %d", len(busy), busy, len(want))
+	}
+	for i, start := range want {
+		if !busy[i].Start.Equal(start) {
+			t.Errorf("occurrence %d starts at %v, want %v", i, busy[i].Start, start)
+		}
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	for _, value := range []string{
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;COUNT=many",
+	} {
+		if _, err := parseRule(value, time.UTC); err == nil {
+			t.Errorf("parseRule(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package live fans appointment events out to connected dashboards. Events
+// are published with Postgres NOTIFY when a change commits, so every replica
+// hears about changes made through any other.
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres notification channel events are sent on
+const Channel = "live_events"
+
+// EventResync tells subscribers that events may have been missed, e.g. while
+// the listener was reconnecting, and that they should reload.
+const EventResync = "resync"
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before it is dropped
+const subscriberBuffer = 64
+
+// Event is an appointment change as published by the database.
+type Event struct {
+	Event           string    `json:"event"`
+	AppointmentID   int32     `json:"appointment_id,omitempty"`
+	HostID          int32     `json:"host_id,omitempty"`
+	VisitorID       int32     `json:"visitor_id,omitempty"`
+	HostName        string    `json:"host_name,omitempty"`
+	VisitorName     string    `json:"visitor_name,omitempty"`
+	Status          string    `json:"status,omitempty"`
+	AppointmentDate string    `json:"appointment_date,omitempty"`
+	StartTime       string    `json:"start_time,omitempty"`
+	EndTime         string    `json:"end_time,omitempty"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// Hub listens for events and passes each one to every subscriber.
+type Hub struct {
+	dataSource  string
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	stopped     bool
+}
+
+// NewHub creates a hub that listens on the database at dataSource.
+func NewHub(dataSource string) *Hub {
+	return &Hub{
+		dataSource:  dataSource,
+		subscribers: map[chan Event]struct{}{},
+	}
+}
+
+// Run listens for events until the context is cancelled, reconnecting as
+// needed. Subscribers are told to resync after every reconnect. Once Run
+// returns every subscription is closed, so open streams end and the server
+// can shut down.
+func (hub *Hub) Run(ctx context.Context) {
+	listener := pq.NewListener(hub.dataSource, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("live event listener:", err)
+		}
+	})
+	defer listener.Close()
+
+	// Listen waits for a connection, which may be a while if the database is
+	// down, so it must not hold up shutting down
+	go func() {
+		if err := listener.Listen(Channel); err != nil && ctx.Err() == nil {
+			log.Println("cannot listen for live events:", err)
+		}
+	}()
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			hub.stop()
+			return
+		case <-ping.C:
+			go listener.Ping()
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was re-established
+			if notification == nil {
+				hub.broadcast(Event{Event: EventResync, OccurredAt: time.Now()})
+				continue
+			}
+
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				log.Println("cannot decode live event:", err)
+				continue
+			}
+			hub.broadcast(event)
+		}
+	}
+}
+
+// Subscribe returns a channel of every event from now on, and a function
+// that ends the subscription. The channel is closed if the subscriber falls
+// too far behind, so it can reconnect and reload rather than miss events.
+func (hub *Hub) Subscribe() (<-chan Event, func()) {
+	events := make(chan Event, subscriberBuffer)
+
+	hub.mu.Lock()
+	if hub.stopped {
+		close(events)
+	} else {
+		hub.subscribers[events] = struct{}{}
+	}
+	hub.mu.Unlock()
+
+	return events, func() { hub.unsubscribe(events) }
+}
+
+func (hub *Hub) unsubscribe(events chan Event) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if _, ok := hub.subscribers[events]; ok {
+		delete(hub.subscribers, events)
+		close(events)
+	}
+}
+
+func (hub *Hub) broadcast(event Event) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for events := range hub.subscribers {
+		select {
+		case events <- event:
+		default:
+			delete(hub.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+func (hub *Hub) stop() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.stopped = true
+	for events := range hub.subscribers {
+		delete(hub.subscribers, events)
+		close(events)
+	}
+}
@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/DebdipWritesCode/VisitorManagementSystem/api"
 	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/live"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/storage"
 	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/worker"
 	_ "github.com/lib/pq"
 	"github.com/rs/cors"
 )
@@ -19,19 +30,70 @@ func main() {
 		log.Fatal("cannot load config:", err)
 	}
 
-	// Connect to the database
+	// Cancelled on SIGTERM or Ctrl-C, which starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	// Connect to the database; sql.Open does not, so wait until it answers
 	conn, err := sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to database:", err)
 	}
+	defer conn.Close()
+	if err := waitForDatabase(ctx, conn, config.DBConnectTimeout); err != nil {
+		log.Fatal("cannot connect to database:", err)
+	}
+
+	// Photo storage backend
+	photos, err := storage.New(config)
+	if err != nil {
+		log.Fatal("cannot create photo storage:", err)
+	}
+
+	// Everything started with run stops when ctx is cancelled, and is waited
+	// for before the process exits
+	var background sync.WaitGroup
+	run := func(runner interface{ Run(context.Context) }) {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			runner.Run(ctx)
+		}()
+	}
+
+	// Live dashboard events, relayed from Postgres NOTIFY
+	hub := live.NewHub(config.DBSource)
+	run(hub)
 
 	// Create the store and server
 	store := db.NewStore(conn)
-	server, err := api.NewServer(config, store)
+
+	// Notification templates in every locale, with admin overrides
+	catalog, err := notifications.NewCatalog(config, store)
+	if err != nil {
+		log.Fatal("cannot load message catalog:", err)
+	}
+	run(catalog)
+
+	server, err := api.NewServer(config, store, photos, hub, catalog)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
 
+	// Notification delivery channels
+	channels, err := notifications.NewChannels(config, store, catalog)
+	if err != nil {
+		log.Fatal("cannot create notification channels:", err)
+	}
+
+	// Background workers
+	run(worker.NewWaitlistWorker(config, store, catalog))
+	run(worker.NewPhotoRetentionWorker(config, store, photos))
+	run(worker.NewOverstayWorker(config, store, catalog))
+	run(worker.NewNotificationWorker(store, channels))
+	run(worker.NewReminderWorker(config, store, catalog))
+	run(worker.NewCalendarImportWorker(config, store))
+
 	// CORS middleware
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins: []string{
@@ -41,16 +103,77 @@ func main() {
 			"GET", "POST", "PUT", "DELETE", "OPTIONS",
 		},
 		AllowedHeaders: []string{
-			"Content-Type", "Authorization",
+			"Content-Type", "Authorization", "X-Kiosk-Key",
 		},
 	})
 
 	// Use CORS handler with the server router
-	handler := corsHandler.Handler(server.GetRouter())
+	httpServer := &http.Server{
+		Addr:              config.ServerAddress,
+		Handler:           corsHandler.Handler(server.GetRouter()),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       config.ServerReadTimeout,
+		WriteTimeout:      config.ServerWriteTimeout,
+		IdleTimeout:       config.ServerIdleTimeout,
+	}
 
 	// Start the HTTP server
-	err = http.ListenAndServe(config.ServerAddress, handler)
-	if err != nil {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+	log.Println("listening on", config.ServerAddress)
+
+	select {
+	case err := <-serveErr:
 		log.Fatal("cannot start server:", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Stop accepting connections and let in-flight requests finish. Live
+	// streams end as soon as the hub stops, which ctx has already told it to.
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Println("cannot drain HTTP server:", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Println("HTTP server:", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Println("shutdown complete")
+	case <-shutdownCtx.Done():
+		log.Println("background workers did not stop within SHUTDOWN_TIMEOUT")
+	}
+}
+
+// waitForDatabase pings the database until it answers, giving up after
+// timeout or when ctx is cancelled.
+func waitForDatabase(ctx context.Context, conn *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		err := conn.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Println("waiting for database:", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(2 * time.Second):
+		}
 	}
 }
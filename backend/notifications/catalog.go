@@ -0,0 +1,460 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// fallbackLocale has every message, so it ends every fallback chain
+const fallbackLocale = "en"
+
+// catalogRefreshInterval is how soon an override saved on another replica
+// takes effect here
+const catalogRefreshInterval = time.Minute
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Message is the subject and body template of one notification, or just the
+// body for a piece of text such as an email label.
+type Message struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// locale is a built-in translation with its date and time formats. Layouts
+// use Go's reference time; month and weekday names are translated.
+type locale struct {
+	Name          string             `json:"name"`
+	Date          string             `json:"date"`
+	Time          string             `json:"time"`
+	Months        [12]string         `json:"months"`
+	MonthsShort   [12]string         `json:"months_short"`
+	Weekdays      [7]string          `json:"weekdays"`
+	WeekdaysShort [7]string          `json:"weekdays_short"`
+	Messages      map[string]Message `json:"messages"`
+	// Errors translates API error messages, keyed by their English text or,
+	// for those with parameters, their format string
+	Errors map[string]string `json:"errors"`
+}
+
+// Catalog holds the notification templates for every locale: the built-in
+// ones shipped in locales/, overridden by any an admin saved in
+// message_templates.
+//
+// A locale such as "pt-br" falls back to "pt", then to DEFAULT_LOCALE and
+// finally to English, message by message, so a partial translation or
+// override is fine.
+type Catalog struct {
+	defaultLocale string
+	store         db.Querier
+	builtin       map[string]*locale
+
+	mu        sync.RWMutex
+	overrides map[string]map[string]Message
+}
+
+// NewCatalog loads the built-in locales. Overrides are loaded by Reload.
+func NewCatalog(config util.Config, store db.Querier) (*Catalog, error) {
+	defaultLocale, err := NormalizeLocale(config.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEFAULT_LOCALE: %w", err)
+	}
+
+	catalog := &Catalog{
+		defaultLocale: defaultLocale,
+		store:         store,
+		builtin:       map[string]*locale{},
+		overrides:     map[string]map[string]Message{},
+	}
+
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := localeFS.ReadFile("locales/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		var loc locale
+		if err := json.Unmarshal(data, &loc); err != nil {
+			return nil, fmt.Errorf("cannot load locale %s: %w", file.Name(), err)
+		}
+		name := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		catalog.builtin[name] = &loc
+	}
+
+	if catalog.builtin[fallbackLocale] == nil {
+		return nil, fmt.Errorf("missing the %s locale", fallbackLocale)
+	}
+	for name, loc := range catalog.builtin {
+		for key, message := range loc.Messages {
+			if err := catalog.Validate(key, message); err != nil {
+				return nil, fmt.Errorf("locale %s: %w", name, err)
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// NormalizeLocale lower-cases a BCP 47 tag such as "pt_BR" to "pt-br".
+func NormalizeLocale(tag string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if !localePattern.MatchString(normalized) {
+		return "", fmt.Errorf("%q is not a language tag such as en or pt-br", tag)
+	}
+	return normalized, nil
+}
+
+// Run reloads the overrides periodically until the context is cancelled.
+func (catalog *Catalog) Run(ctx context.Context) {
+	if err := catalog.Reload(ctx); err != nil {
+		log.Println("cannot load message template overrides:", err)
+	}
+
+	ticker := time.NewTicker(catalogRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := catalog.Reload(ctx); err != nil {
+				log.Println("cannot reload message template overrides:", err)
+			}
+		}
+	}
+}
+
+// Reload replaces the cached overrides with those in the database.
+func (catalog *Catalog) Reload(ctx context.Context) error {
+	rows, err := catalog.store.ListMessageTemplates(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := map[string]map[string]Message{}
+	for _, row := range rows {
+		if overrides[row.Locale] == nil {
+			overrides[row.Locale] = map[string]Message{}
+		}
+		overrides[row.Locale][row.Key] = Message{Subject: row.Subject, Body: row.Body}
+	}
+
+	catalog.mu.Lock()
+	catalog.overrides = overrides
+	catalog.mu.Unlock()
+	return nil
+}
+
+// Keys lists every message key, as found in the English locale.
+func (catalog *Catalog) Keys() []string {
+	keys := make([]string, 0, len(catalog.builtin[fallbackLocale].Messages))
+	for key := range catalog.builtin[fallbackLocale].Messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Locales lists the built-in locales.
+func (catalog *Catalog) Locales() []string {
+	locales := make([]string, 0, len(catalog.builtin))
+	for name := range catalog.builtin {
+		locales = append(locales, name)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// DefaultLocale is the locale of users who have not chosen one.
+func (catalog *Catalog) DefaultLocale() string {
+	return catalog.defaultLocale
+}
+
+// chain returns the locales to try for a tag, most specific first.
+func (catalog *Catalog) chain(tag string) []string {
+	var chain []string
+	add := func(tag string) {
+		for tag != "" {
+			if !contains(chain, tag) {
+				chain = append(chain, tag)
+			}
+			cut := strings.LastIndex(tag, "-")
+			if cut < 0 {
+				break
+			}
+			tag = tag[:cut]
+		}
+	}
+
+	if normalized, err := NormalizeLocale(tag); err == nil {
+		add(normalized)
+	}
+	add(catalog.defaultLocale)
+	add(fallbackLocale)
+	return chain
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the message used for a key in a locale and where it came
+// from: "override:<locale>" or "builtin:<locale>".
+func (catalog *Catalog) Lookup(key, tag string) (Message, string, bool) {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	for _, name := range catalog.chain(tag) {
+		if message, ok := catalog.overrides[name][key]; ok {
+			return message, "override:" + name, true
+		}
+		if loc := catalog.builtin[name]; loc != nil {
+			if message, ok := loc.Messages[key]; ok {
+				return message, "builtin:" + name, true
+			}
+		}
+	}
+	return Message{}, "", false
+}
+
+// Render executes a message's templates in the given locale, formatting
+// dates and times the locale's way. A template that fails to render, e.g. a
+// broken override, is skipped in favour of the next locale in the chain.
+func (catalog *Catalog) Render(key, tag string, params map[string]any) (subject, body string, err error) {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	funcs := catalog.funcs(tag)
+	for _, name := range catalog.chain(tag) {
+		candidates := []map[string]Message{catalog.overrides[name]}
+		if loc := catalog.builtin[name]; loc != nil {
+			candidates = append(candidates, loc.Messages)
+		}
+
+		for _, messages := range candidates {
+			message, ok := messages[key]
+			if !ok {
+				continue
+			}
+			subject, body, err = renderMessage(message, funcs, params)
+			if err == nil {
+				return subject, body, nil
+			}
+			log.Printf("cannot render message %s in %s: %v\n", key, name, err)
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("unknown message %q", key)
+	}
+	return "", "", err
+}
+
+// Text renders a message that has no parameters, such as an email label,
+// falling back to the key itself.
+func (catalog *Catalog) Text(key, tag string) string {
+	_, body, err := catalog.Render(key, tag, nil)
+	if err != nil {
+		return key
+	}
+	return body
+}
+
+// Error translates an API error message, falling back along the same chain
+// as messages. A message without a translation of its own, such as
+// "cannot store photo: <cause>", is translated up to its first colon; any
+// other is returned as it is, in English.
+func (catalog *Catalog) Error(message, tag string) string {
+	if translated, ok := catalog.errorText(message, tag); ok {
+		return translated
+	}
+	if head, detail, ok := strings.Cut(message, ": "); ok {
+		if translated, ok := catalog.errorText(head, tag); ok {
+			return translated + ": " + detail
+		}
+	}
+	return message
+}
+
+func (catalog *Catalog) errorText(message, tag string) (string, bool) {
+	for _, name := range catalog.chain(tag) {
+		// Errors are written in English, so there is nothing to look up
+		// once the chain reaches it
+		if name == fallbackLocale {
+			break
+		}
+		if loc := catalog.builtin[name]; loc != nil {
+			if translated, ok := loc.Errors[message]; ok {
+				return translated, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Validate checks that a message is one the server sends and that its
+// templates render with the parameters the server provides.
+func (catalog *Catalog) Validate(key string, message Message) error {
+	if _, ok := catalog.builtin[fallbackLocale].Messages[key]; !ok {
+		return fmt.Errorf("unknown message %q", key)
+	}
+	if strings.TrimSpace(message.Body) == "" {
+		return fmt.Errorf("message %q: body is required", key)
+	}
+	if _, _, err := renderMessage(message, catalog.funcs(fallbackLocale), messageParams[key]); err != nil {
+		return fmt.Errorf("message %q: %w", key, err)
+	}
+	return nil
+}
+
+func renderMessage(message Message, funcs template.FuncMap, params map[string]any) (subject, body string, err error) {
+	if subject, err = renderTemplate(message.Subject, funcs, params); err != nil {
+		return "", "", err
+	}
+	if body, err = renderTemplate(message.Body, funcs, params); err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func renderTemplate(text string, funcs template.FuncMap, params map[string]any) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formats returns the built-in locale whose date and time formats apply.
+func (catalog *Catalog) formats(tag string) *locale {
+	for _, name := range catalog.chain(tag) {
+		if loc := catalog.builtin[name]; loc != nil {
+			return loc
+		}
+	}
+	return catalog.builtin[fallbackLocale]
+}
+
+// funcs are the template functions for a locale:
+//
+//	date    a date, given as a time or "2006-01-02"
+//	time    a time of day, given as a time or "15:04"
+//	minutes a duration in whole minutes
+func (catalog *Catalog) funcs(tag string) template.FuncMap {
+	loc := catalog.formats(tag)
+	return template.FuncMap{
+		"date": func(value any) (string, error) {
+			t, err := toTime(value, "2006-01-02")
+			if err != nil {
+				return "", err
+			}
+			return loc.format(t, loc.Date), nil
+		},
+		"time": func(value any) (string, error) {
+			t, err := toTime(value, "15:04")
+			if err != nil {
+				return "", err
+			}
+			return loc.format(t, loc.Time), nil
+		},
+		"minutes": func(value any) (string, error) {
+			d, ok := value.(time.Duration)
+			if !ok {
+				return "", fmt.Errorf("minutes expects a duration, got %T", value)
+			}
+			return fmt.Sprint(int(d.Round(time.Minute).Minutes())), nil
+		},
+	}
+}
+
+func toTime(value any, layout string) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if len(v) > len(layout) {
+			v = v[:len(layout)]
+		}
+		return time.Parse(layout, v)
+	default:
+		return time.Time{}, fmt.Errorf("expected a time, got %T", value)
+	}
+}
+
+// nameTokens are the parts of a Go layout that spell out a name, longest
+// first so "January" is not read as "Jan"
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// format formats t with a Go layout, translating month and weekday names.
+func (loc *locale) format(t time.Time, layout string) string {
+	var out, chunk strings.Builder
+	flush := func() {
+		if chunk.Len() > 0 {
+			out.WriteString(t.Format(chunk.String()))
+			chunk.Reset()
+		}
+	}
+
+	for i := 0; i < len(layout); {
+		matched := ""
+		for _, token := range nameTokens {
+			if strings.HasPrefix(layout[i:], token) {
+				matched = token
+				break
+			}
+		}
+		if matched == "" {
+			chunk.WriteByte(layout[i])
+			i++
+			continue
+		}
+
+		flush()
+		switch matched {
+		case "January":
+			out.WriteString(loc.Months[t.Month()-1])
+		case "Jan":
+			out.WriteString(loc.MonthsShort[t.Month()-1])
+		case "Monday":
+			out.WriteString(loc.Weekdays[t.Weekday()])
+		case "Mon":
+			out.WriteString(loc.WeekdaysShort[t.Weekday()])
+		}
+		i += len(matched)
+	}
+	flush()
+	return out.String()
+}
@@ -0,0 +1,60 @@
+// Package notifications queues outbound messages in the notifications outbox
+// and delivers them through pluggable channels.
+package notifications
+
+import (
+	"context"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// Channel names, as stored in notifications.channel
+const (
+	ChannelSMS     = "sms"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelLog     = "log"
+	ChannelInApp   = "in_app"
+)
+
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Channel delivers a single notification. Returning an error fails the
+// attempt, and the notification is retried later.
+type Channel interface {
+	Send(ctx context.Context, notification db.Notification) error
+}
+
+// NewChannels returns every channel that can be used with the given config.
+// Email is only available when SMTP_HOST is set.
+func NewChannels(config util.Config, store db.Querier, catalog *Catalog) (map[string]Channel, error) {
+	channels := map[string]Channel{
+		ChannelSMS:     SMSChannel{},
+		ChannelWebhook: NewWebhookChannel(store),
+		ChannelLog:     LogChannel{},
+		ChannelInApp:   InAppChannel{},
+	}
+	if config.SMTPHost != "" {
+		email, err := NewEmailChannel(config, store, catalog)
+		if err != nil {
+			return nil, err
+		}
+		channels[ChannelEmail] = email
+	}
+	return channels, nil
+}
+
+// Backoff returns how long to wait before the next attempt after the given
+// number of attempts: 30s, 1m, 2m, ... capped at an hour.
+func Backoff(attempts int32) time.Duration {
+	backoff := baseBackoff
+	for i := int32(1); i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
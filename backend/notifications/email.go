@@ -0,0 +1,408 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// emailQRSize is the edge length in pixels of the QR image embedded in emails
+const emailQRSize = 256
+
+//go:embed templates
+var emailTemplateFS embed.FS
+
+// emailTemplateFuncs let the templates parse; each email is rendered with
+// the functions of its recipient's locale from emailFuncs
+var emailTemplateFuncs = map[string]any{
+	"date":  func(any) (string, error) { return "", nil },
+	"clock": func(any) (string, error) { return "", nil },
+	"t":     func(string) string { return "" },
+}
+
+// emailFuncs formats dates and times the locale's way and translates labels
+// with t, e.g. {{t "email.host"}}.
+func emailFuncs(catalog *Catalog, locale string) map[string]any {
+	funcs := catalog.funcs(locale)
+	return map[string]any{
+		"date":  funcs["date"],
+		"clock": funcs["time"],
+		"t":     func(key string) string { return catalog.Text(key, locale) },
+	}
+}
+
+// emailTemplates are the HTML and plain text bodies for each event, falling
+// back to the default template for events without their own.
+type emailTemplates struct {
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+func loadEmailTemplates() (*emailTemplates, error) {
+	templates := &emailTemplates{
+		html: map[string]*htmltemplate.Template{},
+		text: map[string]*texttemplate.Template{},
+	}
+
+	names := []string{"default"}
+	for _, event := range []string{
+		EventAppointmentBooked,
+		EventAppointmentCancelled,
+		EventAppointmentRescheduled,
+		EventAppointmentReminder,
+	} {
+		names = append(names, emailTemplateName(event))
+	}
+
+	for _, name := range names {
+		html, err := htmltemplate.New("").Funcs(emailTemplateFuncs).ParseFS(emailTemplateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		text, err := texttemplate.New("").Funcs(emailTemplateFuncs).ParseFS(emailTemplateFS, "templates/layout.txt", "templates/"+name+".txt")
+		if err != nil {
+			return nil, err
+		}
+		templates.html[name] = html
+		templates.text[name] = text
+	}
+	return templates, nil
+}
+
+// emailTemplateName maps an event such as appointment.booked to the
+// templates/appointment_booked.{html,txt} pair.
+func emailTemplateName(event string) string {
+	return strings.ReplaceAll(event, ".", "_")
+}
+
+// render executes the event's templates, or the default ones, with the
+// given functions.
+func (templates *emailTemplates) render(event string, view emailView, funcs map[string]any) (html, text []byte, err error) {
+	name := emailTemplateName(event)
+	if _, ok := templates.html[name]; !ok {
+		name = "default"
+	}
+
+	htmlTemplate, err := templates.html[name].Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	textTemplate, err := templates.text[name].Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var htmlBuf, textBuf bytes.Buffer
+	if err := htmlTemplate.Funcs(funcs).ExecuteTemplate(&htmlBuf, "layout", view); err != nil {
+		return nil, nil, err
+	}
+	if err := textTemplate.Funcs(funcs).ExecuteTemplate(&textBuf, "layout", view); err != nil {
+		return nil, nil, err
+	}
+	return htmlBuf.Bytes(), textBuf.Bytes(), nil
+}
+
+// emailView is what the email templates are executed with.
+type emailView struct {
+	Subject     string
+	Body        string
+	Appointment *db.GetAppointmentBadgeRow // Nil when the email is not about an appointment
+	ForVisitor  bool
+	QR          bool // The QR image is embedded as cid:qr
+	Data        map[string]any
+}
+
+// EmailChannel sends templated HTML and plain text email through the
+// configured SMTP server, with the visitor's QR code embedded where relevant.
+// Booking, reschedule and cancellation emails also carry an invite.ics so
+// the appointment shows up in the recipient's own calendar. Labels, dates and
+// the invite are in the locale the notification was rendered in.
+type EmailChannel struct {
+	host      string
+	addr      string
+	auth      smtp.Auth
+	from      string
+	store     db.Querier
+	catalog   *Catalog
+	templates *emailTemplates
+	organizer ical.Person
+	domain    string
+	location  *time.Location
+}
+
+// NewEmailChannel creates an email channel from the SMTP_* settings. Any
+// SMTP server works, including a local catcher such as MailHog on port 1025.
+func NewEmailChannel(config util.Config, store db.Querier, catalog *Catalog) (*EmailChannel, error) {
+	templates, err := loadEmailTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load email templates: %w", err)
+	}
+	location, err := time.LoadLocation(config.SiteTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SITE_TIMEZONE: %w", err)
+	}
+
+	channel := &EmailChannel{
+		host:      config.SMTPHost,
+		addr:      net.JoinHostPort(config.SMTPHost, fmt.Sprint(config.SMTPPort)),
+		from:      config.SMTPFrom,
+		store:     store,
+		catalog:   catalog,
+		templates: templates,
+		organizer: ical.Person{Name: "VisiTrack", Email: config.SMTPFrom},
+		domain:    ical.Domain(config.PublicURL),
+		location:  location,
+	}
+	if address, err := mail.ParseAddress(config.SMTPFrom); err == nil {
+		channel.organizer = ical.Person{Name: address.Name, Email: address.Address}
+	}
+	if config.SMTPUsername != "" {
+		channel.auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+	return channel, nil
+}
+
+func (channel *EmailChannel) Send(ctx context.Context, notification db.Notification) error {
+	view := emailView{
+		Subject: notification.Subject,
+		Body:    notification.Body,
+	}
+
+	var payload struct {
+		Locale string         `json:"locale"`
+		Data   map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(notification.Payload, &payload); err == nil {
+		view.Data = payload.Data
+	}
+
+	var qr []byte
+	var invite *calendarInvite
+	if notification.AppointmentID.Valid {
+		badge, err := channel.store.GetAppointmentBadge(ctx, notification.AppointmentID.Int32)
+		if err != nil {
+			return fmt.Errorf("cannot load appointment %d: %w", notification.AppointmentID.Int32, err)
+		}
+		view.Appointment = &badge
+		view.ForVisitor = notification.UserID.Valid && notification.UserID.Int32 == badge.VisitorID
+
+		// A cancelled appointment's code is no longer any use at the gate
+		if view.ForVisitor && badge.QrCode.Valid && badge.QrCode.String != "" && notification.Event != EventAppointmentCancelled {
+			qr, err = qrcode.Encode(badge.QrCode.String, qrcode.Medium, emailQRSize)
+			if err != nil {
+				return err
+			}
+			view.QR = true
+		}
+
+		if method := inviteMethod(notification.Event); method != "" {
+			invite = channel.invite(badge, method, payload.Locale)
+		}
+	}
+
+	html, text, err := channel.templates.render(notification.Event, view, emailFuncs(channel.catalog, payload.Locale))
+	if err != nil {
+		return fmt.Errorf("cannot render email: %w", err)
+	}
+
+	msg, err := channel.message(notification.Recipient, notification.Subject, html, text, qr, invite)
+	if err != nil {
+		return err
+	}
+	return channel.send(ctx, notification.Recipient, msg)
+}
+
+// message builds a multipart/alternative email. When a QR image is given the
+// HTML part becomes multipart/related so the image can be shown inline, and
+// when an invite is given the whole body is wrapped in multipart/mixed with
+// the invite attached.
+func (channel *EmailChannel) message(to, subject string, html, text, qr []byte, invite *calendarInvite) ([]byte, error) {
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+
+	if err := writeQuotedPrintable(alternative, "text/plain; charset=UTF-8", text); err != nil {
+		return nil, err
+	}
+
+	if qr == nil {
+		if err := writeQuotedPrintable(alternative, "text/html; charset=UTF-8", html); err != nil {
+			return nil, err
+		}
+	} else {
+		var related bytes.Buffer
+		relatedWriter := multipart.NewWriter(&related)
+		if err := writeQuotedPrintable(relatedWriter, "text/html; charset=UTF-8", html); err != nil {
+			return nil, err
+		}
+		image, err := relatedWriter.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"image/png"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<qr>"},
+			"Content-Disposition":       {`inline; filename="qr.png"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(image, qr); err != nil {
+			return nil, err
+		}
+		if err := relatedWriter.Close(); err != nil {
+			return nil, err
+		}
+
+		part, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {"multipart/related; boundary=" + relatedWriter.Boundary()},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(related.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	contentType := "multipart/alternative; boundary=" + alternative.Boundary()
+
+	if invite != nil {
+		var mixed bytes.Buffer
+		mixedWriter := multipart.NewWriter(&mixed)
+
+		part, err := mixedWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(body.Bytes()); err != nil {
+			return nil, err
+		}
+
+		attachment, err := mixedWriter.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/calendar; charset=UTF-8; method=" + invite.method},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="invite.ics"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(attachment, invite.data); err != nil {
+			return nil, err
+		}
+		if err := mixedWriter.Close(); err != nil {
+			return nil, err
+		}
+
+		body = mixed
+		contentType = "multipart/mixed; boundary=" + mixedWriter.Boundary()
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", channel.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s\r\n\r\n", contentType)
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func writeQuotedPrintable(writer *multipart.Writer, contentType string, content []byte) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write(content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 writes data base64 encoded in 76 character lines, as MIME requires.
+func writeBase64(part io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := part.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// send delivers the message over SMTP, upgrading to TLS when the server offers
+// it. Unlike smtp.SendMail it gives up when the context is done.
+func (channel *EmailChannel) send(ctx context.Context, to string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", channel.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, channel.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: channel.host}); err != nil {
+			return err
+		}
+	}
+	if channel.auth != nil {
+		if err := client.Auth(channel.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(channel.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(msg); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,241 @@
+package notifications
+
+import (
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+)
+
+// Message keys, as found in locales/*.json and message_templates.key
+const (
+	MessageAppointmentBookedHost    = "appointment.booked.host"
+	MessageAppointmentBookedVisitor = "appointment.booked.visitor"
+	MessageAppointmentCancelled     = "appointment.cancelled"
+	MessageAppointmentRescheduled   = "appointment.rescheduled"
+	MessageAppointmentReminder      = "appointment.reminder"
+	MessageVisitorCheckedIn         = "visitor.checked_in"
+	MessageWaitlistOffered          = "waitlist.offered"
+	MessageVisitOverstayHost        = "visit.overstay.host"
+	MessageVisitOverstaySecurity    = "visit.overstay.security"
+	MessageInviteSummary            = "invite.summary"
+	MessageFeedSummaryHost          = "feed.summary.host"
+	MessageFeedSummaryVisitor       = "feed.summary.visitor"
+)
+
+// messageParams are example parameters for every message key, matching what
+// the builders below pass. Overrides are test-rendered with them before they
+// are saved.
+var messageParams = func() map[string]map[string]any {
+	date := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2000, time.January, 1, 10, 30, 0, 0, time.UTC)
+	return map[string]map[string]any{
+		MessageAppointmentBookedHost:    {"Visitor": "Ada Lovelace", "Host": "Alan Turing", "Date": date, "Start": start},
+		MessageAppointmentBookedVisitor: {"Visitor": "Ada Lovelace", "Host": "Alan Turing", "Date": date, "Start": start},
+		MessageAppointmentCancelled:     {"Date": date, "Start": start, "Actor": "Alan Turing", "Reason": "Travelling"},
+		MessageAppointmentRescheduled:   {"PreviousDate": date, "PreviousStart": start, "Date": date.AddDate(0, 0, 3), "Start": start, "Actor": "Alan Turing"},
+		MessageAppointmentReminder:      {"Host": "Alan Turing", "Date": date, "Start": start, "Location": "Building 2"},
+		MessageVisitorCheckedIn:         {"Visitor": "Ada Lovelace", "Gate": "North gate", "Location": "Building 2", "At": start, "ReplyURL": "https://example.com/reply"},
+		MessageWaitlistOffered:          {"Date": date, "Start": start, "ValidFor": 30 * time.Minute, "ClaimURL": "https://example.com/claim"},
+		MessageVisitOverstayHost:        {"Visitor": "Ada Lovelace", "Location": "Building 2", "ScheduledEnd": start},
+		MessageVisitOverstaySecurity:    {"Visitor": "Ada Lovelace", "Location": "Building 2", "ScheduledEnd": start},
+		MessageInviteSummary:            {"Visitor": "Ada Lovelace", "Host": "Alan Turing"},
+		MessageFeedSummaryHost:          {"Visitor": "Ada Lovelace"},
+		MessageFeedSummaryVisitor:       {"Host": "Alan Turing"},
+	}
+}()
+
+func fullName(user db.User) string {
+	return user.FirstName + " " + user.LastName
+}
+
+// AppointmentBooked tells the host about a new booking and confirms it to the visitor.
+func AppointmentBooked(appointment db.CreateAppointmentParams, visitor, host db.User) []Notice {
+	params := map[string]any{
+		"Visitor": fullName(visitor),
+		"Host":    fullName(host),
+		"Date":    appointment.AppointmentDate,
+		"Start":   appointment.StartTime,
+	}
+	data := map[string]any{
+		"visitor_id":       visitor.ID,
+		"host_id":          host.ID,
+		"appointment_date": appointment.AppointmentDate.Format("2006-01-02"),
+		"start_time":       appointment.StartTime.Format("15:04"),
+		"end_time":         appointment.EndTime.Format("15:04"),
+	}
+
+	return []Notice{
+		{
+			Event:      EventAppointmentBooked,
+			Recipients: []Recipient{UserRecipient(host)},
+			Message:    MessageAppointmentBookedHost,
+			Params:     params,
+			Data:       data,
+		},
+		{
+			Event:      EventAppointmentBooked,
+			Recipients: []Recipient{UserRecipient(visitor)},
+			Message:    MessageAppointmentBookedVisitor,
+			Params:     params,
+			Data:       data,
+		},
+	}
+}
+
+// AppointmentCancelled tells the other party that the appointment was
+// cancelled. When an admin cancels, both the host and the visitor are told.
+func AppointmentCancelled(appointment db.Appointment, reason string, actor db.User, recipients []db.User) Notice {
+	notice := Notice{
+		Event:         EventAppointmentCancelled,
+		AppointmentID: appointment.ID,
+		Message:       MessageAppointmentCancelled,
+		Params: map[string]any{
+			"Date":   appointment.AppointmentDate,
+			"Start":  appointment.StartTime,
+			"Actor":  fullName(actor),
+			"Reason": reason,
+		},
+		Data: map[string]any{
+			"cancelled_by": actor.ID,
+			"reason":       reason,
+		},
+	}
+
+	for _, user := range recipients {
+		notice.Recipients = append(notice.Recipients, UserRecipient(user))
+	}
+	return notice
+}
+
+// AppointmentRescheduled tells the other party, or both when an admin moved
+// it, that the appointment now takes place at a different time.
+func AppointmentRescheduled(previous, appointment db.Appointment, actor db.User, recipients []db.User) Notice {
+	notice := Notice{
+		Event:         EventAppointmentRescheduled,
+		AppointmentID: appointment.ID,
+		Message:       MessageAppointmentRescheduled,
+		Params: map[string]any{
+			"PreviousDate":  previous.AppointmentDate,
+			"PreviousStart": previous.StartTime,
+			"Date":          appointment.AppointmentDate,
+			"Start":         appointment.StartTime,
+			"Actor":         fullName(actor),
+		},
+		Data: map[string]any{
+			"rescheduled_by":            actor.ID,
+			"previous_appointment_date": previous.AppointmentDate.Format("2006-01-02"),
+			"previous_start_time":       previous.StartTime.Format("15:04"),
+			"appointment_date":          appointment.AppointmentDate.Format("2006-01-02"),
+			"start_time":                appointment.StartTime.Format("15:04"),
+			"end_time":                  appointment.EndTime.Format("15:04"),
+		},
+	}
+
+	for _, user := range recipients {
+		notice.Recipients = append(notice.Recipients, UserRecipient(user))
+	}
+	return notice
+}
+
+// AppointmentReminder reminds the visitor of an upcoming appointment.
+func AppointmentReminder(appointment db.Appointment, visitor, host db.User) Notice {
+	return Notice{
+		Event:         EventAppointmentReminder,
+		AppointmentID: appointment.ID,
+		Recipients:    []Recipient{UserRecipient(visitor)},
+		Message:       MessageAppointmentReminder,
+		Params: map[string]any{
+			"Host":     fullName(host),
+			"Date":     appointment.AppointmentDate,
+			"Start":    appointment.StartTime,
+			"Location": appointment.Location.String,
+		},
+		Data: map[string]any{
+			"host_id":          host.ID,
+			"appointment_date": appointment.AppointmentDate.Format("2006-01-02"),
+			"start_time":       appointment.StartTime.Format("15:04"),
+			"end_time":         appointment.EndTime.Format("15:04"),
+		},
+	}
+}
+
+// VisitorCheckedIn tells the host that their visitor has arrived and at which
+// gate, with a link to reply "on my way" or "please wait" to the guard.
+func VisitorCheckedIn(appointment db.Appointment, visitor, host db.User, at time.Time, gate, replyURL string) Notice {
+	return Notice{
+		Event:         EventVisitorCheckedIn,
+		AppointmentID: appointment.ID,
+		Recipients:    []Recipient{UserRecipient(host)},
+		Message:       MessageVisitorCheckedIn,
+		Params: map[string]any{
+			"Visitor":  fullName(visitor),
+			"Gate":     gate,
+			"Location": appointment.Location.String,
+			"At":       at,
+			"ReplyURL": replyURL,
+		},
+		Data: map[string]any{
+			"visitor_id":    visitor.ID,
+			"gate":          gate,
+			"checked_in_at": at,
+			"reply_url":     replyURL,
+		},
+	}
+}
+
+// WaitlistOffered sends a waitlisted visitor the link to claim a freed slot.
+func WaitlistOffered(visitor db.User, slot db.FreedSlot, validFor time.Duration, claimURL string) Notice {
+	return Notice{
+		Event:      EventWaitlistOffered,
+		Recipients: []Recipient{UserRecipient(visitor)},
+		Message:    MessageWaitlistOffered,
+		Params: map[string]any{
+			"Date":     slot.AppointmentDate,
+			"Start":    slot.StartTime,
+			"ValidFor": validFor,
+			"ClaimURL": claimURL,
+		},
+		Data: map[string]any{
+			"host_id":          slot.HostID,
+			"appointment_date": slot.AppointmentDate.Format("2006-01-02"),
+			"start_time":       slot.StartTime.Format("15:04"),
+		},
+	}
+}
+
+// VisitOverstay alerts the host, and security when a number is configured,
+// that a visitor is still on site past their scheduled end. Security is
+// always told in DEFAULT_LOCALE.
+func VisitOverstay(overstay db.GetOverstayNoticeRow, securityPhone string) []Notice {
+	params := map[string]any{
+		"Visitor":      overstay.VisitorName,
+		"Location":     overstay.Location.String,
+		"ScheduledEnd": overstay.ScheduledEnd,
+	}
+	data := map[string]any{
+		"overstay_id":   overstay.ID,
+		"scheduled_end": overstay.ScheduledEnd,
+	}
+
+	host := Recipient{UserID: overstay.HostID, Phone: overstay.HostPhone, Locale: overstay.HostPreferredLocale.String}
+	notices := []Notice{{
+		Event:         EventVisitOverstay,
+		AppointmentID: overstay.AppointmentID,
+		Recipients:    []Recipient{host},
+		Message:       MessageVisitOverstayHost,
+		Params:        params,
+		Data:          data,
+	}}
+
+	if securityPhone != "" {
+		notices = append(notices, Notice{
+			Event:         EventVisitOverstay,
+			AppointmentID: overstay.AppointmentID,
+			Recipients:    []Recipient{{Phone: securityPhone}},
+			Message:       MessageVisitOverstaySecurity,
+			Params:        params,
+			Data:          data,
+		})
+	}
+	return notices
+}
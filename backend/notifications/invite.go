@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+)
+
+// inviteMethod is the iTIP method of the calendar invite attached to an
+// event's email, or "" when the email carries none.
+func inviteMethod(event string) string {
+	switch event {
+	case EventAppointmentBooked, EventAppointmentRescheduled:
+		return ical.MethodRequest
+	case EventAppointmentCancelled:
+		return ical.MethodCancel
+	default:
+		return ""
+	}
+}
+
+// calendarInvite is an invite.ics attachment.
+type calendarInvite struct {
+	method string
+	data   []byte
+}
+
+// invite renders the appointment as a VEVENT. Its UID never changes and its
+// sequence goes up with every cancellation or reschedule, so calendar clients
+// update the entry they already have instead of adding another.
+func (channel *EmailChannel) invite(badge db.GetAppointmentBadgeRow, method, locale string) *calendarInvite {
+	_, summary, err := channel.catalog.Render(MessageInviteSummary, locale, map[string]any{
+		"Visitor": badge.VisitorName,
+		"Host":    badge.HostName,
+	})
+	if err != nil {
+		summary = badge.VisitorName
+	}
+
+	event := ical.Event{
+		UID:       ical.UID(badge.ID, channel.domain),
+		Sequence:  badge.IcsSequence,
+		Start:     ical.At(badge.AppointmentDate, badge.StartTime, channel.location),
+		End:       ical.At(badge.AppointmentDate, badge.EndTime, channel.location),
+		Summary:   summary,
+		Location:  badge.Location.String,
+		Status:    ical.StatusConfirmed,
+		Organizer: &channel.organizer,
+		Attendees: []ical.Person{
+			{Name: badge.VisitorName, Email: badge.VisitorEmail.String},
+			{Name: badge.HostName, Email: badge.HostEmail.String},
+		},
+	}
+	if badge.Purpose.Valid {
+		event.Description = badge.Purpose.String
+	}
+	if method == ical.MethodCancel {
+		event.Status = ical.StatusCancelled
+	}
+
+	return &calendarInvite{
+		method: method,
+		data:   ical.Calendar{Method: method, Events: []ical.Event{event}}.Encode(),
+	}
+}
@@ -0,0 +1,17 @@
+package notifications
+
+import (
+	"context"
+	"log"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+)
+
+// LogChannel writes notifications to the server log. It is meant for local
+// development, where nothing should leave the machine.
+type LogChannel struct{}
+
+func (LogChannel) Send(ctx context.Context, notification db.Notification) error {
+	log.Printf("notification %d [%s] to %s: %s\n", notification.ID, notification.Event, notification.Recipient, notification.Body)
+	return nil
+}
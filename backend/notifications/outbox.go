@@ -0,0 +1,164 @@
+package notifications
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// Event names, as stored in notifications.event
+const (
+	EventAppointmentBooked      = "appointment.booked"
+	EventAppointmentCancelled   = "appointment.cancelled"
+	EventAppointmentRescheduled = "appointment.rescheduled"
+	EventAppointmentReminder    = "appointment.reminder"
+	EventVisitorCheckedIn       = "visitor.checked_in"
+	EventWaitlistOffered        = "waitlist.offered"
+	EventVisitOverstay          = "visit.overstay"
+)
+
+// Recipient is someone a notice is addressed to: a user, or a bare phone
+// number such as the security desk's.
+type Recipient struct {
+	UserID    int32
+	Phone     string
+	Email     string
+	Preferred string // The user's notification_channel, if any
+	Locale    string // The user's preferred_locale; empty for DEFAULT_LOCALE
+}
+
+// UserRecipient addresses a notice to a user.
+func UserRecipient(user db.User) Recipient {
+	return Recipient{
+		UserID:    user.ID,
+		Phone:     user.PhoneNumber,
+		Email:     user.Email.String,
+		Preferred: user.NotificationChannel,
+		Locale:    user.PreferredLocale.String,
+	}
+}
+
+// Notice is one message about an event, addressed to one or more recipients.
+// The message is rendered from the catalog in each recipient's locale.
+type Notice struct {
+	Event         string
+	AppointmentID int32 // Zero for a booking that does not exist yet
+	Recipients    []Recipient
+	Message       string // Catalog key, e.g. MessageAppointmentReminder
+	Params        map[string]any
+	Data          map[string]any
+}
+
+// Outbox turns notices into outbox rows for every configured channel.
+type Outbox struct {
+	channels    []string
+	maxAttempts int32
+	catalog     *Catalog
+}
+
+// NewOutbox creates an outbox that fans notices out to NOTIFICATION_CHANNELS.
+// Webhook subscribers are fed by the store as events are committed.
+func NewOutbox(config util.Config, catalog *Catalog) *Outbox {
+	outbox := &Outbox{
+		maxAttempts: config.NotificationAttempts,
+		catalog:     catalog,
+	}
+	for _, channel := range strings.Split(config.NotificationChannels, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			outbox.channels = append(outbox.channels, channel)
+		}
+	}
+	return outbox
+}
+
+// Entries returns the outbox rows for the notices, ready to be written in the
+// same transaction as the change that caused them. Each recipient gets the
+// message in their own locale, which is kept in the payload for the email
+// layout.
+func (outbox *Outbox) Entries(notices ...Notice) []db.CreateNotificationParams {
+	entries := []db.CreateNotificationParams{}
+
+	for _, notice := range notices {
+		for _, recipient := range notice.Recipients {
+			locale := recipient.Locale
+			if locale == "" {
+				locale = outbox.catalog.DefaultLocale()
+			}
+
+			subject, body, err := outbox.catalog.Render(notice.Message, locale, notice.Params)
+			if err != nil {
+				log.Printf("cannot render %s for user %d: %v\n", notice.Message, recipient.UserID, err)
+				subject, body = notice.Message, notice.Message
+			}
+
+			payload, err := json.Marshal(map[string]any{
+				"event":          notice.Event,
+				"appointment_id": notice.AppointmentID,
+				"locale":         locale,
+				"data":           notice.Data,
+			})
+			if err != nil {
+				payload = []byte("{}")
+			}
+
+			for _, channel := range outbox.recipientChannels(recipient) {
+				address, ok := recipientAddress(channel, recipient)
+				if !ok {
+					continue
+				}
+
+				entries = append(entries, db.CreateNotificationParams{
+					Event:         notice.Event,
+					AppointmentID: sql.NullInt32{Int32: notice.AppointmentID, Valid: notice.AppointmentID != 0},
+					UserID:        sql.NullInt32{Int32: recipient.UserID, Valid: recipient.UserID != 0},
+					Channel:       channel,
+					Recipient:     address,
+					Subject:       subject,
+					Body:          body,
+					Payload:       payload,
+					MaxAttempts:   outbox.maxAttempts,
+				})
+			}
+		}
+	}
+
+	return entries
+}
+
+// recipientChannels returns the channels to reach the recipient on: their
+// preferred channel when it is enabled and can reach them, otherwise every
+// configured channel. In-app delivery needs no setup, so it is always enabled.
+func (outbox *Outbox) recipientChannels(recipient Recipient) []string {
+	if _, ok := recipientAddress(recipient.Preferred, recipient); ok {
+		if recipient.Preferred == ChannelInApp || slices.Contains(outbox.channels, recipient.Preferred) {
+			return []string{recipient.Preferred}
+		}
+	}
+	return outbox.channels
+}
+
+// recipientAddress returns where a channel should deliver to the recipient,
+// or false when the channel cannot reach them.
+func recipientAddress(channel string, recipient Recipient) (string, bool) {
+	switch channel {
+	case ChannelSMS:
+		return recipient.Phone, recipient.Phone != ""
+	case ChannelEmail:
+		return recipient.Email, recipient.Email != ""
+	case ChannelInApp:
+		return fmt.Sprintf("user:%d", recipient.UserID), recipient.UserID != 0
+	case ChannelLog:
+		if recipient.UserID != 0 {
+			return fmt.Sprintf("user:%d", recipient.UserID), true
+		}
+		return recipient.Phone, recipient.Phone != ""
+	default:
+		return "", false
+	}
+}
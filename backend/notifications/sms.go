@@ -0,0 +1,15 @@
+package notifications
+
+import (
+	"context"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// SMSChannel sends the notification body as a text message through Twilio.
+type SMSChannel struct{}
+
+func (SMSChannel) Send(ctx context.Context, notification db.Notification) error {
+	return util.SendSMS(notification.Recipient, notification.Body)
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-VisiTrack-Event"
+	WebhookDeliveryHeader  = "X-VisiTrack-Delivery"
+	WebhookTimestampHeader = "X-VisiTrack-Timestamp"
+	WebhookSignatureHeader = "X-VisiTrack-Signature"
+)
+
+// WebhookChannel POSTs the event payload as JSON to a subscriber's URL. Any
+// non-2xx response counts as a failed attempt.
+//
+// Each request is signed with the subscription's secret: the signature header
+// is "sha256=" followed by the hex HMAC-SHA256 of the timestamp header, a
+// period and the raw body. Receivers should recompute it, compare in constant
+// time and reject stale timestamps to stop replays.
+type WebhookChannel struct {
+	client *http.Client
+	store  db.Querier
+}
+
+// NewWebhookChannel creates a webhook channel with a bounded request timeout.
+func NewWebhookChannel(store db.Querier) *WebhookChannel {
+	return &WebhookChannel{
+		client: &http.Client{Timeout: 10 * time.Second},
+		store:  store,
+	}
+}
+
+func (channel *WebhookChannel) Send(ctx context.Context, notification db.Notification) error {
+	if !notification.SubscriptionID.Valid {
+		return fmt.Errorf("webhook delivery has no subscription")
+	}
+	subscription, err := channel.store.GetWebhookSubscription(ctx, notification.SubscriptionID.Int32)
+	if err != nil {
+		return fmt.Errorf("cannot load webhook subscription %d: %w", notification.SubscriptionID.Int32, err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.Recipient, bytes.NewReader(notification.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "VisiTrack-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, notification.Event)
+	req.Header.Set(WebhookDeliveryHeader, fmt.Sprint(notification.ID))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(subscription.Secret, timestamp, notification.Payload))
+
+	rsp, err := channel.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, 64<<10))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", rsp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the signature header value for a delivery.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage keeps objects in a bucket of any S3-compatible service such as
+// AWS S3, MinIO or Cloudflare R2. Signed URLs are S3 presigned GET URLs.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage connects to the bucket configured through the S3_* settings.
+func NewS3Storage(config util.Config) (*S3Storage, error) {
+	if config.S3Endpoint == "" || config.S3Bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for s3 photo storage")
+	}
+
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKeyID, config.S3SecretAccessKey, ""),
+		Secure: config.S3UseSSL,
+		Region: config.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create S3 client: %w", err)
+	}
+
+	return &S3Storage{client: client, bucket: config.S3Bucket}, nil
+}
+
+func (storage *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := storage.client.PutObject(ctx, storage.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	return storage.client.RemoveObject(ctx, storage.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (storage *S3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	url, err := storage.client.PresignedGetObject(ctx, storage.bucket, key, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return url.String(), nil
+}
@@ -0,0 +1,34 @@
+// Package storage keeps binary uploads such as visitor photos outside the
+// database, behind a backend chosen by configuration.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// Storage is a blob store addressed by key.
+type Storage interface {
+	// Put stores the content under key, replacing anything already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete removes the object stored under key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that grants read access to key until ttl elapses.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New creates the storage backend selected by PHOTO_STORAGE.
+func New(config util.Config) (Storage, error) {
+	switch config.PhotoStorage {
+	case "", "local":
+		return NewLocalStorage(config.PhotoDir, config.PublicURL+"/photos", config.PhotoURLSecret)
+	case "s3":
+		return NewS3Storage(config)
+	default:
+		return nil, fmt.Errorf("unknown photo storage backend %q", config.PhotoStorage)
+	}
+}
@@ -0,0 +1,43 @@
+package token
+
+import (
+	"errors"
+	"time"
+)
+
+// Different types of error returned by the VerifyToken function
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Payload contains the payload data of the token
+type Payload struct {
+	UserID    int32     `json:"user_id"`
+	Role      string    `json:"role"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// NewPayload creates a new token payload with a specific user and duration
+func NewPayload(userID int32, role string, duration time.Duration) *Payload {
+	return &Payload{
+		UserID:    userID,
+		Role:      role,
+		IssuedAt:  time.Now(),
+		ExpiredAt: time.Now().Add(duration),
+	}
+}
+
+// Valid checks if the token payload is valid or not
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
+
+// IsAdmin reports whether the token belongs to an admin
+func (payload *Payload) IsAdmin() bool {
+	return payload.Role == "admin"
+}
@@ -8,11 +8,46 @@ import (
 
 // Config stores all configuration values read from env or .env
 type Config struct {
-	DBDriver            string        `mapstructure:"DB_DRIVER"`
-	DBSource            string        `mapstructure:"DB_SOURCE"`
-	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DBDriver              string        `mapstructure:"DB_DRIVER"`
+	DBSource              string        `mapstructure:"DB_SOURCE"`
+	ServerAddress         string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	ClientURL             string        `mapstructure:"CLIENT_URL"`
+	WaitlistOfferDuration time.Duration `mapstructure:"WAITLIST_OFFER_DURATION"`
+	LeaderboardCacheTTL   time.Duration `mapstructure:"LEADERBOARD_CACHE_TTL"`
+	PublicURL             string        `mapstructure:"PUBLIC_URL"`
+	PhotoStorage          string        `mapstructure:"PHOTO_STORAGE"`
+	PhotoDir              string        `mapstructure:"PHOTO_DIR"`
+	PhotoRetention        time.Duration `mapstructure:"PHOTO_RETENTION"`
+	PhotoURLTTL           time.Duration `mapstructure:"PHOTO_URL_TTL"`
+	PhotoURLSecret        string        `mapstructure:"PHOTO_URL_SECRET"`
+	S3Endpoint            string        `mapstructure:"S3_ENDPOINT"`
+	S3Region              string        `mapstructure:"S3_REGION"`
+	S3Bucket              string        `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID         string        `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey     string        `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	S3UseSSL              bool          `mapstructure:"S3_USE_SSL"`
+	OverstayGracePeriod   time.Duration `mapstructure:"OVERSTAY_GRACE_PERIOD"`
+	SecurityPhoneNumber   string        `mapstructure:"SECURITY_PHONE_NUMBER"`
+	NotificationChannels  string        `mapstructure:"NOTIFICATION_CHANNELS"`
+	NotificationAttempts  int32         `mapstructure:"NOTIFICATION_MAX_ATTEMPTS"`
+	SMTPHost              string        `mapstructure:"SMTP_HOST"`
+	SMTPPort              int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername          string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword          string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom              string        `mapstructure:"SMTP_FROM"`
+	ReminderLeadTime      time.Duration `mapstructure:"REMINDER_LEAD_TIME"`
+	SiteTimezone          string        `mapstructure:"SITE_TIMEZONE"`
+	CalendarSyncInterval  time.Duration `mapstructure:"CALENDAR_SYNC_INTERVAL"`
+	CalendarSyncHorizon   time.Duration `mapstructure:"CALENDAR_SYNC_HORIZON"`
+	CalendarAllowPrivate  bool          `mapstructure:"CALENDAR_ALLOW_PRIVATE_URLS"`
+	DefaultLocale         string        `mapstructure:"DEFAULT_LOCALE"`
+	ServerReadTimeout     time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ServerWriteTimeout    time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	ServerIdleTimeout     time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout       time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	DBConnectTimeout      time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
 }
 
 // LoadConfig loads env variables from file or environment
@@ -21,6 +56,44 @@ func LoadConfig(path string) (Config, error) {
 	viper.SetConfigName("app.env")
 	viper.SetConfigType("env")
 
+	// Defaults for optional settings
+	viper.SetDefault("ACCESS_TOKEN_DURATION", "24h")
+	viper.SetDefault("CLIENT_URL", "http://localhost:5173")
+	viper.SetDefault("WAITLIST_OFFER_DURATION", "30m")
+	viper.SetDefault("LEADERBOARD_CACHE_TTL", "5m")
+	viper.SetDefault("PUBLIC_URL", "http://localhost:8080")
+	viper.SetDefault("PHOTO_STORAGE", "local")
+	viper.SetDefault("PHOTO_DIR", "./data/photos")
+	viper.SetDefault("PHOTO_RETENTION", "720h")
+	viper.SetDefault("PHOTO_URL_TTL", "15m")
+	viper.SetDefault("PHOTO_URL_SECRET", "")
+	viper.SetDefault("S3_ENDPOINT", "")
+	viper.SetDefault("S3_REGION", "")
+	viper.SetDefault("S3_BUCKET", "")
+	viper.SetDefault("S3_ACCESS_KEY_ID", "")
+	viper.SetDefault("S3_SECRET_ACCESS_KEY", "")
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("OVERSTAY_GRACE_PERIOD", "15m")
+	viper.SetDefault("SECURITY_PHONE_NUMBER", "")
+	viper.SetDefault("NOTIFICATION_CHANNELS", "sms")
+	viper.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 5)
+	viper.SetDefault("SMTP_HOST", "")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("SMTP_FROM", "")
+	viper.SetDefault("REMINDER_LEAD_TIME", "24h")
+	viper.SetDefault("SITE_TIMEZONE", "UTC")
+	viper.SetDefault("CALENDAR_SYNC_INTERVAL", "1h")
+	viper.SetDefault("CALENDAR_SYNC_HORIZON", "2160h")
+	viper.SetDefault("CALENDAR_ALLOW_PRIVATE_URLS", false)
+	viper.SetDefault("DEFAULT_LOCALE", "en")
+	viper.SetDefault("SERVER_READ_TIMEOUT", "30s")
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", "60s")
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", "120s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "25s")
+	viper.SetDefault("DB_CONNECT_TIMEOUT", "60s")
+
 	viper.AutomaticEnv() // override from system env variables
 
 	err := viper.ReadInConfig()
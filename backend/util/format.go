@@ -0,0 +1,20 @@
+package util
+
+import "strings"
+
+// MaskPhone hides all but the country code and the last four digits of a
+// phone number, e.g. +919876543210 becomes +91******3210
+func MaskPhone(phone string) string {
+	const visibleSuffix = 4
+	prefix := 0
+	if strings.HasPrefix(phone, "+") && len(phone) > 3 {
+		prefix = 3
+	}
+
+	if len(phone) <= prefix+visibleSuffix {
+		return phone
+	}
+
+	hidden := len(phone) - prefix - visibleSuffix
+	return phone[:prefix] + strings.Repeat("*", hidden) + phone[len(phone)-visibleSuffix:]
+}
@@ -6,12 +6,14 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/twilio/twilio-go"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
 	verify "github.com/twilio/twilio-go/rest/verify/v2"
 )
 
 var (
 	twilioClient *twilio.RestClient
 	serviceSID   string
+	fromNumber   string
 )
 
 // InitTwilio initializes the Twilio client and loads the service SID
@@ -27,6 +29,7 @@ func InitTwilio() {
 	accountSID := os.Getenv("ACCOUNT_SID")
 	authToken := os.Getenv("AUTH_TOKEN")
 	serviceSID = os.Getenv("TWILIO_VERIFY_SERVICE_SID")
+	fromNumber = os.Getenv("TWILIO_FROM_NUMBER")
 
 	// Check if the credentials are available
 	if accountSID == "" || authToken == "" || serviceSID == "" {
@@ -83,3 +86,26 @@ func CheckPhoneVerification(phone, code string) (bool, error) {
 
 	return *resp.Status == "approved", nil
 }
+
+// SendSMS sends a plain text message
+func SendSMS(phone, body string) error {
+	if twilioClient == nil || fromNumber == "" {
+		return fmt.Errorf("Twilio messaging not configured")
+	}
+
+	params := &openapi.CreateMessageParams{}
+	params.SetTo(phone)
+	params.SetFrom(fromNumber)
+	params.SetBody(body)
+
+	resp, err := twilioClient.Api.CreateMessage(params)
+	if err != nil {
+		fmt.Println("Failed to send SMS:", err.Error())
+		return err
+	}
+
+	if resp.Sid != nil {
+		fmt.Println("SMS sent, message SID:", *resp.Sid)
+	}
+	return nil
+}
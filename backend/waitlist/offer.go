@@ -0,0 +1,48 @@
+// Package waitlist hands freed appointment slots to waitlisted visitors. It is
+// shared by the API, which frees slots on cancellation and rescheduling, and
+// by the worker, which passes on offers that were not claimed in time.
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// OfferFreedSlot offers a freed slot to the next waitlisted visitor and queues
+// their claim link in the same transaction. It is a no-op when nobody is
+// waiting or the slot has already started.
+func OfferFreedSlot(ctx context.Context, config util.Config, store db.Store, outbox *notifications.Outbox, slot db.FreedSlot) error {
+	location, err := time.LoadLocation(config.SiteTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid SITE_TIMEZONE: %w", err)
+	}
+	if ical.At(slot.AppointmentDate, slot.StartTime, location).Before(time.Now()) {
+		return nil
+	}
+
+	token, err := util.RandomToken(16)
+	if err != nil {
+		return err
+	}
+
+	claimURL := fmt.Sprintf("%s/waitlist/claim/%s", config.ClientURL, token)
+	_, err = store.OfferFreedSlotTx(ctx, db.OfferFreedSlotTxParams{
+		Slot:       slot,
+		ClaimToken: token,
+		ExpiresAt:  time.Now().Add(config.WaitlistOfferDuration),
+		Notifications: func(visitor db.User) []db.CreateNotificationParams {
+			return outbox.Entries(notifications.WaitlistOffered(visitor, slot, config.WaitlistOfferDuration, claimURL))
+		},
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,225 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/ical"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+const (
+	// calendarImportBatchSize is how many imports are synced per tick
+	calendarImportBatchSize = 20
+	// MaxICSSize is the largest calendar accepted, uploaded or fetched
+	MaxICSSize = 5 << 20
+)
+
+// icsClient fetches user-supplied calendar URLs, so it only dials public
+// addresses: the check runs on the resolved address, after DNS, and covers
+// every redirect too. Proxies are not used as they would be dialled instead.
+// privateICSClient skips the check, for sites whose calendar server is on
+// their own network (CALENDAR_ALLOW_PRIVATE_URLS).
+var (
+	icsClient        = newICSClient(refusePrivateAddress)
+	privateICSClient = newICSClient(nil)
+)
+
+func newICSClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 10 * time.Second,
+				Control: control,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("calendar URL redirected too many times")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errICSScheme
+			}
+			return nil
+		},
+	}
+}
+
+var (
+	errICSScheme         = errors.New("calendar URL must use http or https")
+	errICSAddressRefused = errors.New("calendar URL points to a private address")
+)
+
+// refusePrivateAddress is the dialer's Control hook: it refuses loopback,
+// private, link-local and unspecified addresses.
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return errICSAddressRefused
+	}
+	return nil
+}
+
+// CalendarImportWorker re-syncs every imported calendar once per
+// CALENDAR_SYNC_INTERVAL: URLs are fetched again, and uploaded files are
+// expanded again so recurring events keep covering the sync horizon.
+type CalendarImportWorker struct {
+	config   util.Config
+	store    db.Store
+	interval time.Duration
+}
+
+// NewCalendarImportWorker creates a new calendar import worker.
+func NewCalendarImportWorker(config util.Config, store db.Store) *CalendarImportWorker {
+	return &CalendarImportWorker{
+		config:   config,
+		store:    store,
+		interval: 5 * time.Minute,
+	}
+}
+
+// Run syncs due imports until the context is cancelled.
+func (worker *CalendarImportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.syncDueImports(ctx)
+		}
+	}
+}
+
+func (worker *CalendarImportWorker) syncDueImports(ctx context.Context) {
+	imports, err := worker.store.ListDueCalendarImports(ctx, db.ListDueCalendarImportsParams{
+		IntervalSeconds: int32(worker.config.CalendarSyncInterval / time.Second),
+		BatchSize:       calendarImportBatchSize,
+	})
+	if err != nil {
+		log.Println("cannot list due calendar imports:", err)
+		return
+	}
+
+	for _, calendar := range imports {
+		if err := syncCalendarImport(ctx, worker.config, worker.store, calendar); err != nil {
+			log.Printf("cannot sync calendar import %d: %v\n", calendar.ID, err)
+		}
+	}
+}
+
+// syncCalendarImport expands the import's busy events from a day ago to
+// CALENDAR_SYNC_HORIZON ahead and replaces its busy blocks with them. A
+// failure is recorded on the import, leaving the previous blocks in place.
+func syncCalendarImport(ctx context.Context, config util.Config, store db.Store, calendar db.CalendarImport) error {
+	busy, err := expandCalendarImport(ctx, config, calendar)
+	if err != nil {
+		if markErr := store.MarkCalendarImportFailed(ctx, db.MarkCalendarImportFailedParams{
+			ID:        calendar.ID,
+			LastError: sql.NullString{String: err.Error(), Valid: true},
+		}); markErr != nil {
+			log.Printf("cannot record failure of calendar import %d: %v\n", calendar.ID, markErr)
+		}
+		return err
+	}
+
+	blocks := make([]db.CreateBusyBlockParams, 0, len(busy))
+	for _, b := range busy {
+		blocks = append(blocks, db.CreateBusyBlockParams{
+			UserID:   calendar.UserID,
+			StartsAt: b.Start,
+			EndsAt:   b.End,
+			Summary:  b.Summary,
+		})
+	}
+
+	return store.SyncCalendarImportTx(ctx, db.SyncCalendarImportTxParams{
+		ImportID: calendar.ID,
+		Blocks:   blocks,
+	})
+}
+
+func expandCalendarImport(ctx context.Context, config util.Config, calendar db.CalendarImport) ([]ical.Busy, error) {
+	location, err := time.LoadLocation(config.SiteTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SITE_TIMEZONE: %w", err)
+	}
+
+	data := []byte(calendar.IcsData.String)
+	if calendar.Url.Valid {
+		if data, err = FetchICS(ctx, calendar.Url.String, config.CalendarAllowPrivate); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	return ical.BusyTimes(bytes.NewReader(data), now.Add(-24*time.Hour), now.Add(config.CalendarSyncHorizon), location)
+}
+
+// FetchICS downloads a calendar over http or https, treating webcal:// as
+// https://. Private addresses are refused unless allowPrivate is set.
+func FetchICS(ctx context.Context, rawURL string, allowPrivate bool) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(rawURL, "webcal://"); ok {
+		rawURL = "https://" + rest
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, errICSScheme
+	}
+	req.Header.Set("Accept", "text/calendar")
+	req.Header.Set("User-Agent", "VisiTrack-Calendar/1.0")
+
+	// The transport's error would name the address the host resolved to
+	client := icsClient
+	if allowPrivate {
+		client = privateICSClient
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errICSAddressRefused):
+			return nil, errICSAddressRefused
+		case errors.Is(err, errICSScheme):
+			return nil, errICSScheme
+		}
+		return nil, errors.New("calendar URL could not be reached")
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return nil, fmt.Errorf("calendar URL responded with %s", rsp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, MaxICSSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxICSSize {
+		return nil, fmt.Errorf("calendar is larger than %d MB", MaxICSSize>>20)
+	}
+	return data, nil
+}
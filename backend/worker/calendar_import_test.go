@@ -0,0 +1,231 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// calendarStore records what a sync writes. Calls to any other Store method
+// panic on the nil embedded interface.
+type calendarStore struct {
+	db.Store
+	synced []db.SyncCalendarImportTxParams
+	failed []db.MarkCalendarImportFailedParams
+}
+
+func (store *calendarStore) SyncCalendarImportTx(_ context.Context, arg db.SyncCalendarImportTxParams) error {
+	store.synced = append(store.synced, arg)
+	return nil
+}
+
+func (store *calendarStore) MarkCalendarImportFailed(_ context.Context, arg db.MarkCalendarImportFailedParams) error {
+	store.failed = append(store.failed, arg)
+	return nil
+}
+
+func testCalendarConfig(allowPrivate bool) util.Config {
+	return util.Config{
+		SiteTimezone:         "UTC",
+		CalendarSyncHorizon:  30 * 24 * time.Hour,
+		CalendarAllowPrivate: allowPrivate,
+	}
+}
+
+// tomorrowICS is a calendar with one event tomorrow, 09:00 to 10:00 UTC, and
+// a daily one that repeats three times from then, at 14:00.
+func tomorrowICS() (string, time.Time) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1).Add(9 * time.Hour)
+	stamp := func(t time.Time) string { return t.Format("20060102T150405Z") }
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:one@test",
+		"DTSTART:" + stamp(start),
+		"DTEND:" + stamp(start.Add(time.Hour)),
+		"SUMMARY:Interview",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:daily@test",
+		"DTSTART:" + stamp(start.Add(5*time.Hour)),
+		"DTEND:" + stamp(start.Add(6*time.Hour)),
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	return ics, start
+}
+
+func TestSyncCalendarImportFetchesURL(t *testing.T) {
+	ics, start := tomorrowICS()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/calendar" {
+			t.Errorf("Accept header = %q, want text/calendar", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		fmt.Fprint(w, ics)
+	}))
+	defer server.Close()
+
+	store := &calendarStore{}
+	calendar := db.CalendarImport{ID: 7, UserID: 3, Url: sql.NullString{String: server.URL + "/team.ics", Valid: true}}
+
+	if err := syncCalendarImport(context.Background(), testCalendarConfig(true), store, calendar); err != nil {
+		t.Fatalf("syncCalendarImport: %v", err)
+	}
+
+	if len(store.failed) != 0 {
+		t.Errorf("import was marked failed: %v", store.failed)
+	}
+	if len(store.synced) != 1 {
+		t.Fatalf("got %d syncs, want 1", len(store.synced))
+	}
+	sync := store.synced[0]
+	if sync.ImportID != calendar.ID {
+		t.Errorf("synced import %d, want %d", sync.ImportID, calendar.ID)
+	}
+	if len(sync.Blocks) != 4 {
+		t.Fatalf("got %d busy blocks %v, want 4", len(sync.Blocks), sync.Blocks)
+	}
+
+	first := sync.Blocks[0]
+	if first.UserID != calendar.UserID || !first.StartsAt.Equal(start) || !first.EndsAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("first block = %+v, want user %d from %v to %v", first, calendar.UserID, start, start.Add(time.Hour))
+	}
+	if first.Summary != "Interview" {
+		t.Errorf("first block summary = %q, want Interview", first.Summary)
+	}
+	for i, block := range sync.Blocks[1:] {
+		want := start.Add(5*time.Hour).AddDate(0, 0, i)
+		if !block.StartsAt.Equal(want) {
+			t.Errorf("daily block %d starts at %v, want %v", i, block.StartsAt, want)
+		}
+	}
+}
+
+func TestSyncCalendarImportUploadedFile(t *testing.T) {
+	ics, _ := tomorrowICS()
+	store := &calendarStore{}
+	calendar := db.CalendarImport{ID: 1, UserID: 2, IcsData: sql.NullString{String: ics, Valid: true}}
+
+	if err := syncCalendarImport(context.Background(), testCalendarConfig(false), store, calendar); err != nil {
+		t.Fatalf("syncCalendarImport: %v", err)
+	}
+	if len(store.synced) != 1 || len(store.synced[0].Blocks) != 4 {
+		t.Fatalf("got syncs %v, want one with 4 blocks", store.synced)
+	}
+}
+
+func TestSyncCalendarImportFailures(t *testing.T) {
+	ics, _ := tomorrowICS()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing.ics":
+			http.NotFound(w, r)
+		case "/huge.ics":
+			w.Write([]byte(strings.Repeat("X", MaxICSSize+1)))
+		case "/login":
+			fmt.Fprint(w, "<html>Sign in</html>")
+		case "/redirect":
+			http.Redirect(w, r, "ftp://example.com/team.ics", http.StatusFound)
+		default:
+			fmt.Fprint(w, ics)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		want         string
+	}{
+		{
+			name: "private address refused by default",
+			url:  server.URL + "/team.ics",
+			want: errICSAddressRefused.Error(),
+		},
+		{
+			name:         "not found",
+			url:          server.URL + "/missing.ics",
+			allowPrivate: true,
+			want:         "calendar URL responded with 404 Not Found",
+		},
+		{
+			name:         "too large",
+			url:          server.URL + "/huge.ics",
+			allowPrivate: true,
+			want:         "calendar is larger than 5 MB",
+		},
+		{
+			name:         "not a calendar",
+			url:          server.URL + "/login",
+			allowPrivate: true,
+			want:         "not a valid iCalendar file: content line 1 is malformed",
+		},
+		{
+			name:         "redirect to another scheme",
+			url:          server.URL + "/redirect",
+			allowPrivate: true,
+			want:         errICSScheme.Error(),
+		},
+		{
+			name: "unsupported scheme",
+			url:  "ftp://example.com/team.ics",
+			want: errICSScheme.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &calendarStore{}
+			calendar := db.CalendarImport{ID: 9, UserID: 2, Url: sql.NullString{String: tc.url, Valid: true}}
+
+			err := syncCalendarImport(context.Background(), testCalendarConfig(tc.allowPrivate), store, calendar)
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("got error %v, want %q", err, tc.want)
+			}
+			if len(store.synced) != 0 {
+				t.Errorf("failed import replaced its busy blocks: %v", store.synced)
+			}
+			if len(store.failed) != 1 || store.failed[0].ID != calendar.ID || store.failed[0].LastError.String != tc.want {
+				t.Errorf("got failures %v, want %q recorded on import %d", store.failed, tc.want, calendar.ID)
+			}
+		})
+	}
+}
+
+func TestRefusePrivateAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		refused bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"10.1.2.3:80", true},
+		{"172.16.0.1:80", true},
+		{"192.168.1.10:80", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"[fc00::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+	}
+
+	for _, tc := range tests {
+		err := refusePrivateAddress("tcp", tc.address, nil)
+		if refused := errors.Is(err, errICSAddressRefused); refused != tc.refused {
+			t.Errorf("refusePrivateAddress(%q) = %v, want refused %v", tc.address, err, tc.refused)
+		}
+	}
+}
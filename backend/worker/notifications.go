@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+)
+
+const (
+	// notificationBatchSize is how many notifications are claimed per tick
+	notificationBatchSize = 50
+	// notificationLease is how long a claimed notification is reserved for
+	// this worker before another one may pick it up again
+	notificationLease = 5 * time.Minute
+	// notificationSendTimeout bounds a single delivery attempt
+	notificationSendTimeout = 30 * time.Second
+)
+
+// NotificationWorker delivers queued notifications through their channels,
+// retrying failures with exponential backoff until they are dead-lettered.
+type NotificationWorker struct {
+	store    db.Store
+	channels map[string]notifications.Channel
+	interval time.Duration
+}
+
+// NewNotificationWorker creates a new notification worker.
+func NewNotificationWorker(store db.Store, channels map[string]notifications.Channel) *NotificationWorker {
+	return &NotificationWorker{
+		store:    store,
+		channels: channels,
+		interval: 5 * time.Second,
+	}
+}
+
+// Run delivers due notifications until the context is cancelled.
+func (worker *NotificationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.deliverDue(ctx)
+		}
+	}
+}
+
+func (worker *NotificationWorker) deliverDue(ctx context.Context) {
+	dead, err := worker.store.DeadLetterExpiredLeases(ctx)
+	if err != nil {
+		log.Println("cannot dead-letter expired notification leases:", err)
+	}
+	for _, notification := range dead {
+		log.Printf("notification %d dead-lettered: lease expired on its last attempt\n", notification.ID)
+	}
+
+	for {
+		due, err := worker.store.ClaimDueNotifications(ctx, db.ClaimDueNotificationsParams{
+			LeaseSeconds: int32(notificationLease / time.Second),
+			BatchSize:    notificationBatchSize,
+		})
+		if err != nil {
+			log.Println("cannot claim notifications:", err)
+			return
+		}
+
+		for _, notification := range due {
+			// On shutdown the rest of the batch is left claimed; another
+			// worker picks it up once the lease runs out
+			if ctx.Err() != nil {
+				return
+			}
+			worker.deliver(ctx, notification)
+		}
+
+		if len(due) < notificationBatchSize {
+			return
+		}
+	}
+}
+
+// deliver sends one notification and records the outcome. An attempt that
+// has started is finished even if the worker is stopped meanwhile, so a
+// message that went out is never left unmarked and sent again.
+func (worker *NotificationWorker) deliver(ctx context.Context, notification db.Notification) {
+	ctx = context.WithoutCancel(ctx)
+
+	err := worker.send(ctx, notification)
+	if err == nil {
+		if err := worker.store.MarkNotificationSent(ctx, notification.ID); err != nil {
+			log.Printf("cannot mark notification %d as sent: %v\n", notification.ID, err)
+		}
+		return
+	}
+
+	failed, markErr := worker.store.MarkNotificationFailed(ctx, db.MarkNotificationFailedParams{
+		ID:             notification.ID,
+		BackoffSeconds: int32(notifications.Backoff(notification.Attempts) / time.Second),
+		LastError:      sql.NullString{String: err.Error(), Valid: true},
+	})
+	if markErr != nil {
+		log.Printf("cannot record failure of notification %d: %v\n", notification.ID, markErr)
+		return
+	}
+	if failed.Status == "dead" {
+		log.Printf("notification %d dead-lettered after %d attempts: %v\n", notification.ID, failed.Attempts, err)
+	}
+}
+
+func (worker *NotificationWorker) send(ctx context.Context, notification db.Notification) error {
+	channel, ok := worker.channels[notification.Channel]
+	if !ok {
+		return fmt.Errorf("channel %q is not configured", notification.Channel)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+	defer cancel()
+
+	return channel.Send(ctx, notification)
+}
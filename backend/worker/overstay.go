@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// OverstayWorker flags visitors who are still checked in past their
+// appointment's end time plus OVERSTAY_GRACE_PERIOD, alerts the host and
+// security, and closes each overstay once the visitor checks out.
+type OverstayWorker struct {
+	config   util.Config
+	store    db.Store
+	outbox   *notifications.Outbox
+	interval time.Duration
+}
+
+// NewOverstayWorker creates a new overstay worker.
+func NewOverstayWorker(config util.Config, store db.Store, catalog *notifications.Catalog) *OverstayWorker {
+	return &OverstayWorker{
+		config:   config,
+		store:    store,
+		outbox:   notifications.NewOutbox(config, catalog),
+		interval: time.Minute,
+	}
+}
+
+// Run checks for overstays until the context is cancelled.
+func (worker *OverstayWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.resolveOverstays(ctx)
+			worker.flagOverstays(ctx)
+		}
+	}
+}
+
+func (worker *OverstayWorker) flagOverstays(ctx context.Context) {
+	_, err := worker.store.FlagOverstaysTx(ctx, db.FlagOverstaysTxParams{
+		GraceMinutes:  int32(worker.config.OverstayGracePeriod / time.Minute),
+		Notifications: worker.alerts,
+	})
+	if err != nil {
+		log.Println("cannot flag overstays:", err)
+	}
+}
+
+func (worker *OverstayWorker) resolveOverstays(ctx context.Context) {
+	if _, err := worker.store.ResolveOverstays(ctx); err != nil {
+		log.Println("cannot resolve overstays:", err)
+	}
+}
+
+// alerts builds the notices for the host, and security when
+// SECURITY_PHONE_NUMBER is set, that a visitor is still on site.
+func (worker *OverstayWorker) alerts(notice db.GetOverstayNoticeRow) []db.CreateNotificationParams {
+	return worker.outbox.Entries(notifications.VisitOverstay(notice, worker.config.SecurityPhoneNumber)...)
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/storage"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// photoRetentionBatchSize is how many expired photos are removed per query
+const photoRetentionBatchSize = 100
+
+// PhotoRetentionWorker deletes visitor photos once they are older than
+// PHOTO_RETENTION.
+type PhotoRetentionWorker struct {
+	config   util.Config
+	store    db.Store
+	photos   storage.Storage
+	interval time.Duration
+}
+
+// NewPhotoRetentionWorker creates a new photo retention worker.
+func NewPhotoRetentionWorker(config util.Config, store db.Store, photos storage.Storage) *PhotoRetentionWorker {
+	return &PhotoRetentionWorker{
+		config:   config,
+		store:    store,
+		photos:   photos,
+		interval: time.Hour,
+	}
+}
+
+// Run purges expired photos once at startup and then on every tick until
+// the context is cancelled. A zero retention keeps photos forever.
+func (worker *PhotoRetentionWorker) Run(ctx context.Context) {
+	if worker.config.PhotoRetention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		worker.purgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (worker *PhotoRetentionWorker) purgeExpired(ctx context.Context) {
+	cutoff := time.Now().Add(-worker.config.PhotoRetention)
+
+	for {
+		photos, err := worker.store.ListExpiredVisitorPhotos(ctx, db.ListExpiredVisitorPhotosParams{
+			CapturedBefore: cutoff,
+			BatchSize:      photoRetentionBatchSize,
+		})
+		if err != nil {
+			log.Println("cannot list expired visitor photos:", err)
+			return
+		}
+
+		deleted := 0
+		for _, photo := range photos {
+			// Remove the file first so a failure leaves the row behind to retry
+			if err := worker.photos.Delete(ctx, photo.StorageKey); err != nil {
+				log.Printf("cannot delete visitor photo %d: %v\n", photo.ID, err)
+				continue
+			}
+			if err := worker.store.DeleteVisitorPhoto(ctx, photo.ID); err != nil {
+				log.Printf("cannot delete visitor photo record %d: %v\n", photo.ID, err)
+				continue
+			}
+			deleted++
+		}
+
+		// Stop once a batch is short or nothing could be removed, so a
+		// persistent storage error cannot spin this loop
+		if len(photos) < photoRetentionBatchSize || deleted == 0 {
+			return
+		}
+	}
+}
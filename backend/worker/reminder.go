@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+)
+
+// reminderBatchSize is how many reminders are sent per tick
+const reminderBatchSize = 100
+
+// ReminderWorker reminds visitors of appointments starting within
+// REMINDER_LEAD_TIME. Each appointment is reminded once, and again after it
+// is rescheduled.
+type ReminderWorker struct {
+	config   util.Config
+	store    db.Store
+	outbox   *notifications.Outbox
+	interval time.Duration
+}
+
+// NewReminderWorker creates a new reminder worker.
+func NewReminderWorker(config util.Config, store db.Store, catalog *notifications.Catalog) *ReminderWorker {
+	return &ReminderWorker{
+		config:   config,
+		store:    store,
+		outbox:   notifications.NewOutbox(config, catalog),
+		interval: 5 * time.Minute,
+	}
+}
+
+// Run sends due reminders until the context is cancelled.
+func (worker *ReminderWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.sendDueReminders(ctx)
+		}
+	}
+}
+
+func (worker *ReminderWorker) sendDueReminders(ctx context.Context) {
+	appointments, err := worker.store.ListDueReminders(ctx, db.ListDueRemindersParams{
+		LeadSeconds: int32(worker.config.ReminderLeadTime / time.Second),
+		BatchSize:   reminderBatchSize,
+	})
+	if err != nil {
+		log.Println("cannot list due reminders:", err)
+		return
+	}
+
+	for _, appointment := range appointments {
+		if err := worker.remind(ctx, appointment); err != nil {
+			log.Printf("cannot send reminder for appointment %d: %v\n", appointment.ID, err)
+		}
+	}
+}
+
+func (worker *ReminderWorker) remind(ctx context.Context, appointment db.Appointment) error {
+	visitor, err := worker.store.GetUserByID(ctx, appointment.VisitorID)
+	if err != nil {
+		return err
+	}
+	host, err := worker.store.GetUserByID(ctx, appointment.HostID)
+	if err != nil {
+		return err
+	}
+
+	err = worker.store.SendReminderTx(ctx, db.SendReminderTxParams{
+		AppointmentID: appointment.ID,
+		Notifications: worker.outbox.Entries(notifications.AppointmentReminder(appointment, visitor, host)),
+	})
+	if err == sql.ErrNoRows {
+		// Another replica got there first
+		return nil
+	}
+	return err
+}
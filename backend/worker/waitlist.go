@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/DebdipWritesCode/VisitorManagementSystem/db/sqlc"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/notifications"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/util"
+	"github.com/DebdipWritesCode/VisitorManagementSystem/waitlist"
+)
+
+// WaitlistWorker expires unclaimed waitlist offers and passes their slots on
+// to the next visitor in line.
+type WaitlistWorker struct {
+	config   util.Config
+	store    db.Store
+	outbox   *notifications.Outbox
+	interval time.Duration
+}
+
+// NewWaitlistWorker creates a new waitlist worker.
+func NewWaitlistWorker(config util.Config, store db.Store, catalog *notifications.Catalog) *WaitlistWorker {
+	return &WaitlistWorker{
+		config:   config,
+		store:    store,
+		outbox:   notifications.NewOutbox(config, catalog),
+		interval: time.Minute,
+	}
+}
+
+// Run checks for expired offers until the context is cancelled.
+func (worker *WaitlistWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.expireOffers(ctx)
+		}
+	}
+}
+
+func (worker *WaitlistWorker) expireOffers(ctx context.Context) {
+	offers, err := worker.store.ListExpiredWaitlistOffers(ctx)
+	if err != nil {
+		log.Println("cannot list expired waitlist offers:", err)
+		return
+	}
+
+	for _, offer := range offers {
+		slot, err := worker.store.ExpireWaitlistOfferTx(ctx, offer.ID)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Printf("cannot expire waitlist offer %d: %v\n", offer.ID, err)
+			}
+			continue
+		}
+
+		if err := waitlist.OfferFreedSlot(ctx, worker.config, worker.store, worker.outbox, slot); err != nil {
+			log.Printf("cannot re-offer slot from waitlist offer %d: %v\n", offer.ID, err)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import QRPage from "./pages/QRPage";
 import Layout from "./components/Layout";
 import { getUserId } from "./utils/auth";
 import AdminPage from "./pages/AdminPage";
+import ClaimWaitlistOffer from "./pages/ClaimWaitlistOffer";
+import ReplyToCheckIn from "./pages/ReplyToCheckIn";
 
 const AppRouter = () => {
   const userId = getUserId();
@@ -29,9 +31,11 @@ const AppRouter = () => {
         <Route path="/book" element={<BookAppointment />} />
         <Route path="/appointments" element={<MyAppointments />} />
         <Route path="/availability" element={<ManageAvailability />} />
+        <Route path="/appointments/:id/reply" element={<ReplyToCheckIn />} />
       </Route>
 
       <Route path="/qr/:id" element={<QRPage />} /> {/* Optional sidebar */}
+      <Route path="/waitlist/claim/:token" element={<ClaimWaitlistOffer />} />
     </Routes>
   );
 };
@@ -61,14 +61,24 @@ const AdminPage = () => {
   };
 
   const updateStatus = async (status) => {
+    let reason;
+    if (status === "cancelled") {
+      reason = window.prompt("Why is this appointment being cancelled?");
+      if (!reason) return;
+    }
+
     setUpdatingStatus(true);
     try {
-      const payload = {
-        id: selectedAppointment.id,
-        status,
-      };
+      if (status === "cancelled") {
+        await API.post(`/appointments/${selectedAppointment.id}/cancel`, { reason });
+      } else {
+        const payload = {
+          id: selectedAppointment.id,
+          status,
+        };
   
-      await API.put("/appointments/status", payload);
+        await API.put("/appointments/status", payload);
+      }
   
       toast.success(`Marked as ${status}`);
   
@@ -80,7 +90,11 @@ const AdminPage = () => {
       setAppointments(updatedAppointments);
       setSelectedAppointment(null);
     } catch (err) {
-      toast.error("Failed to update status");
+      if (err.response?.status === 412) {
+        toast.error("The visitor still has to complete the required declarations.");
+      } else {
+        toast.error("Failed to update status");
+      }
     } finally {
       setUpdatingStatus(false);
     }
@@ -339,6 +353,39 @@ const AdminPage = () => {
                           </p>
                         </div>
 
+                        {selectedAppointment.purpose && selectedAppointment.purpose.String && (
+                          <div className="border-t border-gray-100 pt-2">
+                            <p className="font-medium text-gray-700 mb-1">Purpose</p>
+                            <p className="text-gray-900">
+                              {selectedAppointment.purpose.String}
+                              {selectedAppointment.purpose_category && selectedAppointment.purpose_category.String && (
+                                <span className="ml-2 text-xs text-gray-500">({selectedAppointment.purpose_category.String})</span>
+                              )}
+                            </p>
+                          </div>
+                        )}
+
+                        {selectedAppointment.location && selectedAppointment.location.String && (
+                          <div className="border-t border-gray-100 pt-2">
+                            <p className="font-medium text-gray-700 mb-1">Location</p>
+                            <p className="text-gray-900">{selectedAppointment.location.String}</p>
+                          </div>
+                        )}
+
+                        {selectedAppointment.host_notes && selectedAppointment.host_notes.String && (
+                          <div className="border-t border-gray-100 pt-2">
+                            <p className="font-medium text-gray-700 mb-1">Host Notes (staff only)</p>
+                            <p className="text-gray-900">{selectedAppointment.host_notes.String}</p>
+                          </div>
+                        )}
+
+                        {selectedAppointment.visitor_notes && selectedAppointment.visitor_notes.String && (
+                          <div className="border-t border-gray-100 pt-2">
+                            <p className="font-medium text-gray-700 mb-1">Visitor Notes</p>
+                            <p className="text-gray-900">{selectedAppointment.visitor_notes.String}</p>
+                          </div>
+                        )}
+
                         {selectedAppointment.qr_code && selectedAppointment.qr_code.String && (
                           <div className="border-t border-gray-100 pt-2">
                             <p className="font-medium text-gray-700 mb-1">QR Code</p>
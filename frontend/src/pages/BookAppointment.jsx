@@ -24,10 +24,12 @@ const BookAppointment = () => {
     const fetchPopularUsers = async () => {
       try {
         setLoading(true); // Set loading to true when fetching
-        const res = await API.get("/users/popular");
+        const res = await API.get("/users/leaderboard", {
+          params: { window: 30, rank_by: "hosted" },
+        });
 
         const filteredUsers = res.data.filter(
-          (user) => user.role.String !== "admin" && user.id !== parseInt(getUserId())
+          (user) => user.id !== parseInt(getUserId())
         );
 
         setUsers(filteredUsers);
@@ -154,9 +156,12 @@ const BookAppointment = () => {
                   </p>
                   <div className="flex items-center text-sm text-gray-500 mt-1">
                     <svg className="w-4 h-4 mr-1" fill="none" stroke="currentColor" viewBox="0 0 24 24" xmlns="http://www.w3.org/2000/svg">
-                      <path strokeLinecap="round" strokeLinejoin="round" strokeWidth="2" d="M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z"></path>
+                      <path strokeLinecap="round" strokeLinejoin="round" strokeWidth="2" d="M19 21V5a2 2 0 00-2-2H7a2 2 0 00-2 2v16m14 0h2m-2 0h-5m-9 0H3m2 0h5M9 7h1m-1 4h1m4-4h1m-1 4h1m-5 10v-5a1 1 0 011-1h2a1 1 0 011 1v5m-4 0h4"></path>
                     </svg>
-                    {user.phone_number}
+                    {user.department?.Valid ? user.department.String : "No department"}
+                    {user.completed_appointments !== undefined && (
+                      <span className="ml-2">· {user.completed_appointments} completed</span>
+                    )}
                   </div>
                 </div>
               </div>
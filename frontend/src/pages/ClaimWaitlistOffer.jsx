@@ -0,0 +1,83 @@
+import React, { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import API from "../utils/api";
+
+// Landing page for the link in a waitlist offer SMS. The claim token in the
+// URL is all that is needed, so it works without logging in.
+const ClaimWaitlistOffer = () => {
+  const { token } = useParams();
+  const [offer, setOffer] = useState(null);
+  const [error, setError] = useState("");
+  const [claimed, setClaimed] = useState(null);
+  const [claiming, setClaiming] = useState(false);
+
+  const formatTime = (h) => {
+    const tm = h.split("T")[1].split(":");
+    return `${tm[0]}:${tm[1]}`;
+  };
+
+  useEffect(() => {
+    API.get(`/waitlist/offers/${token}`)
+      .then((res) => setOffer(res.data))
+      .catch((err) => setError(err.response?.data?.error || "This offer could not be found."));
+  }, [token]);
+
+  const handleClaim = async () => {
+    setClaiming(true);
+    try {
+      const res = await API.post(`/waitlist/offers/${token}/claim`);
+      setClaimed(res.data.appointment);
+    } catch (err) {
+      setError(err.response?.data?.error || "Something went wrong.");
+    }
+    setClaiming(false);
+  };
+
+  const available = offer && offer.status.String === "pending" && new Date(offer.expires_at) > new Date();
+
+  return (
+    <div className="flex items-center justify-center min-h-screen bg-blue-100">
+      <div className="bg-white p-8 rounded-lg shadow-lg w-96 text-center">
+        <h2 className="text-2xl font-bold text-blue-700 mb-6">A slot opened up</h2>
+
+        {error && <p className="text-red-600 mb-4">{error}</p>}
+
+        {!error && !offer && <p className="text-gray-600">Loading offer...</p>}
+
+        {offer && !claimed && (
+          <>
+            <p className="text-gray-700 mb-2">
+              Meeting with <span className="font-semibold">{offer.host_name}</span>
+            </p>
+            <p className="text-gray-700 mb-6">
+              {offer.appointment_date.split("T")[0]} at {formatTime(offer.start_time)} - {formatTime(offer.end_time)}
+            </p>
+
+            {available ? (
+              <button
+                onClick={handleClaim}
+                disabled={claiming}
+                className="w-full bg-blue-600 text-white py-2 rounded-md hover:bg-blue-700 transition disabled:opacity-50"
+              >
+                {claiming ? "Booking..." : "Book this slot"}
+              </button>
+            ) : (
+              <p className="text-gray-600">This offer is no longer available.</p>
+            )}
+          </>
+        )}
+
+        {claimed && (
+          <>
+            <p className="text-green-700 mb-6">Your appointment is booked.</p>
+            <Link to="/appointments" className="text-blue-600 hover:underline">
+              View my appointments
+            </Link>
+          </>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default ClaimWaitlistOffer;
@@ -1,23 +1,39 @@
 import React, { useState } from "react";
 import { Link, useNavigate } from "react-router-dom";
 import API from "../utils/api";
-import { saveUserId } from "../utils/auth";
+import { saveAccessToken, saveUserId } from "../utils/auth";
 import { ToastContainer, toast } from "react-toastify";
 import "react-toastify/dist/ReactToastify.css";
 
 const Login = () => {
   const [phoneNumber, setPhoneNumber] = useState("");
+  const [otpCode, setOtpCode] = useState("");
+  const [otpSent, setOtpSent] = useState(false);
   const navigate = useNavigate();
 
+  const handleSendOTP = async (e) => {
+    e.preventDefault();
+    try {
+      await API.post("/otp/send", { phone_number: phoneNumber });
+      setOtpSent(true);
+      toast.success("We sent a code to your phone.");
+    } catch (error) {
+      console.error("OTP error:", error);
+      toast.error("Could not send the code. Please try again later.");
+    }
+  };
+
   const handleLogin = async (e) => {
     e.preventDefault();
     try {
       const res = await API.post("/auth/login", {
         phone_number: phoneNumber,
+        otp_code: otpCode,
       });
       
       toast.success("Login successful!");
       saveUserId(res.data.id);
+      saveAccessToken(res.data.access_token);
   
       if (res.data.role.String === "admin") {
         navigate("/admin");
@@ -27,7 +43,7 @@ const Login = () => {
     } catch (error) {
       console.error("Login error:", error);
       if (error.response && error.response.status === 401) {
-        toast.error("Invalid phone number or password. Please try again.");
+        toast.error("Invalid phone number or code. Please try again.");
       } else {
         toast.error("An error occurred. Please try again later.");
       }
@@ -38,7 +54,7 @@ const Login = () => {
     <div className="flex items-center justify-center min-h-screen bg-blue-100">
       <div className="bg-white p-8 rounded-lg shadow-lg w-96">
         <h2 className="text-2xl font-bold text-blue-700 mb-6 text-center">Login</h2>
-        <form onSubmit={handleLogin} className="space-y-4">
+        <form onSubmit={otpSent ? handleLogin : handleSendOTP} className="space-y-4">
           <div>
             <label className="block text-sm font-medium text-gray-700">Phone Number</label>
             <input
@@ -46,15 +62,31 @@ const Login = () => {
               value={phoneNumber}
               onChange={(e) => setPhoneNumber(e.target.value)}
               className="w-full mt-1 px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500"
+              disabled={otpSent}
               required
             />
           </div>
 
+          {otpSent && (
+            <div>
+              <label className="block text-sm font-medium text-gray-700">Verification Code</label>
+              <input
+                type="text"
+                inputMode="numeric"
+                autoComplete="one-time-code"
+                value={otpCode}
+                onChange={(e) => setOtpCode(e.target.value)}
+                className="w-full mt-1 px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500"
+                required
+              />
+            </div>
+          )}
+
           <button
             type="submit"
             className="w-full bg-blue-600 text-white py-2 rounded-md hover:bg-blue-700 transition"
           >
-            Login
+            {otpSent ? "Login" : "Send Code"}
           </button>
         </form>
 
@@ -36,8 +36,11 @@ const MyAppointments = () => {
   };
 
   const handleCancel = async (id) => {
+    const reason = window.prompt("Why are you cancelling this appointment?");
+    if (!reason) return;
+
     try {
-      await API.post(`/appointments/${id}/cancel`);
+      await API.post(`/appointments/${id}/cancel`, { reason });
       fetchAppointments();
     } catch (err) {
       console.error("Failed to cancel appointment:", err);
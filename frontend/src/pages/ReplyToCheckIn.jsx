@@ -0,0 +1,91 @@
+import React, { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import API from "../utils/api";
+
+const replyLabels = {
+  on_my_way: "On my way",
+  please_wait: "Please wait",
+};
+
+// Landing page for the link in a check-in notification. The host picks a
+// reply, which guards see on the on-site roster.
+const ReplyToCheckIn = () => {
+  const { id } = useParams();
+  const [replies, setReplies] = useState([]);
+  const [message, setMessage] = useState("");
+  const [error, setError] = useState("");
+  const [sending, setSending] = useState(false);
+  const [sent, setSent] = useState(false);
+
+  useEffect(() => {
+    API.get(`/appointments/${id}/checkin_replies`)
+      .then((res) => setReplies(res.data))
+      .catch((err) => setError(err.response?.data?.error || "This appointment could not be found."));
+  }, [id]);
+
+  const handleReply = async (reply) => {
+    setSending(true);
+    setError("");
+    try {
+      const res = await API.post(`/appointments/${id}/checkin_replies`, { reply, message });
+      setReplies([res.data, ...replies]);
+      setMessage("");
+      setSent(true);
+    } catch (err) {
+      setError(err.response?.data?.error || "Something went wrong.");
+    }
+    setSending(false);
+  };
+
+  return (
+    <div className="max-w-md mx-auto p-6">
+      <h2 className="text-2xl font-bold text-blue-700 mb-6">Your visitor has arrived</h2>
+
+      {error && <p className="text-red-600 mb-4">{error}</p>}
+      {sent && <p className="text-green-700 mb-4">Your reply has been passed on to the gate.</p>}
+
+      <label className="block text-sm font-medium text-gray-700">Message (optional)</label>
+      <input
+        type="text"
+        maxLength={200}
+        value={message}
+        onChange={(e) => setMessage(e.target.value)}
+        placeholder="5 minutes, grab a coffee"
+        className="w-full mt-1 mb-4 px-3 py-2 border border-gray-300 rounded-md"
+      />
+
+      <div className="grid grid-cols-2 gap-2 mb-6">
+        {Object.entries(replyLabels).map(([reply, label]) => (
+          <button
+            key={reply}
+            onClick={() => handleReply(reply)}
+            disabled={sending}
+            className="bg-blue-600 text-white py-2 rounded-md hover:bg-blue-700 transition disabled:opacity-50"
+          >
+            {label}
+          </button>
+        ))}
+      </div>
+
+      {replies.length > 0 && (
+        <>
+          <p className="font-medium text-gray-700 mb-2">Earlier replies</p>
+          <ul className="space-y-1 text-sm text-gray-600 mb-6">
+            {replies.map((reply) => (
+              <li key={reply.id}>
+                {new Date(reply.created_at).toLocaleTimeString()} · {replyLabels[reply.reply] || reply.reply}
+                {reply.message?.Valid && ` · ${reply.message.String}`}
+              </li>
+            ))}
+          </ul>
+        </>
+      )}
+
+      <Link to="/appointments" className="text-blue-600 hover:underline">
+        View my appointments
+      </Link>
+    </div>
+  );
+};
+
+export default ReplyToCheckIn;
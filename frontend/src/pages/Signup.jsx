@@ -8,7 +8,6 @@ const Signup = () => {
     first_name: "",
     last_name: "",
     phone_number: "",
-    role: "user", // default role
   });
   const navigate = useNavigate();
 
@@ -1,5 +1,6 @@
 // src/utils/api.js
 import axios from "axios";
+import { getAccessToken } from "./auth";
 
 const API = axios.create({
   baseURL: import.meta.env.VITE_BACKEND_URL,
@@ -8,4 +9,12 @@ const API = axios.create({
   },
 });
 
+API.interceptors.request.use((config) => {
+  const token = getAccessToken();
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
 export default API;
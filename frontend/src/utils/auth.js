@@ -6,6 +6,15 @@ export const getUserId = () => {
   return localStorage.getItem("user_id");
 };
 
+export const saveAccessToken = (token) => {
+  localStorage.setItem("access_token", token);
+};
+
+export const getAccessToken = () => {
+  return localStorage.getItem("access_token");
+};
+
 export const logoutUser = () => {
   localStorage.removeItem("user_id");
+  localStorage.removeItem("access_token");
 };